@@ -0,0 +1,60 @@
+package commands
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/vijayaxai/terraship/internal/cloud"
+
+	// Cloud adapters register themselves with the cloud package
+	_ "github.com/vijayaxai/terraship/internal/cloud/aws"
+	_ "github.com/vijayaxai/terraship/internal/cloud/azure"
+	_ "github.com/vijayaxai/terraship/internal/cloud/fake"
+	_ "github.com/vijayaxai/terraship/internal/cloud/gcp"
+)
+
+// providerFlagUsage describes the --provider flag with the registered adapters
+func providerFlagUsage() string {
+	return "Cloud provider (" + providerNames() + ") - auto-detect if not specified"
+}
+
+// providerNames lists the registered adapters
+func providerNames() string {
+	names := make([]string, 0)
+	for _, provider := range cloud.Registered() {
+		names = append(names, string(provider))
+	}
+	return strings.Join(names, ", ")
+}
+
+// endpointFlagUsage describes the --endpoint flag with the services adapters call
+func endpointFlagUsage() string {
+	return "Call a cloud service at a URL, e.g. s3=http://localhost:4566 (" + strings.Join(endpointServices(), ", ") + "; repeatable)"
+}
+
+// endpointServices lists the services whose endpoints can be overridden; a
+// service name of several providers, like kms, overrides each of them
+func endpointServices() []string {
+	seen := make(map[string]bool)
+	var services []string
+	for _, names := range cloud.EndpointServices {
+		for _, name := range names {
+			if !seen[name] {
+				seen[name] = true
+				services = append(services, name)
+			}
+		}
+	}
+	sort.Strings(services)
+	return services
+}
+
+// isEndpointService reports whether an adapter calls a service
+func isEndpointService(service string) bool {
+	for _, name := range endpointServices() {
+		if name == service {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,100 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/vijayaxai/terraship/internal/core"
+)
+
+var baselineCmd = &cobra.Command{
+	Use:   "baseline",
+	Short: "Manage baselines of accepted findings",
+	Long: `Manage baselines of accepted findings.
+
+A baseline snapshots the findings of a validation run, keyed by resource
+address and rule name. Passing it to 'terraship validate --baseline' reports
+those findings separately and only fails the run on findings that are new,
+which makes it possible to adopt Terraship on an existing codebase.`,
+}
+
+var baselineCreateCmd = &cobra.Command{
+	Use:   "create [directory]",
+	Short: "Snapshot current findings into a baseline file",
+	Long: `Run a validation and record every failed finding in a baseline file.
+
+Examples:
+  # Create a baseline for a Terraform project
+  terraship baseline create ./terraform
+
+  # Create a baseline from an exported plan
+  terraship baseline create --plan-json plan.json --file baseline.json
+
+  # Fail only on new findings from now on
+  terraship validate ./terraform --baseline .terraship-baseline.json`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runBaselineCreate,
+}
+
+var baselineFile string
+
+func init() {
+	rootCmd.AddCommand(baselineCmd)
+	baselineCmd.AddCommand(baselineCreateCmd)
+
+	baselineCreateCmd.Flags().StringVar(&baselineFile, "file", ".terraship-baseline.json", "Baseline file to write")
+	baselineCreateCmd.Flags().StringArrayVarP(&policyPaths, "policy", "p", []string{"./policies/sample-policy.yml"}, "Policy YAML file, directory or glob (repeatable)")
+	baselineCreateCmd.Flags().StringVar(&cloudProvider, "provider", "", providerFlagUsage())
+	baselineCreateCmd.Flags().StringToStringVar(&providerMap, "provider-map", nil, "Map a terraform provider source to an adapter, e.g. registry.terraform.io/acme/aws=aws (repeatable)")
+	baselineCreateCmd.Flags().StringVar(&planJSONPath, "plan-json", "", "Create the baseline from a pre-generated 'terraform show -json' plan file offline")
+	baselineCreateCmd.Flags().StringVar(&waiversPath, "waivers", "", "Waivers YAML file of accepted findings")
+	baselineCreateCmd.Flags().IntVar(&parallelism, "parallelism", core.DefaultParallelism, "Number of resources to evaluate concurrently")
+	baselineCreateCmd.Flags().BoolVarP(&verbose, "verbose", "v", false, "Verbose output")
+}
+
+func runBaselineCreate(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	workingDir := "."
+	if len(args) > 0 {
+		workingDir = args[0]
+	}
+
+	// A baseline snapshots the findings of the plan; no report is written
+	config, err := newValidatorConfig(workingDir, core.ModeValidateExisting, "json")
+	if err != nil {
+		return err
+	}
+
+	if verbose {
+		fmt.Printf("Creating Terraship baseline...\n")
+		fmt.Printf("  Working directory: %s\n", workingDir)
+		fmt.Printf("  Policy: %s\n", strings.Join(policyPaths, ", "))
+		fmt.Printf("  Baseline file: %s\n", baselineFile)
+		fmt.Println()
+	}
+
+	validator, err := core.NewValidator(config)
+	if err != nil {
+		return fmt.Errorf("failed to create validator: %w", err)
+	}
+
+	summary, err := validator.Validate(ctx)
+	if err != nil {
+		return fmt.Errorf("validation failed: %w", err)
+	}
+
+	accepted := summary.Baseline()
+	if err := accepted.Save(baselineFile); err != nil {
+		return err
+	}
+
+	colorGreen := "\033[32m"
+	colorReset := "\033[0m"
+	fmt.Printf("%s✓%s Baseline created: %s (%d findings)\n", colorGreen, colorReset, baselineFile, len(accepted.Findings))
+	fmt.Printf("  Use it with: terraship validate %s --baseline %s\n", workingDir, baselineFile)
+
+	return nil
+}
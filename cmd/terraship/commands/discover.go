@@ -0,0 +1,224 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"github.com/vijayaxai/terraship/internal/core"
+	"github.com/vijayaxai/terraship/internal/discovery"
+)
+
+var discoverCmd = &cobra.Command{
+	Use:   "discover [directory]",
+	Short: "Report cloud resources that are not managed by Terraform",
+	Long: `List the live resources of the types a Terraform configuration manages and
+report those that are not under Terraform control.
+
+The managed resources are read from a generated plan, a pre-generated plan
+(--plan-json) or a state file (--state). Every cloud adapter of their
+providers lists the resources of their types in its account, subscription or
+project, and the IDs Terraform manages are subtracted. Types an adapter cannot
+list are reported as skipped. The command exits with status 1 when unmanaged
+resources are found.
+
+Examples:
+  # Find unmanaged resources of the types a project manages
+  terraship discover ./terraform
+
+  # Discover from a state file exported from a remote backend
+  terraform show -json > state.json
+  terraship discover --state state.json
+
+  # Write an HTML report
+  terraship discover ./terraform --output html --output-file unmanaged.html
+
+  # Discover against the resources of a fixture
+  terraship discover --state terraform.tfstate --provider fake --fake-fixture fixture.yaml`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runDiscover,
+}
+
+var discoverStatePath string
+
+func init() {
+	rootCmd.AddCommand(discoverCmd)
+
+	discoverCmd.Flags().StringVar(&cloudProvider, "provider", "", providerFlagUsage())
+	discoverCmd.Flags().StringToStringVar(&providerMap, "provider-map", nil, "Map a terraform provider source to an adapter, e.g. registry.terraform.io/acme/aws=aws (repeatable)")
+	discoverCmd.Flags().StringVar(&region, "region", "", "Cloud region (AWS region, Azure location, GCP region)")
+	discoverCmd.Flags().StringVar(&planJSONPath, "plan-json", "", "Read the managed resources from a pre-generated 'terraform show -json' plan file")
+	discoverCmd.Flags().StringVar(&discoverStatePath, "state", "", "Read the managed resources from a state file ('terraform show -json' output or terraform.tfstate)")
+	discoverCmd.Flags().StringVar(&fakeFixture, "fake-fixture", "", "YAML or JSON fixture of the resources served by --provider fake")
+	discoverCmd.Flags().StringVar(&recordDir, "record", "", "Record sanitized cloud API traffic to cassettes in this directory")
+	discoverCmd.Flags().StringVar(&replayDir, "replay", "", "Replay cloud API traffic from the cassettes in this directory instead of the network")
+	discoverCmd.Flags().StringVar(&endpointURL, "endpoint-url", "", "Call every cloud service at this URL, e.g. a LocalStack endpoint")
+	discoverCmd.Flags().StringToStringVar(&endpoints, "endpoint", nil, endpointFlagUsage())
+	discoverCmd.Flags().Float64Var(&rateLimit, "rate-limit", 0, "Maximum cloud API requests per second (0 uses the provider default, -1 disables)")
+	discoverCmd.Flags().StringVarP(&outputFormat, "output", "o", "human", "Output format: human, json, html (comma-separated for multiple)")
+	discoverCmd.Flags().StringVarP(&outputFile, "output-file", "f", "", "Write output to file instead of the default report file")
+	discoverCmd.Flags().BoolVarP(&verbose, "verbose", "v", false, "Verbose output")
+}
+
+func runDiscover(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	workingDir := "."
+	if len(args) > 0 {
+		workingDir = args[0]
+	}
+
+	if _, err := os.Stat(workingDir); os.IsNotExist(err) {
+		return fmt.Errorf("directory does not exist: %s", workingDir)
+	}
+	inputs := []struct{ name, path string }{
+		{"plan JSON", planJSONPath},
+		{"state", discoverStatePath},
+	}
+	for _, input := range inputs {
+		if input.path == "" {
+			continue
+		}
+		if _, err := os.Stat(input.path); os.IsNotExist(err) {
+			return fmt.Errorf("%s file does not exist: %s", input.name, input.path)
+		}
+	}
+
+	formats := strings.Split(outputFormat, ",")
+	for i, f := range formats {
+		formats[i] = strings.TrimSpace(f)
+		if formats[i] != "human" && formats[i] != "json" && formats[i] != "html" {
+			return fmt.Errorf("invalid output format: %s (must be human, json, or html)", formats[i])
+		}
+	}
+
+	cloudConfig, err := newCloudConfig()
+	if err != nil {
+		return err
+	}
+
+	if verbose {
+		fmt.Printf("Starting Terraship discovery...\n")
+		fmt.Printf("  Working directory: %s\n", workingDir)
+		if planJSONPath != "" {
+			fmt.Printf("  Plan JSON: %s\n", planJSONPath)
+		}
+		if discoverStatePath != "" {
+			fmt.Printf("  State file: %s\n", discoverStatePath)
+		}
+		if cloudProvider != "" {
+			fmt.Printf("  Cloud provider: %s\n", cloudProvider)
+		}
+		fmt.Println()
+	}
+
+	discoverer, err := core.NewDiscoverer(core.ValidatorConfig{
+		Mode:          core.ModeValidateExisting,
+		WorkingDir:    workingDir,
+		CloudProvider: cloudProvider,
+		Verbose:       verbose,
+		PlanJSONPath:  planJSONPath,
+		StatePath:     discoverStatePath,
+		RateLimit:     rateLimit,
+
+		ProviderMappings: providerMap,
+		Cloud:            cloudConfig,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create discoverer: %w", err)
+	}
+
+	report, err := discoverer.Discover(ctx)
+	if err != nil {
+		return fmt.Errorf("discovery failed: %w", err)
+	}
+
+	for _, f := range formats {
+		if err := writeDiscoveryReport(f, report); err != nil {
+			fmt.Printf("❌ Error generating %s report: %v\n", f, err)
+		}
+	}
+
+	if report.UnmanagedCount > 0 {
+		os.Exit(1)
+	}
+	return nil
+}
+
+// writeDiscoveryReport prints or writes the discovery report in a format
+func writeDiscoveryReport(format string, report *discovery.Report) error {
+	var data []byte
+	var defaultFile string
+	switch format {
+	case "human":
+		printDiscoveryReport(report)
+		return nil
+	case "json":
+		jsonBytes, err := report.ToJSON()
+		if err != nil {
+			return err
+		}
+		data, defaultFile = jsonBytes, "terraship-discovery.json"
+	case "html":
+		html, err := report.ToHTML()
+		if err != nil {
+			return err
+		}
+		data, defaultFile = []byte(html), "discovery.html"
+	default:
+		return fmt.Errorf("unknown format: %s", format)
+	}
+
+	outFile := outputFile
+	if outFile == "" {
+		outFile = defaultFile
+	}
+	if err := os.WriteFile(outFile, data, 0644); err != nil {
+		return err
+	}
+
+	colorGreen := "\033[32m"
+	colorReset := "\033[0m"
+	fmt.Printf("%s✓%s %s report generated: %s\n", colorGreen, colorReset, strings.ToUpper(format), outFile)
+	return nil
+}
+
+// printDiscoveryReport prints the unmanaged resources and the listed types
+func printDiscoveryReport(report *discovery.Report) {
+	fmt.Println("\n" + strings.Repeat("=", 63))
+	fmt.Println("                 TERRASHIP UNMANAGED RESOURCES")
+	fmt.Println(strings.Repeat("=", 63))
+	fmt.Println()
+	fmt.Printf("SUMMARY:\n")
+	fmt.Printf("  Live Resources:     %d\n", report.LiveResources)
+	fmt.Printf("  ✗ Unmanaged:        %d\n", report.UnmanagedCount)
+	if report.SkippedTypes > 0 {
+		fmt.Printf("  ⚠ Types Not Listed: %d\n", report.SkippedTypes)
+	}
+	fmt.Println()
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	if len(report.Unmanaged) > 0 {
+		fmt.Fprintln(w, "TYPE\tID\tSCOPE")
+		for _, resource := range report.Unmanaged {
+			fmt.Fprintf(w, "%s\t%s\t%s\n", resource.Type, resource.ID, resource.Scope)
+		}
+		w.Flush()
+		fmt.Println()
+	}
+
+	for _, result := range report.Types {
+		if result.Error != "" {
+			fmt.Printf("⚠  %s (%s) not listed: %s\n", result.Type, result.Scope, result.Error)
+		}
+	}
+
+	if report.UnmanagedCount > 0 {
+		fmt.Println("✗ UNMANAGED RESOURCES FOUND")
+	} else {
+		fmt.Println("✓ ALL LISTED RESOURCES ARE MANAGED")
+	}
+}
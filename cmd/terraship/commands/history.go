@@ -0,0 +1,229 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/vijayaxai/terraship/internal/core"
+	"github.com/vijayaxai/terraship/internal/history"
+	"github.com/vijayaxai/terraship/internal/output"
+)
+
+// historyChartRuns is the number of recent runs shown in the HTML trend chart
+const historyChartRuns = 30
+
+var historyCmd = &cobra.Command{
+	Use:   "history [directory]",
+	Short: "Show the validation run history",
+	Long: `Show the recorded validation runs of a directory and policy set.
+
+Every 'terraship validate' and 'terraship scan-state' run is recorded in a
+local history store (.terraship/history by default), keyed by the validated
+directory (or state file) and the policies used. Pass the same directory and
+--policy flags as the validation to see its runs.
+
+Examples:
+  # Show recent runs of a Terraform project
+  terraship history ./terraform
+
+  # Runs validated with a custom policy
+  terraship history ./terraform --policy ./my-policy.yml
+
+  # List every recorded directory and policy set
+  terraship history --all
+
+  # Export the history for a dashboard
+  terraship history ./terraform --output json`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runHistory,
+}
+
+var (
+	historyDir   string
+	noHistory    bool
+	historyLimit int
+	historyAll   bool
+)
+
+func init() {
+	rootCmd.AddCommand(historyCmd)
+
+	historyCmd.Flags().StringArrayVarP(&policyPaths, "policy", "p", []string{"./policies/sample-policy.yml"}, "Policy YAML file, directory or glob (repeatable)")
+	historyCmd.Flags().StringVar(&historyDir, "history-dir", history.DefaultDir, "Directory of the run history store")
+	historyCmd.Flags().IntVarP(&historyLimit, "limit", "n", 20, "Number of most recent runs to show (0 for all)")
+	historyCmd.Flags().BoolVar(&historyAll, "all", false, "List every recorded directory and policy set")
+	historyCmd.Flags().StringVarP(&outputFormat, "output", "o", "human", "Output format: human or json")
+}
+
+func runHistory(cmd *cobra.Command, args []string) error {
+	target := "."
+	if len(args) > 0 {
+		target = args[0]
+	}
+
+	if outputFormat != "human" && outputFormat != "json" {
+		return fmt.Errorf("invalid output format: %s (must be human or json)", outputFormat)
+	}
+
+	store := history.Open(historyDir)
+
+	if historyAll {
+		series, err := store.Series()
+		if err != nil {
+			return err
+		}
+		if outputFormat == "json" {
+			return printJSON(series)
+		}
+		printHistorySeries(series)
+		return nil
+	}
+
+	records, err := store.List(history.Key(target, policyPaths), historyLimit)
+	if err != nil {
+		return err
+	}
+	if outputFormat == "json" {
+		return printJSON(records)
+	}
+
+	if len(records) == 0 {
+		fmt.Printf("No runs recorded for %s with policy %s in %s\n", target, strings.Join(policyPaths, ", "), historyDir)
+		return nil
+	}
+	printHistoryRecords(records)
+	return nil
+}
+
+// recordRun appends the run to the history store unless --no-history is set
+// and returns the recent runs of its series for the HTML trend chart.
+// Warnings go to stderr so they do not mix with reports written to stdout.
+func recordRun(summary *core.Summary, target string) []output.HistoryPoint {
+	store := history.Open(historyDir)
+	record := newHistoryRecord(summary, target)
+
+	if !noHistory {
+		if err := store.Append(record); err != nil {
+			fmt.Fprintf(os.Stderr, "⚠  Warning: Could not record run history: %v\n", err)
+		}
+	}
+
+	if !includeHistory {
+		return nil
+	}
+
+	records, err := store.List(record.Key(), historyChartRuns)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "⚠  Warning: Could not load run history: %v\n", err)
+		return nil
+	}
+	if noHistory {
+		records = append(records, record)
+	}
+
+	points := make([]output.HistoryPoint, 0, len(records))
+	for _, r := range records {
+		points = append(points, output.HistoryPoint{
+			Label:             r.Timestamp.Local().Format("Jan 2 15:04"),
+			Passed:            r.PassedResources,
+			Failed:            r.FailedResources,
+			Warnings:          r.WarningResources,
+			CompliancePercent: r.CompliancePercent,
+		})
+	}
+	return points
+}
+
+// newHistoryRecord summarizes a validation run for the history store
+func newHistoryRecord(summary *core.Summary, target string) history.Record {
+	record := history.Record{
+		Timestamp:        time.Now().UTC(),
+		Target:           target,
+		Policies:         policyPaths,
+		TotalResources:   summary.TotalResources,
+		PassedResources:  summary.PassedResources,
+		FailedResources:  summary.FailedResources,
+		WarningResources: summary.WarningResources,
+		ErrorResources:   summary.ErrorResources,
+		WaivedFindings:   summary.WaivedFindings,
+		BaselineFindings: summary.BaselineFindings,
+	}
+
+	for _, report := range summary.Reports {
+		for _, result := range report.RuleResults {
+			if !result.Passed && !result.Waived && !result.Baselined {
+				record.Findings++
+			}
+		}
+	}
+
+	if summary.TotalResources > 0 {
+		record.CompliancePercent = (float64(summary.PassedResources) / float64(summary.TotalResources)) * 100
+	}
+
+	return record
+}
+
+// printHistoryRecords prints the runs of a series with the compliance trend
+// between consecutive runs
+func printHistoryRecords(records []history.Record) {
+	last := records[len(records)-1]
+	fmt.Printf("HISTORY: %s\n", last.Target)
+	fmt.Printf("  Policy: %s\n\n", strings.Join(last.Policies, ", "))
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(w, "TIMESTAMP\tRESOURCES\tPASSED\tFAILED\tWARNINGS\tFINDINGS\tCOMPLIANCE\tTREND\t")
+	for i, record := range records {
+		trend := "-"
+		if i > 0 {
+			trend = fmt.Sprintf("%+.1f%%", record.CompliancePercent-records[i-1].CompliancePercent)
+		}
+		fmt.Fprintf(w, "%s\t%d\t%d\t%d\t%d\t%d\t%.1f%%\t%s\t\n",
+			record.Timestamp.Local().Format("2006-01-02 15:04:05"),
+			record.TotalResources,
+			record.PassedResources,
+			record.FailedResources,
+			record.WarningResources,
+			record.Findings,
+			record.CompliancePercent,
+			trend,
+		)
+	}
+	w.Flush()
+}
+
+// printHistorySeries prints one line per recorded directory and policy set
+func printHistorySeries(series []history.Series) {
+	if len(series) == 0 {
+		fmt.Printf("No runs recorded in %s\n", historyDir)
+		return
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "LAST RUN\tRUNS\tCOMPLIANCE\tTARGET\tPOLICY")
+	for _, s := range series {
+		fmt.Fprintf(w, "%s\t%d\t%.1f%%\t%s\t%s\n",
+			s.Last.Timestamp.Local().Format("2006-01-02 15:04:05"),
+			s.Runs,
+			s.Last.CompliancePercent,
+			s.Target,
+			strings.Join(s.Policies, ", "),
+		)
+	}
+	w.Flush()
+}
+
+// printJSON writes v to stdout as indented JSON
+func printJSON(v interface{}) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(data))
+	return nil
+}
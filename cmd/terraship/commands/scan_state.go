@@ -0,0 +1,148 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/vijayaxai/terraship/internal/core"
+	"github.com/vijayaxai/terraship/internal/history"
+	"github.com/vijayaxai/terraship/internal/rules"
+)
+
+var scanStateCmd = &cobra.Command{
+	Use:   "scan-state [state-file]",
+	Short: "Validate deployed resources recorded in a Terraform state file",
+	Long: `Validate the resources recorded in a Terraform state file against policy rules.
+
+This command audits what is actually deployed without generating a plan.
+It accepts either the output of 'terraform show -json' for a state or a raw
+terraform.tfstate file (state format version 4). No terraform binary or
+cloud credentials are required; with --provider, deployed resources are also
+checked for drift through that cloud adapter.
+
+Examples:
+  # Scan the local state file
+  terraship scan-state terraform.tfstate
+
+  # Scan exported state from a remote backend
+  terraform show -json > state.json
+  terraship scan-state state.json --policy ./my-policy.yml
+
+  # Scan every workspace
+  for ws in terraform.tfstate.d/*/terraform.tfstate; do
+    terraship scan-state "$ws" --output json --output-file "$(dirname "$ws").json"
+  done
+
+  # Check the state for drift against the resources of a fixture
+  terraship scan-state terraform.tfstate --provider fake --fake-fixture fixture.yaml
+
+  # Check the state for drift against a local fake-gcs-server
+  terraship scan-state terraform.tfstate --provider gcp --endpoint storage=http://localhost:4443`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runScanState,
+}
+
+func init() {
+	rootCmd.AddCommand(scanStateCmd)
+
+	scanStateCmd.Flags().StringArrayVarP(&policyPaths, "policy", "p", []string{"./policies/sample-policy.yml"}, "Policy YAML file, directory or glob (repeatable)")
+	scanStateCmd.Flags().StringVar(&cloudProvider, "provider", "", "Cloud provider to check deployed resources for drift ("+providerNames()+")")
+	scanStateCmd.Flags().StringVar(&region, "region", "", "Cloud region (AWS region, Azure location, GCP region)")
+	scanStateCmd.Flags().StringVar(&fakeFixture, "fake-fixture", "", "YAML or JSON fixture of the resources served by --provider fake")
+	scanStateCmd.Flags().StringVar(&recordDir, "record", "", "Record sanitized cloud API traffic to cassettes in this directory")
+	scanStateCmd.Flags().StringVar(&replayDir, "replay", "", "Replay cloud API traffic from the cassettes in this directory instead of the network")
+	scanStateCmd.Flags().StringVar(&endpointURL, "endpoint-url", "", "Call every cloud service at this URL, e.g. a LocalStack endpoint")
+	scanStateCmd.Flags().StringToStringVar(&endpoints, "endpoint", nil, endpointFlagUsage())
+	scanStateCmd.Flags().StringVarP(&outputFormat, "output", "o", "human", "Output format: human, json, html, pdf, sarif (comma-separated for multiple)")
+	scanStateCmd.Flags().StringVarP(&outputFile, "output-file", "f", "", "Write output to file instead of stdout")
+	scanStateCmd.Flags().BoolVarP(&verbose, "verbose", "v", false, "Verbose output")
+	scanStateCmd.Flags().BoolVar(&includeHistory, "include-history", false, "Include validation history in report")
+	scanStateCmd.Flags().StringVar(&compareWith, "compare", "", "Compare with previous validation results (JSON file)")
+	scanStateCmd.Flags().StringVar(&waiversPath, "waivers", "", "Waivers YAML file of accepted findings")
+	scanStateCmd.Flags().StringVar(&baselinePath, "baseline", "", "Baseline file; only findings not in the baseline fail the run")
+	scanStateCmd.Flags().IntVar(&parallelism, "parallelism", core.DefaultParallelism, "Number of resources to evaluate concurrently")
+	scanStateCmd.Flags().StringVar(&historyDir, "history-dir", history.DefaultDir, "Directory of the run history store every run is recorded in, relative to the current directory")
+	scanStateCmd.Flags().BoolVar(&noHistory, "no-history", false, "Don't record this run in the history store (--history-dir)")
+}
+
+func runScanState(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	statePath := "terraform.tfstate"
+	if len(args) > 0 {
+		statePath = args[0]
+	}
+
+	// Validate state file exists
+	if _, err := os.Stat(statePath); os.IsNotExist(err) {
+		return fmt.Errorf("state file does not exist: %s", statePath)
+	}
+
+	// Validate policy files exist
+	if _, err := rules.ResolvePolicyPaths(policyPaths); err != nil {
+		return fmt.Errorf("%w\n\n"+
+			"Create a policy file by running:\n"+
+			"  terraship init", err)
+	}
+
+	cloudConfig, err := newCloudConfig()
+	if err != nil {
+		return err
+	}
+
+	// Validate output formats
+	formats, err := parseOutputFormats(outputFormat)
+	if err != nil {
+		return err
+	}
+
+	if verbose {
+		fmt.Printf("Starting Terraship state scan...\n")
+		fmt.Printf("  State file: %s\n", statePath)
+		fmt.Printf("  Policy: %s\n", strings.Join(policyPaths, ", "))
+		fmt.Printf("  Output format: %s\n", outputFormat)
+		if cloudProvider != "" {
+			fmt.Printf("  Cloud provider: %s\n", cloudProvider)
+		}
+		fmt.Println()
+	}
+
+	config := core.ValidatorConfig{
+		Mode:          core.ModeValidateExisting,
+		WorkingDir:    filepath.Dir(statePath),
+		PolicyPaths:   policyPaths,
+		CloudProvider: cloudProvider,
+		OutputFormat:  outputFormat,
+		OutputFile:    outputFile,
+		Verbose:       verbose,
+		StatePath:     statePath,
+		WaiversPath:   waiversPath,
+		BaselinePath:  baselinePath,
+		Parallelism:   parallelism,
+
+		Cloud: cloudConfig,
+	}
+
+	validator, err := core.NewValidator(config)
+	if err != nil {
+		return fmt.Errorf("failed to create validator: %w", err)
+	}
+
+	summary, err := validator.Validate(ctx)
+	if err != nil {
+		return fmt.Errorf("state scan failed: %w", err)
+	}
+
+	writeReports(summary, formats, recordRun(summary, statePath))
+
+	// Exit with error code if validation failed
+	if summary.FailedResources > 0 || summary.ErrorResources > 0 {
+		os.Exit(1)
+	}
+
+	return nil
+}
@@ -9,8 +9,11 @@ import (
 	"time"
 
 	"github.com/spf13/cobra"
+	"github.com/vijayaxai/terraship/internal/cloud"
 	"github.com/vijayaxai/terraship/internal/core"
+	"github.com/vijayaxai/terraship/internal/history"
 	"github.com/vijayaxai/terraship/internal/output"
+	"github.com/vijayaxai/terraship/internal/rules"
 )
 
 var validateCmd = &cobra.Command{
@@ -24,6 +27,10 @@ the specified policy file. It supports two modes:
   validate-existing: Validate existing infrastructure without making changes
   ephemeral-sandbox: Create temporary infrastructure, validate, and destroy
 
+In ephemeral-sandbox mode the created resources are read back from the cloud
+after apply, checked for drift from their planned values and evaluated
+against the policy before they are destroyed.
+
 Examples:
   # Validate with default policy
   terraship validate ./terraform
@@ -40,6 +47,9 @@ Examples:
   # Compare with previous run
   terraship validate ./terraform --compare previous-report.json
 
+  # Chart the recorded run history in the HTML report
+  terraship validate ./terraform --output html --include-history
+
   # Validate existing infrastructure
   terraship validate ./terraform --mode validate-existing
 
@@ -49,14 +59,38 @@ Examples:
   # Use custom policy and output format
   terraship validate ./terraform --policy ./my-policy.yml --output json
 
+  # Combine policy directories, globs and files
+  terraship validate ./terraform --policy ./policies/org --policy './team/*.yml'
+
   # Manually specify cloud provider
-  terraship validate ./terraform --provider aws --region us-west-2`,
+  terraship validate ./terraform --provider aws --region us-west-2
+
+  # Handle a fork of the AWS provider with the AWS adapter
+  terraship validate ./terraform --provider-map registry.terraform.io/acme/aws=aws
+
+  # Validate an exported plan offline (no terraform binary or credentials)
+  terraform show -json plan.tfplan > plan.json
+  terraship validate --plan-json plan.json
+
+  # Detect drift offline against the resources of a fixture
+  terraship validate --plan-json plan.json --provider fake --fake-fixture fixture.yaml
+
+  # Record cloud API traffic, then replay it without network access
+  terraship validate ./terraform --record ./cassettes
+  terraship validate --plan-json plan.json --provider aws --replay ./cassettes
+
+  # Detect drift against LocalStack instead of AWS
+  terraship validate ./terraform --endpoint-url http://localhost:4566
+
+  # Only fail on findings that are not in the baseline
+  terraship baseline create ./terraform
+  terraship validate ./terraform --baseline .terraship-baseline.json`,
 	Args: cobra.MaximumNArgs(1),
 	RunE: runValidate,
 }
 
 var (
-	policyPath     string
+	policyPaths    []string
 	cloudProvider  string
 	region         string
 	mode           string
@@ -67,14 +101,31 @@ var (
 	htmlAdvanced   bool
 	includeHistory bool
 	compareWith    string
+	planJSONPath   string
+	waiversPath    string
+	baselinePath   string
+	parallelism    int
+	rateLimit      float64
+	providerMap    map[string]string
+	fakeFixture    string
+	recordDir      string
+	replayDir      string
+	endpointURL    string
+	endpoints      map[string]string
 )
 
 func init() {
 	rootCmd.AddCommand(validateCmd)
 
-	validateCmd.Flags().StringVarP(&policyPath, "policy", "p", "./policies/sample-policy.yml", "Path to policy YAML file")
-	validateCmd.Flags().StringVar(&cloudProvider, "provider", "", "Cloud provider (aws, azure, gcp) - auto-detect if not specified")
+	validateCmd.Flags().StringArrayVarP(&policyPaths, "policy", "p", []string{"./policies/sample-policy.yml"}, "Policy YAML file, directory or glob (repeatable)")
+	validateCmd.Flags().StringVar(&cloudProvider, "provider", "", providerFlagUsage())
+	validateCmd.Flags().StringToStringVar(&providerMap, "provider-map", nil, "Map a terraform provider source to an adapter, e.g. registry.terraform.io/acme/aws=aws (repeatable)")
 	validateCmd.Flags().StringVar(&region, "region", "", "Cloud region (AWS region, Azure location, GCP region)")
+	validateCmd.Flags().StringVar(&fakeFixture, "fake-fixture", "", "YAML or JSON fixture of the resources served by --provider fake")
+	validateCmd.Flags().StringVar(&recordDir, "record", "", "Record sanitized cloud API traffic to cassettes in this directory")
+	validateCmd.Flags().StringVar(&replayDir, "replay", "", "Replay cloud API traffic from the cassettes in this directory instead of the network")
+	validateCmd.Flags().StringVar(&endpointURL, "endpoint-url", "", "Call every cloud service at this URL, e.g. a LocalStack endpoint")
+	validateCmd.Flags().StringToStringVar(&endpoints, "endpoint", nil, endpointFlagUsage())
 	validateCmd.Flags().StringVarP(&mode, "mode", "m", "validate-existing", "Validation mode: validate-existing or ephemeral-sandbox")
 	validateCmd.Flags().StringVarP(&outputFormat, "output", "o", "human", "Output format: human, json, html, pdf, sarif (comma-separated for multiple)")
 	validateCmd.Flags().StringVarP(&outputFile, "output-file", "f", "", "Write output to file instead of stdout")
@@ -83,6 +134,13 @@ func init() {
 	validateCmd.Flags().BoolVar(&htmlAdvanced, "html-advanced", false, "Use advanced HTML features (dark mode, charts, search)")
 	validateCmd.Flags().BoolVar(&includeHistory, "include-history", false, "Include validation history in report")
 	validateCmd.Flags().StringVar(&compareWith, "compare", "", "Compare with previous validation results (JSON file)")
+	validateCmd.Flags().StringVar(&planJSONPath, "plan-json", "", "Validate a pre-generated 'terraform show -json' plan file offline")
+	validateCmd.Flags().StringVar(&waiversPath, "waivers", "", "Waivers YAML file of accepted findings")
+	validateCmd.Flags().StringVar(&baselinePath, "baseline", "", "Baseline file; only findings not in the baseline fail the run")
+	validateCmd.Flags().IntVar(&parallelism, "parallelism", core.DefaultParallelism, "Number of resources to evaluate concurrently")
+	validateCmd.Flags().Float64Var(&rateLimit, "rate-limit", 0, "Maximum cloud API requests per second (0 uses the provider default, -1 disables)")
+	validateCmd.Flags().StringVar(&historyDir, "history-dir", history.DefaultDir, "Directory of the run history store every run is recorded in, relative to the current directory")
+	validateCmd.Flags().BoolVar(&noHistory, "no-history", false, "Don't record this run in the history store (--history-dir)")
 }
 
 func runValidate(cmd *cobra.Command, args []string) error {
@@ -94,60 +152,35 @@ func runValidate(cmd *cobra.Command, args []string) error {
 		workingDir = args[0]
 	}
 
-	// Validate working directory exists
-	if _, err := os.Stat(workingDir); os.IsNotExist(err) {
-		return fmt.Errorf("directory does not exist: %s", workingDir)
-	}
-
-	// Validate policy file exists
-	if _, err := os.Stat(policyPath); os.IsNotExist(err) {
-		return fmt.Errorf("policy file does not exist: %s\n\n"+
-			"Create a policy file by running:\n"+
-			"  terraship init\n\n"+
-			"Or specify a custom policy with:\n"+
-			"  terraship validate . --policy ./your-policy.yml\n\n"+
-			"For help with policy files, see:\n"+
-			"  terraship validate --help", policyPath)
-	}
-
-	// Validate mode
-	if mode != "validate-existing" && mode != "ephemeral-sandbox" {
-		return fmt.Errorf("invalid mode: %s (must be validate-existing or ephemeral-sandbox)", mode)
+	config, err := newValidatorConfig(workingDir, core.ValidationMode(mode), outputFormat)
+	if err != nil {
+		return err
 	}
 
 	// Validate output formats
-	formats := strings.Split(outputFormat, ",")
-	for _, f := range formats {
-		f = strings.TrimSpace(f)
-		if f != "human" && f != "json" && f != "html" && f != "pdf" && f != "sarif" {
-			return fmt.Errorf("invalid output format: %s (must be human, json, html, pdf, or sarif)", f)
-		}
+	formats, err := parseOutputFormats(outputFormat)
+	if err != nil {
+		return err
 	}
 
 	if verbose {
 		fmt.Printf("Starting Terraship validation...\n")
 		fmt.Printf("  Working directory: %s\n", workingDir)
-		fmt.Printf("  Policy file: %s\n", policyPath)
+		fmt.Printf("  Policy: %s\n", strings.Join(policyPaths, ", "))
 		fmt.Printf("  Mode: %s\n", mode)
 		fmt.Printf("  Output format: %s\n", outputFormat)
 		if cloudProvider != "" {
 			fmt.Printf("  Cloud provider: %s\n", cloudProvider)
 		}
+		if planJSONPath != "" {
+			fmt.Printf("  Plan JSON: %s (offline)\n", planJSONPath)
+		}
+		if baselinePath != "" {
+			fmt.Printf("  Baseline: %s\n", baselinePath)
+		}
 		fmt.Println()
 	}
 
-	// Create validator config
-	config := core.ValidatorConfig{
-		Mode:          core.ValidationMode(mode),
-		WorkingDir:    workingDir,
-		PolicyPath:    policyPath,
-		CloudProvider: cloudProvider,
-		OutputFormat:  outputFormat,
-		OutputFile:    outputFile,
-		NoDestroy:     noDestroy,
-		Verbose:       verbose,
-	}
-
 	// Create validator
 	validator, err := core.NewValidator(config)
 	if err != nil {
@@ -160,8 +193,124 @@ func runValidate(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("validation failed: %w", err)
 	}
 
+	writeReports(summary, formats, recordRun(summary, workingDir))
+
+	// Exit with error code if validation failed; baseline findings do not count
+	if summary.FailedResources > 0 || summary.ErrorResources > 0 {
+		os.Exit(1)
+	}
+
+	return nil
+}
+
+// newValidatorConfig checks the validation flags and builds the validator
+// configuration for workingDir in the given mode and output format, which
+// the calling command passes as it may not register those flags
+func newValidatorConfig(workingDir string, validationMode core.ValidationMode, format string) (core.ValidatorConfig, error) {
+	// Validate working directory exists
+	if _, err := os.Stat(workingDir); os.IsNotExist(err) {
+		return core.ValidatorConfig{}, fmt.Errorf("directory does not exist: %s", workingDir)
+	}
+
+	// Validate policy files exist
+	if _, err := rules.ResolvePolicyPaths(policyPaths); err != nil {
+		return core.ValidatorConfig{}, fmt.Errorf("%w\n\n"+
+			"Create a policy file by running:\n"+
+			"  terraship init\n\n"+
+			"Or specify a custom policy with:\n"+
+			"  terraship validate . --policy ./your-policy.yml\n\n"+
+			"For help with policy files, see:\n"+
+			"  terraship validate --help", err)
+	}
+
+	// Validate optional input files exist
+	inputs := []struct{ name, path string }{
+		{"plan JSON", planJSONPath},
+		{"waivers", waiversPath},
+		{"baseline", baselinePath},
+	}
+	for _, input := range inputs {
+		if input.path == "" {
+			continue
+		}
+		if _, err := os.Stat(input.path); os.IsNotExist(err) {
+			return core.ValidatorConfig{}, fmt.Errorf("%s file does not exist: %s", input.name, input.path)
+		}
+	}
+
+	if parallelism < 1 {
+		return core.ValidatorConfig{}, fmt.Errorf("invalid parallelism: %d (must be at least 1)", parallelism)
+	}
+
+	// Validate mode
+	if validationMode != core.ModeValidateExisting && validationMode != core.ModeEphemeralSandbox {
+		return core.ValidatorConfig{}, fmt.Errorf("invalid mode: %s (must be validate-existing or ephemeral-sandbox)", validationMode)
+	}
+
+	cloudConfig, err := newCloudConfig()
+	if err != nil {
+		return core.ValidatorConfig{}, err
+	}
+
+	return core.ValidatorConfig{
+		Mode:          validationMode,
+		WorkingDir:    workingDir,
+		PolicyPaths:   policyPaths,
+		CloudProvider: cloudProvider,
+		OutputFormat:  format,
+		OutputFile:    outputFile,
+		NoDestroy:     noDestroy,
+		Verbose:       verbose,
+		PlanJSONPath:  planJSONPath,
+		WaiversPath:   waiversPath,
+		BaselinePath:  baselinePath,
+		Parallelism:   parallelism,
+		RateLimit:     rateLimit,
+
+		ProviderMappings: providerMap,
+		Cloud:            cloudConfig,
+	}, nil
+}
+
+// newCloudConfig builds the base cloud adapter configuration from the flags
+func newCloudConfig() (cloud.CloudConfig, error) {
+	if fakeFixture != "" {
+		if _, err := os.Stat(fakeFixture); os.IsNotExist(err) {
+			return cloud.CloudConfig{}, fmt.Errorf("fake fixture file does not exist: %s", fakeFixture)
+		}
+	}
+
+	if recordDir != "" && replayDir != "" {
+		return cloud.CloudConfig{}, fmt.Errorf("--record and --replay cannot be used together")
+	}
+	if replayDir != "" {
+		if _, err := os.Stat(replayDir); os.IsNotExist(err) {
+			return cloud.CloudConfig{}, fmt.Errorf("replay directory does not exist: %s", replayDir)
+		}
+	}
+
+	for service := range endpoints {
+		if !isEndpointService(service) {
+			return cloud.CloudConfig{}, fmt.Errorf("unknown service in --endpoint: %s (supported: %s)", service, strings.Join(endpointServices(), ", "))
+		}
+	}
+
+	return cloud.CloudConfig{
+		Region:      region,
+		FakeFixture: fakeFixture,
+		RecordDir:   recordDir,
+		ReplayDir:   replayDir,
+		EndpointURL: endpointURL,
+		Endpoints:   endpoints,
+	}, nil
+}
+
+// writeReports renders the validation summary in every requested output
+// format; runs are the recent validation runs for the HTML trend chart
+func writeReports(summary *core.Summary, formats []string, runs []output.HistoryPoint) {
 	// Convert summary to ValidationResult for report generation
 	validationResult := convertSummaryToValidationResult(summary)
+	validationResult.History = runs
 
 	// Load previous results if comparing
 	var previousResults *output.ValidationResult
@@ -171,6 +320,7 @@ func runValidate(cmd *cobra.Command, args []string) error {
 			fmt.Printf("⚠  Warning: Could not load previous results: %v\n", err)
 		} else {
 			previousResults = prevResults
+			validationResult.Comparison = output.Compare(validationResult, previousResults)
 		}
 	}
 
@@ -187,13 +337,18 @@ func runValidate(cmd *cobra.Command, args []string) error {
 	if outputFile == "" || strings.Contains(outputFormat, "human") {
 		printValidationSummary(validationResult)
 	}
+}
 
-	// Exit with error code if validation failed
-	if summary.FailedResources > 0 || summary.ErrorResources > 0 {
-		os.Exit(1)
+// parseOutputFormats splits and validates a comma-separated list of output formats
+func parseOutputFormats(value string) ([]string, error) {
+	formats := strings.Split(value, ",")
+	for _, f := range formats {
+		f = strings.TrimSpace(f)
+		if f != "human" && f != "json" && f != "html" && f != "pdf" && f != "sarif" {
+			return nil, fmt.Errorf("invalid output format: %s (must be human, json, html, pdf, or sarif)", f)
+		}
 	}
-
-	return nil
+	return formats, nil
 }
 
 // convertSummaryToValidationResult converts core validator summary to ValidationResult
@@ -203,6 +358,8 @@ func convertSummaryToValidationResult(summary *core.Summary) *output.ValidationR
 		PassedResources:  summary.PassedResources,
 		FailedResources:  summary.FailedResources,
 		WarningResources: summary.WarningResources,
+		WaivedFindings:   summary.WaivedFindings,
+		BaselineFindings: summary.BaselineFindings,
 		Timestamp:        time.Now().Format("2006-01-02 15:04:05"),
 		Resources:        convertResourcesToOutputFormat(summary),
 	}
@@ -229,15 +386,33 @@ func convertResourcesToOutputFormat(summary *core.Summary) []output.Resource {
 				Name:        result.RuleName,
 				Message:     result.Message,
 				Severity:    result.Severity,
-				Failed:      !result.Passed,
+				Failed:      !result.Passed && !result.Waived && !result.Baselined,
 				Warning:     result.Severity == "warning" && result.Passed,
 				Remediation: result.Remediation,
 				Details:     result.Details,
+
+				Waived:        result.Waived,
+				WaiverReason:  result.WaiverReason,
+				WaiverExpires: result.WaiverExpires,
+				Baselined:     result.Baselined,
+				Live:          result.Source == cloud.SourceLive,
 			}
 			
 			resource.Checks = append(resource.Checks, check)
 		}
 		
+		// Add drift as a warning check
+		if report.DriftStatus != nil && report.DriftStatus.DriftDetected {
+			check := output.Check{
+				Name:     "Drift Detected",
+				Message:  "Deployed resource differs from the Terraform configuration",
+				Severity: "warning",
+				Failed:   true,
+				Details:  report.DriftStatus.DriftDetails,
+			}
+			resource.Checks = append(resource.Checks, check)
+		}
+
 		// Add errors as checks if any
 		for _, errMsg := range report.Errors {
 			check := output.Check{
@@ -389,8 +564,18 @@ func printHumanReport(results *output.ValidationResult) {
 	fmt.Printf("  ✓ Passed:           %d\n", results.PassedResources)
 	fmt.Printf("  ✗ Failed:           %d\n", results.FailedResources)
 	fmt.Printf("  ⚠ Warnings:         %d\n", results.WarningResources)
+	if results.WaivedFindings > 0 {
+		fmt.Printf("  ⊘ Waived Findings:  %d\n", results.WaivedFindings)
+	}
+	if results.BaselineFindings > 0 {
+		fmt.Printf("  ≡ Baseline Findings: %d\n", results.BaselineFindings)
+	}
 	fmt.Println()
 
+	if results.Comparison != nil {
+		printComparison(results.Comparison)
+	}
+
 	if results.FailedResources > 0 {
 		fmt.Println("✗ VALIDATION FAILED")
 	} else {
@@ -398,6 +583,24 @@ func printHumanReport(results *output.ValidationResult) {
 	}
 }
 
+// printComparison prints the finding deltas against a previous run
+func printComparison(comparison *output.ComparisonReport) {
+	fmt.Printf("CHANGES SINCE %s:\n", comparison.PreviousTimestamp)
+	fmt.Printf("  + New:              %d\n", comparison.NewFindings)
+	fmt.Printf("  ✓ Fixed:            %d\n", comparison.FixedFindings)
+	fmt.Printf("  ✗ Regressed:        %d\n", comparison.RegressedFindings)
+	fmt.Printf("  = Unchanged:        %d\n", comparison.UnchangedFindings)
+	fmt.Printf("  Compliance trend:   %+.1f%%\n", comparison.TrendPercent)
+
+	for _, change := range comparison.ChangedResources {
+		fmt.Printf("  %s [%s]\n", change.ResourceName, change.Status)
+		for _, detail := range change.DetailChanges {
+			fmt.Printf("    - %s\n", detail)
+		}
+	}
+	fmt.Println()
+}
+
 // printValidationSummary prints summary statistics
 func printValidationSummary(results *output.ValidationResult) {
 	compliance := 0.0
@@ -409,9 +612,8 @@ func printValidationSummary(results *output.ValidationResult) {
 	fmt.Printf("⏱  Validation completed: %s\n\n", results.Timestamp)
 }
 
-// loadValidationResultsFromFile loads previous validation results
+// loadValidationResultsFromFile loads previous validation results written by
+// the json output format
 func loadValidationResultsFromFile(filePath string) (*output.ValidationResult, error) {
-	// This would load and parse the previous results file
-	// For now, placeholder
-	return nil, fmt.Errorf("loading previous results not yet implemented")
+	return output.LoadJSONFile(filePath)
 }
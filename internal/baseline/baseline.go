@@ -0,0 +1,124 @@
+// Package baseline records accepted findings so that only new findings fail
+// a validation run.
+package baseline
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/vijayaxai/terraship/internal/cloud"
+)
+
+// currentVersion is the baseline file format version
+const currentVersion = 1
+
+// Finding is a failed rule result recorded in a baseline, keyed by resource
+// address and rule name
+type Finding struct {
+	Resource string `json:"resource"`
+	Rule     string `json:"rule"`
+	Severity string `json:"severity,omitempty"`
+	Message  string `json:"message,omitempty"`
+}
+
+// Baseline is a snapshot of the findings of a validation run
+type Baseline struct {
+	Version   int       `json:"version"`
+	CreatedAt time.Time `json:"created_at"`
+	Findings  []Finding `json:"findings"`
+
+	index map[string]bool
+}
+
+// New creates a baseline from findings, sorted by resource and rule and
+// de-duplicated
+func New(findings []Finding) *Baseline {
+	b := &Baseline{
+		Version:   currentVersion,
+		CreatedAt: time.Now().UTC(),
+		Findings:  make([]Finding, 0, len(findings)),
+	}
+
+	seen := make(map[string]bool, len(findings))
+	for _, finding := range findings {
+		k := key(finding.Resource, finding.Rule)
+		if seen[k] {
+			continue
+		}
+		seen[k] = true
+		b.Findings = append(b.Findings, finding)
+	}
+
+	sort.Slice(b.Findings, func(i, j int) bool {
+		if b.Findings[i].Resource != b.Findings[j].Resource {
+			return b.Findings[i].Resource < b.Findings[j].Resource
+		}
+		return b.Findings[i].Rule < b.Findings[j].Rule
+	})
+
+	b.buildIndex()
+	return b
+}
+
+// Load reads a baseline file
+func Load(path string) (*Baseline, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read baseline file: %w", err)
+	}
+
+	var b Baseline
+	if err := json.Unmarshal(data, &b); err != nil {
+		return nil, fmt.Errorf("failed to parse baseline file: %w", err)
+	}
+	if b.Version != currentVersion {
+		return nil, fmt.Errorf("unsupported baseline version %d (expected %d)", b.Version, currentVersion)
+	}
+
+	b.buildIndex()
+	return &b, nil
+}
+
+// Save writes the baseline as indented JSON
+func (b *Baseline) Save(path string) error {
+	data, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode baseline: %w", err)
+	}
+
+	if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write baseline file: %w", err)
+	}
+	return nil
+}
+
+// Contains reports whether the baseline has a finding for the rule on the
+// resource
+func (b *Baseline) Contains(resource, rule string) bool {
+	return b != nil && b.index[key(resource, rule)]
+}
+
+// Apply marks a failed result on the resource as a baseline finding when the
+// baseline already contains it
+func (b *Baseline) Apply(resource string, result *cloud.ValidationResult) {
+	if result.Passed || result.Waived {
+		return
+	}
+	if b.Contains(resource, result.RuleName) {
+		result.Baselined = true
+	}
+}
+
+func (b *Baseline) buildIndex() {
+	b.index = make(map[string]bool, len(b.Findings))
+	for _, finding := range b.Findings {
+		b.index[key(finding.Resource, finding.Rule)] = true
+	}
+}
+
+func key(resource, rule string) string {
+	return resource + "\x00" + rule
+}
@@ -0,0 +1,56 @@
+package baseline
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vijayaxai/terraship/internal/cloud"
+)
+
+func TestBaseline_SaveLoad(t *testing.T) {
+	b := New([]Finding{
+		{Resource: "aws_s3_bucket.logs", Rule: "enable-versioning", Severity: "error"},
+		{Resource: "aws_instance.web", Rule: "required-tags", Severity: "error"},
+		{Resource: "aws_s3_bucket.logs", Rule: "enable-versioning", Severity: "error"},
+	})
+	require.Len(t, b.Findings, 2)
+	assert.Equal(t, "aws_instance.web", b.Findings[0].Resource)
+
+	path := filepath.Join(t.TempDir(), "baseline.json")
+	require.NoError(t, b.Save(path))
+
+	loaded, err := Load(path)
+	require.NoError(t, err)
+	assert.Equal(t, b.Findings, loaded.Findings)
+	assert.True(t, loaded.Contains("aws_s3_bucket.logs", "enable-versioning"))
+	assert.False(t, loaded.Contains("aws_s3_bucket.logs", "required-tags"))
+
+	require.NoError(t, os.WriteFile(path, []byte(`{"version": 2, "findings": []}`), 0644))
+	_, err = Load(path)
+	assert.ErrorContains(t, err, "unsupported baseline version")
+}
+
+func TestBaseline_Apply(t *testing.T) {
+	b := New([]Finding{{Resource: "aws_s3_bucket.logs", Rule: "enable-versioning"}})
+
+	existing := cloud.ValidationResult{RuleName: "enable-versioning", Severity: "error"}
+	b.Apply("aws_s3_bucket.logs", &existing)
+	assert.True(t, existing.Baselined)
+
+	newFinding := cloud.ValidationResult{RuleName: "enable-versioning", Severity: "error"}
+	b.Apply("aws_s3_bucket.data", &newFinding)
+	assert.False(t, newFinding.Baselined)
+
+	passed := cloud.ValidationResult{RuleName: "enable-versioning", Passed: true}
+	b.Apply("aws_s3_bucket.logs", &passed)
+	assert.False(t, passed.Baselined)
+
+	// Without a baseline nothing is marked
+	var none *Baseline
+	result := cloud.ValidationResult{RuleName: "enable-versioning"}
+	none.Apply("aws_s3_bucket.logs", &result)
+	assert.False(t, result.Baselined)
+}
@@ -3,6 +3,8 @@ package cloud
 
 import (
 	"context"
+
+	"golang.org/x/time/rate"
 )
 
 // Provider represents supported cloud providers
@@ -25,6 +27,7 @@ type ResourceStatus struct {
 	Properties   map[string]interface{} `json:"properties,omitempty"`
 	DriftDetected bool                  `json:"drift_detected"`
 	DriftDetails  []string              `json:"drift_details,omitempty"`
+	Drifts        []AttributeDrift      `json:"drifts,omitempty"` // drifted attributes, described by DriftDetails
 }
 
 // ValidationResult contains the result of a resource validation
@@ -36,6 +39,18 @@ type ValidationResult struct {
 	Severity    string   `json:"severity"` // "error", "warning", "info"
 	Remediation string   `json:"remediation,omitempty"`
 	Details     []string `json:"details,omitempty"`
+
+	// Waived marks a failed result suppressed by a waiver; it does not fail the resource
+	Waived        bool   `json:"waived,omitempty"`
+	WaiverReason  string `json:"waiver_reason,omitempty"`
+	WaiverExpires string `json:"waiver_expires,omitempty"`
+
+	// Baselined marks a failed result already recorded in the baseline; it does not fail the resource
+	Baselined bool `json:"baselined,omitempty"`
+
+	// Source tells whether the rule was evaluated against the planned values
+	// or the deployed resource: SourcePlanned or SourceLive
+	Source string `json:"source,omitempty"`
 }
 
 // CloudConfig contains configuration for cloud provider authentication
@@ -58,6 +73,22 @@ type CloudConfig struct {
 	GCPProject            string
 	GCPCredentialsFile    string
 	GCPServiceAccountJSON string
+
+	// Fake adapter specific
+	FakeFixture string // YAML or JSON fixture of the resources it serves
+
+	// Record/replay of API traffic for regression tests; see Recorder
+	RecordDir    string // record sanitized API traffic to cassettes in this directory
+	ReplayDir    string // serve API traffic from the cassettes in this directory, without network access
+	CassetteName string // cassette file name in RecordDir or ReplayDir; defaults to the provider name
+
+	// Endpoint overrides, e.g. for LocalStack, Azurite or fake-gcs-server; see Endpoint
+	EndpointURL string            // base URL of every service the adapter calls
+	Endpoints   map[string]string // base URL by service (see EndpointServices), overriding EndpointURL
+
+	// RateLimiter is the request budget of the provider, shared by its
+	// adapters (see RateLimiters and Transport); nil does not limit requests
+	RateLimiter *rate.Limiter
 }
 
 // Adapter defines the interface for cloud provider operations
@@ -78,12 +109,20 @@ type Adapter interface {
 	// GetResourceStatus retrieves the current status of a resource from the cloud
 	GetResourceStatus(ctx context.Context, resourceType, resourceID string) (*ResourceStatus, error)
 
-	// ValidateResourceCompliance checks if a resource complies with policies
-	ValidateResourceCompliance(ctx context.Context, resourceType string, resource map[string]interface{}, rules []ValidationRule) ([]ValidationResult, error)
+	// ValidateResourceCompliance evaluates the rules against the live
+	// configuration of a deployed resource, returning results for the rules
+	// whose conditions the adapter can check (see LiveChecks). A resource that
+	// does not exist has no results.
+	ValidateResourceCompliance(ctx context.Context, resourceType, resourceID string, rules []ValidationRule) ([]ValidationResult, error)
 
 	// DetectDrift compares Terraform state with actual cloud resources
 	DetectDrift(ctx context.Context, plannedState map[string]interface{}, resourceType, resourceID string) (*ResourceStatus, error)
 
+	// CheckResource reads a deployed resource once to both evaluate the rules
+	// against its live configuration, like ValidateResourceCompliance, and
+	// compare it with the planned state, like DetectDrift
+	CheckResource(ctx context.Context, plannedState map[string]interface{}, resourceType, resourceID string, rules []ValidationRule) (*ResourceStatus, []ValidationResult, error)
+
 	// ListResources lists resources of a given type (optional; for discovery)
 	ListResources(ctx context.Context, resourceType string) ([]string, error)
 
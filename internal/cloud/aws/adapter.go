@@ -4,25 +4,45 @@ package aws
 import (
 	"context"
 	"fmt"
+	"net/http"
 	"os"
+	"reflect"
 	"strings"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/cloudtrail"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
 	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
 	"github.com/aws/aws-sdk-go-v2/service/iam"
+	"github.com/aws/aws-sdk-go-v2/service/kms"
+	"github.com/aws/aws-sdk-go-v2/service/lambda"
+	"github.com/aws/aws-sdk-go-v2/service/rds"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
 	"github.com/vijayaxai/terraship/internal/cloud"
+	"github.com/vijayaxai/terraship/internal/cloud/cassette"
 )
 
 // Adapter implements cloud.Adapter for AWS
 type Adapter struct {
-	cfg       aws.Config
-	ec2Client *ec2.Client
-	s3Client  *s3.Client
-	iamClient *iam.Client
-	region    string
-	profile   string
+	cfg              aws.Config
+	ec2Client        *ec2.Client
+	s3Client         *s3.Client
+	iamClient        *iam.Client
+	rdsClient        *rds.Client
+	lambdaClient     *lambda.Client
+	kmsClient        *kms.Client
+	dynamodbClient   *dynamodb.Client
+	cloudtrailClient *cloudtrail.Client
+	region           string
+	profile          string
+	recorder         *cassette.Recorder
+}
+
+func init() {
+	cloud.Register(cloud.ProviderAWS, func() cloud.Adapter { return NewAdapter() }, "hashicorp/aws")
 }
 
 // NewAdapter creates a new AWS adapter
@@ -62,14 +82,63 @@ func (a *Adapter) Initialize(ctx context.Context, cloudConfig cloud.CloudConfig)
 		return fmt.Errorf("failed to load AWS config: %w", err)
 	}
 
+	// Rate limit, record or replay API traffic through the configured HTTP
+	// client; replays need no real credentials
+	transport, recorder, err := cloudConfig.Transport(cassette.Transport(cfg.HTTPClient))
+	if err != nil {
+		return err
+	}
+	a.recorder = recorder
+	if transport != nil {
+		cfg.HTTPClient = &http.Client{Transport: transport}
+	}
+	if a.recorder.Replaying() {
+		cfg.Credentials = credentials.NewStaticCredentialsProvider("replay", "replay", "")
+	}
+
+	// Point clients at endpoint overrides, e.g. LocalStack. The SDK itself
+	// reads AWS_ENDPOINT_URL and its per-service variants.
 	a.cfg = cfg
-	a.ec2Client = ec2.NewFromConfig(cfg)
-	a.s3Client = s3.NewFromConfig(cfg)
-	a.iamClient = iam.NewFromConfig(cfg)
+	a.ec2Client = ec2.NewFromConfig(cfg, func(o *ec2.Options) {
+		setEndpoint(&o.BaseEndpoint, cloudConfig.Endpoint("ec2"))
+	})
+	a.s3Client = s3.NewFromConfig(cfg, func(o *s3.Options) {
+		setEndpoint(&o.BaseEndpoint, cloudConfig.Endpoint("s3"))
+		if o.BaseEndpoint != nil {
+			// Emulators serve buckets by path rather than by virtual host
+			o.UsePathStyle = true
+		}
+	})
+	a.iamClient = iam.NewFromConfig(cfg, func(o *iam.Options) {
+		setEndpoint(&o.BaseEndpoint, cloudConfig.Endpoint("iam"))
+	})
+	a.rdsClient = rds.NewFromConfig(cfg, func(o *rds.Options) {
+		setEndpoint(&o.BaseEndpoint, cloudConfig.Endpoint("rds"))
+	})
+	a.lambdaClient = lambda.NewFromConfig(cfg, func(o *lambda.Options) {
+		setEndpoint(&o.BaseEndpoint, cloudConfig.Endpoint("lambda"))
+	})
+	a.kmsClient = kms.NewFromConfig(cfg, func(o *kms.Options) {
+		setEndpoint(&o.BaseEndpoint, cloudConfig.Endpoint("kms"))
+	})
+	a.dynamodbClient = dynamodb.NewFromConfig(cfg, func(o *dynamodb.Options) {
+		setEndpoint(&o.BaseEndpoint, cloudConfig.Endpoint("dynamodb"))
+	})
+	a.cloudtrailClient = cloudtrail.NewFromConfig(cfg, func(o *cloudtrail.Options) {
+		setEndpoint(&o.BaseEndpoint, cloudConfig.Endpoint("cloudtrail"))
+	})
 
 	return nil
 }
 
+// setEndpoint overrides a client's base endpoint, keeping the one the SDK
+// resolved when endpoint is empty
+func setEndpoint(base **string, endpoint string) {
+	if endpoint != "" {
+		*base = aws.String(endpoint)
+	}
+}
+
 // DetectProvider attempts to detect if AWS is the provider
 func (a *Adapter) DetectProvider(ctx context.Context) (bool, float64, error) {
 	confidence := 0.0
@@ -118,10 +187,30 @@ func (a *Adapter) GetResourceStatus(ctx context.Context, resourceType, resourceI
 	switch {
 	case strings.HasPrefix(resourceType, "aws_instance"):
 		return a.getEC2InstanceStatus(ctx, resourceID)
+	case resourceType == "aws_s3_bucket_public_access_block":
+		return a.getS3PublicAccessBlockStatus(ctx, resourceID)
 	case strings.HasPrefix(resourceType, "aws_s3_bucket"):
 		return a.getS3BucketStatus(ctx, resourceID)
 	case strings.HasPrefix(resourceType, "aws_iam_role"):
 		return a.getIAMRoleStatus(ctx, resourceID)
+	case resourceType == "aws_db_instance":
+		return a.getDBInstanceStatus(ctx, resourceID)
+	case resourceType == "aws_ebs_volume":
+		return a.getEBSVolumeStatus(ctx, resourceID)
+	case resourceType == "aws_security_group":
+		return a.getSecurityGroupStatus(ctx, resourceID)
+	case resourceType == "aws_vpc":
+		return a.getVPCStatus(ctx, resourceID)
+	case resourceType == "aws_subnet":
+		return a.getSubnetStatus(ctx, resourceID)
+	case resourceType == "aws_lambda_function":
+		return a.getLambdaFunctionStatus(ctx, resourceID)
+	case resourceType == "aws_kms_key":
+		return a.getKMSKeyStatus(ctx, resourceID)
+	case resourceType == "aws_dynamodb_table":
+		return a.getDynamoDBTableStatus(ctx, resourceID)
+	case resourceType == "aws_cloudtrail":
+		return a.getCloudTrailStatus(ctx, resourceID)
 	default:
 		return status, fmt.Errorf("unsupported resource type: %s", resourceType)
 	}
@@ -251,17 +340,184 @@ func (a *Adapter) getIAMRoleStatus(ctx context.Context, roleName string) (*cloud
 		Exists:       true,
 		Tags:         tags,
 		Properties: map[string]interface{}{
-			"arn":         *result.Role.Arn,
-			"create_date": result.Role.CreateDate,
+			"arn":                  *result.Role.Arn,
+			"create_date":          result.Role.CreateDate,
+			"description":          result.Role.Description,
+			"max_session_duration": result.Role.MaxSessionDuration,
+			"path":                 result.Role.Path,
 		},
 	}, nil
 }
 
-// ValidateResourceCompliance checks resource compliance with policies
-func (a *Adapter) ValidateResourceCompliance(ctx context.Context, resourceType string, resource map[string]interface{}, rules []cloud.ValidationRule) ([]cloud.ValidationResult, error) {
-	// This is typically handled by the rules engine
-	// Cloud-specific validations can be added here
-	return []cloud.ValidationResult{}, nil
+// differs map the planned attributes of each resource type to the properties
+// GetResourceStatus reads; other types are compared by tags only
+var differs = map[string]cloud.Differ{
+	"aws_instance": {Attributes: []cloud.AttributeMapping{
+		{Attribute: "instance_type", Property: "instance_type"},
+		{Attribute: "availability_zone", Property: "availability_zone"},
+		{Attribute: "private_ip", Property: "private_ip"},
+		{Attribute: "public_ip", Property: "public_ip"},
+	}},
+	"aws_s3_bucket": {Attributes: []cloud.AttributeMapping{
+		{Attribute: "versioning[0].enabled", Property: "versioning_enabled"},
+		{Attribute: "server_side_encryption_configuration", Property: "encryption_enabled", Equal: configured},
+	}},
+	"aws_s3_bucket_public_access_block": {Attributes: []cloud.AttributeMapping{
+		{Attribute: "block_public_acls", Property: "block_public_acls"},
+		{Attribute: "block_public_policy", Property: "block_public_policy"},
+		{Attribute: "ignore_public_acls", Property: "ignore_public_acls"},
+		{Attribute: "restrict_public_buckets", Property: "restrict_public_buckets"},
+	}},
+	"aws_iam_role": {Attributes: []cloud.AttributeMapping{
+		{Attribute: "description", Property: "description"},
+		{Attribute: "max_session_duration", Property: "max_session_duration"},
+		{Attribute: "path", Property: "path"},
+	}},
+	"aws_db_instance": {Attributes: []cloud.AttributeMapping{
+		{Attribute: "instance_class", Property: "instance_class"},
+		{Attribute: "engine", Property: "engine"},
+		{Attribute: "engine_version", Property: "engine_version", Equal: versionPrefix},
+		{Attribute: "allocated_storage", Property: "allocated_storage"},
+		{Attribute: "storage_type", Property: "storage_type"},
+		{Attribute: "storage_encrypted", Property: "storage_encrypted"},
+		{Attribute: "multi_az", Property: "multi_az"},
+		{Attribute: "publicly_accessible", Property: "publicly_accessible"},
+		{Attribute: "backup_retention_period", Property: "backup_retention_period"},
+		{Attribute: "deletion_protection", Property: "deletion_protection"},
+	}},
+	"aws_ebs_volume": {Attributes: []cloud.AttributeMapping{
+		{Attribute: "availability_zone", Property: "availability_zone"},
+		{Attribute: "size", Property: "size"},
+		{Attribute: "type", Property: "type"},
+		{Attribute: "iops", Property: "iops"},
+		{Attribute: "throughput", Property: "throughput"},
+		{Attribute: "encrypted", Property: "encrypted"},
+		{Attribute: "kms_key_id", Property: "kms_key_id"},
+	}},
+	"aws_security_group": {Attributes: []cloud.AttributeMapping{
+		{Attribute: "name", Property: "name"},
+		{Attribute: "description", Property: "description"},
+		{Attribute: "vpc_id", Property: "vpc_id"},
+		{Attribute: "ingress", Property: "ingress_cidr_blocks", Equal: sameCIDRBlocks},
+	}},
+	"aws_vpc": {Attributes: []cloud.AttributeMapping{
+		{Attribute: "cidr_block", Property: "cidr_block"},
+		{Attribute: "instance_tenancy", Property: "instance_tenancy"},
+		{Attribute: "enable_dns_support", Property: "enable_dns_support"},
+		{Attribute: "enable_dns_hostnames", Property: "enable_dns_hostnames"},
+	}},
+	"aws_subnet": {Attributes: []cloud.AttributeMapping{
+		{Attribute: "vpc_id", Property: "vpc_id"},
+		{Attribute: "cidr_block", Property: "cidr_block"},
+		{Attribute: "availability_zone", Property: "availability_zone"},
+		{Attribute: "map_public_ip_on_launch", Property: "map_public_ip_on_launch"},
+	}},
+	"aws_lambda_function": {Attributes: []cloud.AttributeMapping{
+		{Attribute: "runtime", Property: "runtime"},
+		{Attribute: "handler", Property: "handler"},
+		{Attribute: "role", Property: "role"},
+		{Attribute: "description", Property: "description"},
+		{Attribute: "memory_size", Property: "memory_size"},
+		{Attribute: "timeout", Property: "timeout"},
+		{Attribute: "package_type", Property: "package_type"},
+		{Attribute: "architectures", Property: "architectures"},
+	}},
+	"aws_kms_key": {Attributes: []cloud.AttributeMapping{
+		{Attribute: "description", Property: "description"},
+		{Attribute: "is_enabled", Property: "is_enabled"},
+		{Attribute: "key_usage", Property: "key_usage"},
+		{Attribute: "customer_master_key_spec", Property: "customer_master_key_spec"},
+		{Attribute: "multi_region", Property: "multi_region"},
+		{Attribute: "enable_key_rotation", Property: "enable_key_rotation"},
+	}},
+	"aws_dynamodb_table": {Attributes: []cloud.AttributeMapping{
+		{Attribute: "billing_mode", Property: "billing_mode"},
+		{Attribute: "hash_key", Property: "hash_key"},
+		{Attribute: "range_key", Property: "range_key"},
+		{Attribute: "read_capacity", Property: "read_capacity"},
+		{Attribute: "write_capacity", Property: "write_capacity"},
+		{Attribute: "stream_enabled", Property: "stream_enabled"},
+		{Attribute: "server_side_encryption[0].enabled", Property: "encryption_enabled"},
+		{Attribute: "point_in_time_recovery[0].enabled", Property: "point_in_time_recovery_enabled"},
+		{Attribute: "deletion_protection_enabled", Property: "deletion_protection_enabled"},
+	}},
+	"aws_cloudtrail": {Attributes: []cloud.AttributeMapping{
+		{Attribute: "s3_bucket_name", Property: "s3_bucket_name"},
+		{Attribute: "s3_key_prefix", Property: "s3_key_prefix"},
+		{Attribute: "include_global_service_events", Property: "include_global_service_events"},
+		{Attribute: "is_multi_region_trail", Property: "is_multi_region_trail"},
+		{Attribute: "is_organization_trail", Property: "is_organization_trail"},
+		{Attribute: "enable_log_file_validation", Property: "enable_log_file_validation"},
+		{Attribute: "enable_logging", Property: "enable_logging"},
+		{Attribute: "kms_key_id", Property: "kms_key_id"},
+	}},
+}
+
+// configured compares a planned configuration block with a property telling
+// whether the deployed resource has that configuration
+func configured(planned, actual interface{}) bool {
+	blocks, _ := planned.([]interface{})
+	return cloud.EqualValues(len(blocks) > 0, actual)
+}
+
+// versionPrefix compares a planned engine version such as 15 with the
+// deployed version, e.g. 15.4, which AWS upgrades within minor versions
+func versionPrefix(planned, actual interface{}) bool {
+	version, deployed := fmt.Sprint(planned), fmt.Sprint(actual)
+	return deployed == version || strings.HasPrefix(deployed, version+".")
+}
+
+// sameCIDRBlocks compares the CIDR blocks of planned ingress rules with those
+// the deployed rules allow, regardless of how they are grouped into rules.
+// Groups without inline rules are not compared, since their rules are usually
+// aws_security_group_rule resources of their own.
+func sameCIDRBlocks(planned, actual interface{}) bool {
+	rules, _ := planned.([]interface{})
+	deployed, _ := actual.([]string)
+	if len(rules) == 0 {
+		return true
+	}
+
+	blocks := make(map[string]bool)
+	for _, rule := range rules {
+		fields, _ := rule.(map[string]interface{})
+		for _, name := range []string{"cidr_blocks", "ipv6_cidr_blocks"} {
+			cidrs, _ := fields[name].([]interface{})
+			for _, cidr := range cidrs {
+				blocks[fmt.Sprint(cidr)] = true
+			}
+		}
+	}
+
+	actualBlocks := make(map[string]bool)
+	for _, cidr := range deployed {
+		actualBlocks[cidr] = true
+	}
+	return reflect.DeepEqual(blocks, actualBlocks)
+}
+
+// liveChecks answer built-in policy conditions for deployed resources;
+// attribute conditions are answered with the properties of differs
+var liveChecks = map[string]cloud.LiveChecks{
+	"aws_s3_bucket": {
+		"public_access.blocked": s3PublicAccessBlocked,
+		"encryption.enabled":    cloud.EnabledCheck("encryption_enabled", "Default encryption is not configured on the bucket"),
+		"versioning.enabled":    cloud.EnabledCheck("versioning_enabled", "Versioning is not enabled on the bucket"),
+	},
+	"aws_ebs_volume": {
+		"encryption.enabled": cloud.EnabledCheck("encrypted", "Volume is not encrypted"),
+	},
+	"aws_db_instance": {
+		"encryption.enabled":    cloud.EnabledCheck("storage_encrypted", "DB instance storage is not encrypted"),
+		"public_access.blocked": notPubliclyAccessible,
+	},
+}
+
+// ValidateResourceCompliance evaluates the rules against the live
+// configuration of a deployed AWS resource
+func (a *Adapter) ValidateResourceCompliance(ctx context.Context, resourceType, resourceID string, rules []cloud.ValidationRule) ([]cloud.ValidationResult, error) {
+	checks, read := a.complianceChecks(ctx, resourceType, resourceID, rules)
+	return checks.Check(rules, read)
 }
 
 // DetectDrift compares planned state with actual cloud resources
@@ -270,59 +526,88 @@ func (a *Adapter) DetectDrift(ctx context.Context, plannedState map[string]inter
 	if err != nil {
 		return nil, err
 	}
+	return setDrift(plannedState, resourceType, actualStatus), nil
+}
 
-	if !actualStatus.Exists {
-		actualStatus.DriftDetected = true
-		actualStatus.DriftDetails = []string{"Resource does not exist in AWS"}
-		return actualStatus, nil
+// CheckResource reads a deployed AWS resource once to evaluate the rules
+// against it and detect drift
+func (a *Adapter) CheckResource(ctx context.Context, plannedState map[string]interface{}, resourceType, resourceID string, rules []cloud.ValidationRule) (*cloud.ResourceStatus, []cloud.ValidationResult, error) {
+	checks, read := a.complianceChecks(ctx, resourceType, resourceID, rules)
+	actualStatus, err := read()
+	if err != nil {
+		return nil, nil, err
 	}
+	results := checks.Results(rules, actualStatus)
+	return setDrift(plannedState, resourceType, actualStatus), results, nil
+}
 
-	// Compare planned vs actual - basic implementation
-	// In production, this would be more sophisticated
-	driftDetails := []string{}
-
-	// Check tags
-	if plannedTags, ok := plannedState["tags"].(map[string]interface{}); ok {
-		for key, value := range plannedTags {
-			if actualValue, exists := actualStatus.Tags[key]; !exists {
-				driftDetails = append(driftDetails, fmt.Sprintf("Tag '%s' missing", key))
-			} else if fmt.Sprint(value) != actualValue {
-				driftDetails = append(driftDetails, fmt.Sprintf("Tag '%s' differs: planned=%v, actual=%v", key, value, actualValue))
+// complianceChecks returns the live checks of a resource type and how to read
+// the resource for the rules. Buckets the rules check live are read with their
+// public access block and bucket policy status, so policies can require the
+// settings of the block of a bucket.
+func (a *Adapter) complianceChecks(ctx context.Context, resourceType, resourceID string, rules []cloud.ValidationRule) (cloud.LiveChecks, func() (*cloud.ResourceStatus, error)) {
+	checks := differs[resourceType].LiveChecks(liveChecks[resourceType])
+	if resourceType == "aws_s3_bucket" {
+		checks = differs["aws_s3_bucket_public_access_block"].LiveChecks(checks)
+		if checks.Applies(rules) {
+			return checks, func() (*cloud.ResourceStatus, error) {
+				return a.getS3BucketExposure(ctx, resourceID)
 			}
 		}
 	}
 
-	if len(driftDetails) > 0 {
+	return checks, func() (*cloud.ResourceStatus, error) {
+		return a.GetResourceStatus(ctx, resourceType, resourceID)
+	}
+}
+
+// setDrift records the drift of a deployed resource of resourceType from the
+// planned state
+func setDrift(plannedState map[string]interface{}, resourceType string, actualStatus *cloud.ResourceStatus) *cloud.ResourceStatus {
+	if !actualStatus.Exists {
 		actualStatus.DriftDetected = true
-		actualStatus.DriftDetails = driftDetails
+		actualStatus.DriftDetails = []string{"Resource does not exist in AWS"}
+		return actualStatus
 	}
 
-	return actualStatus, nil
+	actualStatus.SetDrift(differs[resourceType].Diff(plannedState, actualStatus))
+	return actualStatus
 }
 
-// ListResources lists AWS resources of a given type
+// ListResources lists AWS resources of a given type. Sub-resources such as
+// aws_s3_bucket_versioning are not listed, since not every bucket has one.
 func (a *Adapter) ListResources(ctx context.Context, resourceType string) ([]string, error) {
-	switch {
-	case strings.HasPrefix(resourceType, "aws_instance"):
+	switch resourceType {
+	case "aws_instance":
 		return a.listEC2Instances(ctx)
-	case strings.HasPrefix(resourceType, "aws_s3_bucket"):
+	case "aws_s3_bucket":
 		return a.listS3Buckets(ctx)
 	default:
 		return nil, fmt.Errorf("listing not supported for resource type: %s", resourceType)
 	}
 }
 
+// listEC2Instances lists the instances of every page. Terminated and
+// shutting-down instances are left out: terraform no longer manages them.
 func (a *Adapter) listEC2Instances(ctx context.Context) ([]string, error) {
-	result, err := a.ec2Client.DescribeInstances(ctx, &ec2.DescribeInstancesInput{})
-	if err != nil {
-		return nil, err
-	}
+	paginator := ec2.NewDescribeInstancesPaginator(a.ec2Client, &ec2.DescribeInstancesInput{
+		Filters: []ec2types.Filter{{
+			Name:   aws.String("instance-state-name"),
+			Values: []string{"pending", "running", "stopping", "stopped"},
+		}},
+	})
 
 	var instanceIDs []string
-	for _, reservation := range result.Reservations {
-		for _, instance := range reservation.Instances {
-			if instance.InstanceId != nil {
-				instanceIDs = append(instanceIDs, *instance.InstanceId)
+	for paginator.HasMorePages() {
+		result, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, reservation := range result.Reservations {
+			for _, instance := range reservation.Instances {
+				if instance.InstanceId != nil {
+					instanceIDs = append(instanceIDs, *instance.InstanceId)
+				}
 			}
 		}
 	}
@@ -349,5 +634,8 @@ func (a *Adapter) listS3Buckets(ctx context.Context) ([]string, error) {
 // Close cleans up AWS adapter resources
 func (a *Adapter) Close() error {
 	// AWS SDK clients don't require explicit cleanup
+	if a.recorder != nil {
+		return a.recorder.Save()
+	}
 	return nil
 }
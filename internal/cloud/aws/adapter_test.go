@@ -0,0 +1,102 @@
+package aws
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vijayaxai/terraship/internal/cloud"
+)
+
+// newReplayAdapter returns an adapter serving the API traffic recorded in
+// testdata/replay/aws.json
+func newReplayAdapter(t *testing.T) *Adapter {
+	t.Helper()
+	t.Setenv("AWS_PROFILE", "")
+	t.Setenv("AWS_CONFIG_FILE", "testdata/no-such-config")
+	t.Setenv("AWS_SHARED_CREDENTIALS_FILE", "testdata/no-such-credentials")
+
+	adapter := NewAdapter()
+	err := adapter.Initialize(context.Background(), cloud.CloudConfig{
+		Provider:  cloud.ProviderAWS,
+		AWSRegion: "us-east-1",
+		ReplayDir: "testdata/replay",
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() { assert.NoError(t, adapter.Close()) })
+
+	return adapter
+}
+
+func TestAdapter_ReplayDetectDrift(t *testing.T) {
+	ctx := context.Background()
+	adapter := newReplayAdapter(t)
+	require.NoError(t, adapter.ValidateCredentials(ctx))
+
+	planned := map[string]interface{}{
+		"tags": map[string]interface{}{"Environment": "production", "Owner": "platform"},
+	}
+
+	instance, err := adapter.DetectDrift(ctx, planned, "aws_instance", "i-0a1b2c3d4e5f67890")
+	require.NoError(t, err)
+	assert.True(t, instance.Exists)
+	assert.Equal(t, "running", instance.State)
+	assert.True(t, instance.DriftDetected)
+	assert.Equal(t, []string{"Tag 'Environment' differs: planned=production, actual=staging"}, instance.DriftDetails)
+
+	bucket, err := adapter.DetectDrift(ctx, planned, "aws_s3_bucket", "acme-app-logs")
+	require.NoError(t, err)
+	assert.True(t, bucket.Exists)
+	assert.False(t, bucket.DriftDetected)
+	assert.Equal(t, true, bucket.Properties["encryption_enabled"])
+	assert.Equal(t, true, bucket.Properties["versioning_enabled"])
+
+	role, err := adapter.DetectDrift(ctx, planned, "aws_iam_role", "deploy")
+	require.NoError(t, err)
+	assert.False(t, role.Exists)
+	assert.Equal(t, []string{"Resource does not exist in AWS"}, role.DriftDetails)
+}
+
+func TestAdapter_EndpointOverride(t *testing.T) {
+	var paths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.Method+" "+r.URL.Path)
+		w.Header().Set("Content-Type", "application/xml")
+		_, _ = io.WriteString(w, `<ListAllMyBucketsResult><Buckets><Bucket><Name>acme-app-logs</Name></Bucket></Buckets></ListAllMyBucketsResult>`)
+	}))
+	defer server.Close()
+
+	t.Setenv("AWS_PROFILE", "")
+	t.Setenv("AWS_CONFIG_FILE", "testdata/no-such-config")
+	t.Setenv("AWS_SHARED_CREDENTIALS_FILE", "testdata/no-such-credentials")
+	t.Setenv("AWS_ENDPOINT_URL", "")
+	t.Setenv("AWS_ACCESS_KEY_ID", "test")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "test")
+
+	ctx := context.Background()
+	adapter := NewAdapter()
+	require.NoError(t, adapter.Initialize(ctx, cloud.CloudConfig{
+		Provider:  cloud.ProviderAWS,
+		AWSRegion: "us-east-1",
+		Endpoints: map[string]string{"s3": server.URL},
+	}))
+	defer adapter.Close()
+
+	buckets, err := adapter.ListResources(ctx, "aws_s3_bucket")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"acme-app-logs"}, buckets)
+
+	// Sub-resources of buckets are not listed as buckets
+	_, err = adapter.ListResources(ctx, "aws_s3_bucket_versioning")
+	assert.EqualError(t, err, "listing not supported for resource type: aws_s3_bucket_versioning")
+
+	// Buckets are addressed by path on the emulator
+	status, err := adapter.GetResourceStatus(ctx, "aws_s3_bucket", "acme-app-logs")
+	require.NoError(t, err)
+	assert.True(t, status.Exists)
+	assert.Equal(t, []string{"GET /", "HEAD /acme-app-logs"}, paths[:2])
+}
@@ -0,0 +1,599 @@
+package aws
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudtrail"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	dynamodbtypes "github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/aws/aws-sdk-go-v2/service/kms"
+	kmstypes "github.com/aws/aws-sdk-go-v2/service/kms/types"
+	"github.com/aws/aws-sdk-go-v2/service/lambda"
+	lambdatypes "github.com/aws/aws-sdk-go-v2/service/lambda/types"
+	"github.com/aws/aws-sdk-go-v2/service/rds"
+	rdstypes "github.com/aws/aws-sdk-go-v2/service/rds/types"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	s3types "github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/aws/smithy-go"
+	"github.com/vijayaxai/terraship/internal/cloud"
+)
+
+// missing returns the status of a resource that does not exist
+func missing(resourceID, resourceType string) *cloud.ResourceStatus {
+	return &cloud.ResourceStatus{
+		ResourceID:   resourceID,
+		ResourceType: resourceType,
+		Exists:       false,
+	}
+}
+
+// errorCode returns the code of an AWS API error, for the EC2 and S3 errors
+// the SDK has no types for, e.g. InvalidVolume.NotFound
+func errorCode(err error) string {
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.ErrorCode()
+	}
+	return ""
+}
+
+// ec2Tags converts EC2 tags to a map
+func ec2Tags(tags []ec2types.Tag) map[string]string {
+	result := make(map[string]string)
+	for _, tag := range tags {
+		if tag.Key != nil && tag.Value != nil {
+			result[*tag.Key] = *tag.Value
+		}
+	}
+	return result
+}
+
+func (a *Adapter) getEBSVolumeStatus(ctx context.Context, volumeID string) (*cloud.ResourceStatus, error) {
+	result, err := a.ec2Client.DescribeVolumes(ctx, &ec2.DescribeVolumesInput{
+		VolumeIds: []string{volumeID},
+	})
+	if errorCode(err) == "InvalidVolume.NotFound" || (err == nil && len(result.Volumes) == 0) {
+		return missing(volumeID, "aws_ebs_volume"), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe EBS volume: %w", err)
+	}
+
+	volume := result.Volumes[0]
+	return &cloud.ResourceStatus{
+		ResourceID:   volumeID,
+		ResourceType: "aws_ebs_volume",
+		Exists:       true,
+		State:        string(volume.State),
+		Tags:         ec2Tags(volume.Tags),
+		Properties: map[string]interface{}{
+			"availability_zone": aws.ToString(volume.AvailabilityZone),
+			"size":              aws.ToInt32(volume.Size),
+			"type":              string(volume.VolumeType),
+			"iops":              aws.ToInt32(volume.Iops),
+			"throughput":        aws.ToInt32(volume.Throughput),
+			"encrypted":         aws.ToBool(volume.Encrypted),
+			"kms_key_id":        aws.ToString(volume.KmsKeyId),
+		},
+	}, nil
+}
+
+func (a *Adapter) getSecurityGroupStatus(ctx context.Context, groupID string) (*cloud.ResourceStatus, error) {
+	result, err := a.ec2Client.DescribeSecurityGroups(ctx, &ec2.DescribeSecurityGroupsInput{
+		GroupIds: []string{groupID},
+	})
+	if errorCode(err) == "InvalidGroup.NotFound" || (err == nil && len(result.SecurityGroups) == 0) {
+		return missing(groupID, "aws_security_group"), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe security group: %w", err)
+	}
+
+	group := result.SecurityGroups[0]
+	return &cloud.ResourceStatus{
+		ResourceID:   groupID,
+		ResourceType: "aws_security_group",
+		Exists:       true,
+		Tags:         ec2Tags(group.Tags),
+		Properties: map[string]interface{}{
+			"name":                aws.ToString(group.GroupName),
+			"description":         aws.ToString(group.Description),
+			"vpc_id":              aws.ToString(group.VpcId),
+			"ingress_rules":       len(group.IpPermissions),
+			"egress_rules":        len(group.IpPermissionsEgress),
+			"ingress_cidr_blocks": ingressCIDRBlocks(group.IpPermissions),
+		},
+	}, nil
+}
+
+// ingressCIDRBlocks lists the IPv4 and IPv6 ranges ingress rules allow
+func ingressCIDRBlocks(permissions []ec2types.IpPermission) []string {
+	blocks := make([]string, 0)
+	for _, permission := range permissions {
+		for _, ipRange := range permission.IpRanges {
+			blocks = append(blocks, aws.ToString(ipRange.CidrIp))
+		}
+		for _, ipRange := range permission.Ipv6Ranges {
+			blocks = append(blocks, aws.ToString(ipRange.CidrIpv6))
+		}
+	}
+	return blocks
+}
+
+func (a *Adapter) getVPCStatus(ctx context.Context, vpcID string) (*cloud.ResourceStatus, error) {
+	result, err := a.ec2Client.DescribeVpcs(ctx, &ec2.DescribeVpcsInput{
+		VpcIds: []string{vpcID},
+	})
+	if errorCode(err) == "InvalidVpcID.NotFound" || (err == nil && len(result.Vpcs) == 0) {
+		return missing(vpcID, "aws_vpc"), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe VPC: %w", err)
+	}
+
+	vpc := result.Vpcs[0]
+	status := &cloud.ResourceStatus{
+		ResourceID:   vpcID,
+		ResourceType: "aws_vpc",
+		Exists:       true,
+		State:        string(vpc.State),
+		Tags:         ec2Tags(vpc.Tags),
+		Properties: map[string]interface{}{
+			"cidr_block":       aws.ToString(vpc.CidrBlock),
+			"instance_tenancy": string(vpc.InstanceTenancy),
+			"is_default":       aws.ToBool(vpc.IsDefault),
+		},
+	}
+
+	// DNS settings are attributes of their own
+	attributes := map[string]ec2types.VpcAttributeName{
+		"enable_dns_support":   ec2types.VpcAttributeNameEnableDnsSupport,
+		"enable_dns_hostnames": ec2types.VpcAttributeNameEnableDnsHostnames,
+	}
+	for property, attribute := range attributes {
+		output, err := a.ec2Client.DescribeVpcAttribute(ctx, &ec2.DescribeVpcAttributeInput{
+			VpcId:     aws.String(vpcID),
+			Attribute: attribute,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to describe VPC attribute %s: %w", attribute, err)
+		}
+		switch {
+		case attribute == ec2types.VpcAttributeNameEnableDnsSupport && output.EnableDnsSupport != nil:
+			status.Properties[property] = aws.ToBool(output.EnableDnsSupport.Value)
+		case attribute == ec2types.VpcAttributeNameEnableDnsHostnames && output.EnableDnsHostnames != nil:
+			status.Properties[property] = aws.ToBool(output.EnableDnsHostnames.Value)
+		}
+	}
+
+	return status, nil
+}
+
+func (a *Adapter) getSubnetStatus(ctx context.Context, subnetID string) (*cloud.ResourceStatus, error) {
+	result, err := a.ec2Client.DescribeSubnets(ctx, &ec2.DescribeSubnetsInput{
+		SubnetIds: []string{subnetID},
+	})
+	if errorCode(err) == "InvalidSubnetID.NotFound" || (err == nil && len(result.Subnets) == 0) {
+		return missing(subnetID, "aws_subnet"), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe subnet: %w", err)
+	}
+
+	subnet := result.Subnets[0]
+	return &cloud.ResourceStatus{
+		ResourceID:   subnetID,
+		ResourceType: "aws_subnet",
+		Exists:       true,
+		State:        string(subnet.State),
+		Tags:         ec2Tags(subnet.Tags),
+		Properties: map[string]interface{}{
+			"vpc_id":                  aws.ToString(subnet.VpcId),
+			"cidr_block":              aws.ToString(subnet.CidrBlock),
+			"availability_zone":       aws.ToString(subnet.AvailabilityZone),
+			"map_public_ip_on_launch": aws.ToBool(subnet.MapPublicIpOnLaunch),
+		},
+	}, nil
+}
+
+// getDBInstanceStatus reads an RDS DB instance. Terraform identifies DB
+// instances by their resource ID (db-ABCD...) since provider v5, and by their
+// identifier before.
+func (a *Adapter) getDBInstanceStatus(ctx context.Context, instanceID string) (*cloud.ResourceStatus, error) {
+	input := &rds.DescribeDBInstancesInput{}
+	if strings.HasPrefix(instanceID, "db-") && instanceID[3:] == strings.ToUpper(instanceID[3:]) {
+		input.Filters = []rdstypes.Filter{{Name: aws.String("dbi-resource-id"), Values: []string{instanceID}}}
+	} else {
+		input.DBInstanceIdentifier = aws.String(instanceID)
+	}
+
+	result, err := a.rdsClient.DescribeDBInstances(ctx, input)
+	var notFound *rdstypes.DBInstanceNotFoundFault
+	if errors.As(err, &notFound) || (err == nil && len(result.DBInstances) == 0) {
+		return missing(instanceID, "aws_db_instance"), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe DB instance: %w", err)
+	}
+
+	instance := result.DBInstances[0]
+	tags := make(map[string]string)
+	for _, tag := range instance.TagList {
+		if tag.Key != nil && tag.Value != nil {
+			tags[*tag.Key] = *tag.Value
+		}
+	}
+
+	return &cloud.ResourceStatus{
+		ResourceID:   instanceID,
+		ResourceType: "aws_db_instance",
+		Exists:       true,
+		State:        aws.ToString(instance.DBInstanceStatus),
+		Tags:         tags,
+		Properties: map[string]interface{}{
+			"identifier":              aws.ToString(instance.DBInstanceIdentifier),
+			"instance_class":          aws.ToString(instance.DBInstanceClass),
+			"engine":                  aws.ToString(instance.Engine),
+			"engine_version":          aws.ToString(instance.EngineVersion),
+			"allocated_storage":       aws.ToInt32(instance.AllocatedStorage),
+			"storage_type":            aws.ToString(instance.StorageType),
+			"storage_encrypted":       aws.ToBool(instance.StorageEncrypted),
+			"kms_key_id":              aws.ToString(instance.KmsKeyId),
+			"multi_az":                aws.ToBool(instance.MultiAZ),
+			"publicly_accessible":     aws.ToBool(instance.PubliclyAccessible),
+			"backup_retention_period": aws.ToInt32(instance.BackupRetentionPeriod),
+			"deletion_protection":     aws.ToBool(instance.DeletionProtection),
+		},
+	}, nil
+}
+
+func (a *Adapter) getLambdaFunctionStatus(ctx context.Context, functionName string) (*cloud.ResourceStatus, error) {
+	result, err := a.lambdaClient.GetFunction(ctx, &lambda.GetFunctionInput{
+		FunctionName: aws.String(functionName),
+	})
+	var notFound *lambdatypes.ResourceNotFoundException
+	if errors.As(err, &notFound) {
+		return missing(functionName, "aws_lambda_function"), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get Lambda function: %w", err)
+	}
+
+	function := result.Configuration
+	if function == nil {
+		function = &lambdatypes.FunctionConfiguration{}
+	}
+	architectures := make([]string, 0, len(function.Architectures))
+	for _, architecture := range function.Architectures {
+		architectures = append(architectures, string(architecture))
+	}
+
+	return &cloud.ResourceStatus{
+		ResourceID:   functionName,
+		ResourceType: "aws_lambda_function",
+		Exists:       true,
+		State:        string(function.State),
+		Tags:         result.Tags,
+		Properties: map[string]interface{}{
+			"arn":           aws.ToString(function.FunctionArn),
+			"runtime":       string(function.Runtime),
+			"handler":       aws.ToString(function.Handler),
+			"role":          aws.ToString(function.Role),
+			"description":   aws.ToString(function.Description),
+			"memory_size":   aws.ToInt32(function.MemorySize),
+			"timeout":       aws.ToInt32(function.Timeout),
+			"package_type":  string(function.PackageType),
+			"architectures": architectures,
+			"kms_key_arn":   aws.ToString(function.KMSKeyArn),
+		},
+	}, nil
+}
+
+func (a *Adapter) getKMSKeyStatus(ctx context.Context, keyID string) (*cloud.ResourceStatus, error) {
+	result, err := a.kmsClient.DescribeKey(ctx, &kms.DescribeKeyInput{
+		KeyId: aws.String(keyID),
+	})
+	var notFound *kmstypes.NotFoundException
+	if errors.As(err, &notFound) {
+		return missing(keyID, "aws_kms_key"), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe KMS key: %w", err)
+	}
+
+	key := result.KeyMetadata
+	if key == nil {
+		key = &kmstypes.KeyMetadata{}
+	}
+	status := &cloud.ResourceStatus{
+		ResourceID:   keyID,
+		ResourceType: "aws_kms_key",
+		Exists:       true,
+		State:        string(key.KeyState),
+		Properties: map[string]interface{}{
+			"arn":                      aws.ToString(key.Arn),
+			"description":              aws.ToString(key.Description),
+			"is_enabled":               key.Enabled,
+			"key_usage":                string(key.KeyUsage),
+			"customer_master_key_spec": string(key.KeySpec),
+			"multi_region":             aws.ToBool(key.MultiRegion),
+		},
+	}
+
+	// Get key rotation; asymmetric, HMAC and custom key store keys do not
+	// rotate, so their rotation stays unanswered
+	rotation, err := a.kmsClient.GetKeyRotationStatus(ctx, &kms.GetKeyRotationStatusInput{
+		KeyId: aws.String(keyID),
+	})
+	var unsupported *kmstypes.UnsupportedOperationException
+	switch {
+	case err == nil:
+		status.Properties["enable_key_rotation"] = rotation.KeyRotationEnabled
+	case !errors.As(err, &unsupported):
+		return nil, fmt.Errorf("failed to get KMS key rotation status: %w", err)
+	}
+
+	// Get key tags
+	tagsOutput, err := a.kmsClient.ListResourceTags(ctx, &kms.ListResourceTagsInput{
+		KeyId: aws.String(keyID),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list KMS key tags: %w", err)
+	}
+	status.Tags = make(map[string]string)
+	for _, tag := range tagsOutput.Tags {
+		if tag.TagKey != nil && tag.TagValue != nil {
+			status.Tags[*tag.TagKey] = *tag.TagValue
+		}
+	}
+
+	return status, nil
+}
+
+func (a *Adapter) getDynamoDBTableStatus(ctx context.Context, tableName string) (*cloud.ResourceStatus, error) {
+	result, err := a.dynamodbClient.DescribeTable(ctx, &dynamodb.DescribeTableInput{
+		TableName: aws.String(tableName),
+	})
+	var notFound *dynamodbtypes.ResourceNotFoundException
+	if errors.As(err, &notFound) {
+		return missing(tableName, "aws_dynamodb_table"), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe DynamoDB table: %w", err)
+	}
+
+	table := result.Table
+	if table == nil {
+		table = &dynamodbtypes.TableDescription{}
+	}
+	status := &cloud.ResourceStatus{
+		ResourceID:   tableName,
+		ResourceType: "aws_dynamodb_table",
+		Exists:       true,
+		State:        string(table.TableStatus),
+		Properties: map[string]interface{}{
+			"arn":                         aws.ToString(table.TableArn),
+			"billing_mode":                "PROVISIONED", // tables without a billing mode summary predate on-demand billing
+			"read_capacity":               int64(0),
+			"write_capacity":              int64(0),
+			"stream_enabled":              table.StreamSpecification != nil && aws.ToBool(table.StreamSpecification.StreamEnabled),
+			"encryption_enabled":          table.SSEDescription != nil && table.SSEDescription.Status == dynamodbtypes.SSEStatusEnabled,
+			"deletion_protection_enabled": aws.ToBool(table.DeletionProtectionEnabled),
+		},
+	}
+	if table.BillingModeSummary != nil && table.BillingModeSummary.BillingMode != "" {
+		status.Properties["billing_mode"] = string(table.BillingModeSummary.BillingMode)
+	}
+	if throughput := table.ProvisionedThroughput; throughput != nil {
+		status.Properties["read_capacity"] = aws.ToInt64(throughput.ReadCapacityUnits)
+		status.Properties["write_capacity"] = aws.ToInt64(throughput.WriteCapacityUnits)
+	}
+	for _, key := range table.KeySchema {
+		switch key.KeyType {
+		case dynamodbtypes.KeyTypeHash:
+			status.Properties["hash_key"] = aws.ToString(key.AttributeName)
+		case dynamodbtypes.KeyTypeRange:
+			status.Properties["range_key"] = aws.ToString(key.AttributeName)
+		}
+	}
+
+	// Get point-in-time recovery
+	backups, err := a.dynamodbClient.DescribeContinuousBackups(ctx, &dynamodb.DescribeContinuousBackupsInput{
+		TableName: aws.String(tableName),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe DynamoDB continuous backups: %w", err)
+	}
+	recovery := backups.ContinuousBackupsDescription
+	status.Properties["point_in_time_recovery_enabled"] = recovery != nil && recovery.PointInTimeRecoveryDescription != nil &&
+		recovery.PointInTimeRecoveryDescription.PointInTimeRecoveryStatus == dynamodbtypes.PointInTimeRecoveryStatusEnabled
+
+	// Get table tags
+	tagsOutput, err := a.dynamodbClient.ListTagsOfResource(ctx, &dynamodb.ListTagsOfResourceInput{
+		ResourceArn: table.TableArn,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list DynamoDB table tags: %w", err)
+	}
+	status.Tags = make(map[string]string)
+	for _, tag := range tagsOutput.Tags {
+		if tag.Key != nil && tag.Value != nil {
+			status.Tags[*tag.Key] = *tag.Value
+		}
+	}
+
+	return status, nil
+}
+
+// getCloudTrailStatus reads a trail by its name or, as provider v5 identifies
+// trails, its ARN
+func (a *Adapter) getCloudTrailStatus(ctx context.Context, trailID string) (*cloud.ResourceStatus, error) {
+	result, err := a.cloudtrailClient.DescribeTrails(ctx, &cloudtrail.DescribeTrailsInput{
+		TrailNameList: []string{trailID},
+	})
+	if err == nil && len(result.TrailList) == 0 {
+		return missing(trailID, "aws_cloudtrail"), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe CloudTrail trail: %w", err)
+	}
+
+	trail := result.TrailList[0]
+	status := &cloud.ResourceStatus{
+		ResourceID:   trailID,
+		ResourceType: "aws_cloudtrail",
+		Exists:       true,
+		Properties: map[string]interface{}{
+			"arn":                           aws.ToString(trail.TrailARN),
+			"name":                          aws.ToString(trail.Name),
+			"s3_bucket_name":                aws.ToString(trail.S3BucketName),
+			"s3_key_prefix":                 aws.ToString(trail.S3KeyPrefix),
+			"include_global_service_events": aws.ToBool(trail.IncludeGlobalServiceEvents),
+			"is_multi_region_trail":         aws.ToBool(trail.IsMultiRegionTrail),
+			"is_organization_trail":         aws.ToBool(trail.IsOrganizationTrail),
+			"enable_log_file_validation":    aws.ToBool(trail.LogFileValidationEnabled),
+			"kms_key_id":                    aws.ToString(trail.KmsKeyId),
+		},
+	}
+
+	// Get logging status
+	trailStatus, err := a.cloudtrailClient.GetTrailStatus(ctx, &cloudtrail.GetTrailStatusInput{
+		Name: trail.TrailARN,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get CloudTrail trail status: %w", err)
+	}
+	logging := aws.ToBool(trailStatus.IsLogging)
+	status.Properties["enable_logging"] = logging
+	status.State = "stopped"
+	if logging {
+		status.State = "logging"
+	}
+
+	// Get trail tags
+	tagsOutput, err := a.cloudtrailClient.ListTags(ctx, &cloudtrail.ListTagsInput{
+		ResourceIdList: []string{aws.ToString(trail.TrailARN)},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list CloudTrail trail tags: %w", err)
+	}
+	status.Tags = make(map[string]string)
+	for _, resource := range tagsOutput.ResourceTagList {
+		for _, tag := range resource.TagsList {
+			if tag.Key != nil && tag.Value != nil {
+				status.Tags[*tag.Key] = *tag.Value
+			}
+		}
+	}
+
+	return status, nil
+}
+
+// publicAccessSettings are the settings of an S3 public access block, by
+// Terraform attribute
+var publicAccessSettings = []string{"block_public_acls", "block_public_policy", "ignore_public_acls", "restrict_public_buckets"}
+
+// publicAccessBlockProperties returns the settings of an S3 public access
+// block by Terraform attribute; without a public access block every setting
+// is off
+func publicAccessBlockProperties(config *s3types.PublicAccessBlockConfiguration) map[string]interface{} {
+	if config == nil {
+		config = &s3types.PublicAccessBlockConfiguration{}
+	}
+	return map[string]interface{}{
+		"block_public_acls":       aws.ToBool(config.BlockPublicAcls),
+		"block_public_policy":     aws.ToBool(config.BlockPublicPolicy),
+		"ignore_public_acls":      aws.ToBool(config.IgnorePublicAcls),
+		"restrict_public_buckets": aws.ToBool(config.RestrictPublicBuckets),
+	}
+}
+
+func (a *Adapter) getS3PublicAccessBlockStatus(ctx context.Context, bucketName string) (*cloud.ResourceStatus, error) {
+	result, err := a.s3Client.GetPublicAccessBlock(ctx, &s3.GetPublicAccessBlockInput{
+		Bucket: aws.String(bucketName),
+	})
+	if code := errorCode(err); code == "NoSuchBucket" || code == "NoSuchPublicAccessBlockConfiguration" {
+		return missing(bucketName, "aws_s3_bucket_public_access_block"), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get S3 public access block: %w", err)
+	}
+
+	return &cloud.ResourceStatus{
+		ResourceID:   bucketName,
+		ResourceType: "aws_s3_bucket_public_access_block",
+		Exists:       true,
+		Properties:   publicAccessBlockProperties(result.PublicAccessBlockConfiguration),
+	}, nil
+}
+
+// getS3BucketExposure reads the status of a bucket along with the settings
+// that decide whether it is public: its public access block and whether its
+// bucket policy grants public access
+func (a *Adapter) getS3BucketExposure(ctx context.Context, bucketName string) (*cloud.ResourceStatus, error) {
+	status, err := a.getS3BucketStatus(ctx, bucketName)
+	if err != nil || !status.Exists {
+		return status, err
+	}
+
+	block, err := a.s3Client.GetPublicAccessBlock(ctx, &s3.GetPublicAccessBlockInput{
+		Bucket: aws.String(bucketName),
+	})
+	if err != nil && errorCode(err) != "NoSuchPublicAccessBlockConfiguration" {
+		return nil, fmt.Errorf("failed to get S3 public access block: %w", err)
+	}
+	var config *s3types.PublicAccessBlockConfiguration
+	if err == nil {
+		config = block.PublicAccessBlockConfiguration
+	}
+	for setting, enabled := range publicAccessBlockProperties(config) {
+		status.Properties[setting] = enabled
+	}
+
+	policy, err := a.s3Client.GetBucketPolicyStatus(ctx, &s3.GetBucketPolicyStatusInput{
+		Bucket: aws.String(bucketName),
+	})
+	if err != nil && errorCode(err) != "NoSuchBucketPolicy" {
+		return nil, fmt.Errorf("failed to get S3 bucket policy status: %w", err)
+	}
+	status.Properties["policy_public"] = err == nil && policy.PolicyStatus != nil && aws.ToBool(policy.PolicyStatus.IsPublic)
+
+	return status, nil
+}
+
+// s3PublicAccessBlocked answers public_access.blocked for a deployed bucket:
+// every public access block setting is on and the bucket policy is not public
+func s3PublicAccessBlocked(live map[string]interface{}, expected interface{}) (bool, []string, bool) {
+	if required, _ := expected.(bool); !required {
+		return true, nil, true
+	}
+
+	var details []string
+	for _, setting := range publicAccessSettings {
+		if enabled, _ := live[setting].(bool); !enabled {
+			details = append(details, fmt.Sprintf("Public access block setting '%s' is off", setting))
+		}
+	}
+	if public, _ := live["policy_public"].(bool); public {
+		details = append(details, "Bucket policy grants public access")
+	}
+	return len(details) == 0, details, true
+}
+
+// notPubliclyAccessible answers public_access.blocked for a deployed database
+func notPubliclyAccessible(live map[string]interface{}, expected interface{}) (bool, []string, bool) {
+	public, ok := live["publicly_accessible"].(bool)
+	if !ok {
+		return false, nil, false
+	}
+	if required, _ := expected.(bool); !required || !public {
+		return true, nil, true
+	}
+	return false, []string{"DB instance is publicly accessible"}, true
+}
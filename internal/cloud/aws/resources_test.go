@@ -0,0 +1,523 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vijayaxai/terraship/internal/cloud"
+)
+
+// standInResponses are the stand-in's responses by EC2 or RDS action, JSON
+// protocol target or REST path
+var standInResponses = map[string]string{
+	"DescribeVolumes": `<DescribeVolumesResponse xmlns="http://ec2.amazonaws.com/doc/2016-11-15/"><volumeSet><item>
+		<volumeId>vol-0a1b2c3d</volumeId><size>100</size><availabilityZone>us-east-1a</availabilityZone><status>in-use</status>
+		<volumeType>gp3</volumeType><iops>3000</iops><throughput>125</throughput><encrypted>false</encrypted>
+		<tagSet><item><key>Environment</key><value>production</value></item></tagSet></item></volumeSet></DescribeVolumesResponse>`,
+	"DescribeSecurityGroups": `<DescribeSecurityGroupsResponse xmlns="http://ec2.amazonaws.com/doc/2016-11-15/"><securityGroupInfo><item>
+		<groupId>sg-0a1b2c3d</groupId><groupName>web</groupName><groupDescription>Web servers</groupDescription><vpcId>vpc-0a1b2c3d</vpcId>
+		<ipPermissions><item><ipProtocol>tcp</ipProtocol><fromPort>443</fromPort><toPort>443</toPort>
+		<ipRanges><item><cidrIp>0.0.0.0/0</cidrIp></item></ipRanges></item>
+		<item><ipProtocol>tcp</ipProtocol><fromPort>22</fromPort><toPort>22</toPort>
+		<ipRanges><item><cidrIp>0.0.0.0/0</cidrIp></item></ipRanges></item></ipPermissions>
+		<ipPermissionsEgress/><tagSet><item><key>Environment</key><value>production</value></item></tagSet></item></securityGroupInfo></DescribeSecurityGroupsResponse>`,
+	"DescribeVpcs": `<DescribeVpcsResponse xmlns="http://ec2.amazonaws.com/doc/2016-11-15/"><vpcSet><item>
+		<vpcId>vpc-0a1b2c3d</vpcId><state>available</state><cidrBlock>10.0.0.0/16</cidrBlock>
+		<instanceTenancy>default</instanceTenancy><isDefault>false</isDefault></item></vpcSet></DescribeVpcsResponse>`,
+	"DescribeVpcAttribute": `<DescribeVpcAttributeResponse xmlns="http://ec2.amazonaws.com/doc/2016-11-15/"><vpcId>vpc-0a1b2c3d</vpcId>
+		<enableDnsSupport><value>true</value></enableDnsSupport><enableDnsHostnames><value>false</value></enableDnsHostnames></DescribeVpcAttributeResponse>`,
+	"DescribeSubnets": `<DescribeSubnetsResponse xmlns="http://ec2.amazonaws.com/doc/2016-11-15/"><subnetSet><item>
+		<subnetId>subnet-0a1b2c3d</subnetId><state>available</state><vpcId>vpc-0a1b2c3d</vpcId><cidrBlock>10.0.1.0/24</cidrBlock>
+		<availabilityZone>us-east-1a</availabilityZone><mapPublicIpOnLaunch>true</mapPublicIpOnLaunch></item></subnetSet></DescribeSubnetsResponse>`,
+	"DescribeDBInstances": `<DescribeDBInstancesResponse xmlns="http://rds.amazonaws.com/doc/2014-10-31/"><DescribeDBInstancesResult><DBInstances><DBInstance>
+		<DBInstanceIdentifier>orders</DBInstanceIdentifier><DBInstanceClass>db.t3.large</DBInstanceClass><DBInstanceStatus>available</DBInstanceStatus>
+		<Engine>postgres</Engine><EngineVersion>15.4</EngineVersion><AllocatedStorage>100</AllocatedStorage><StorageType>gp3</StorageType>
+		<StorageEncrypted>true</StorageEncrypted><MultiAZ>false</MultiAZ><PubliclyAccessible>false</PubliclyAccessible>
+		<BackupRetentionPeriod>7</BackupRetentionPeriod><DeletionProtection>true</DeletionProtection>
+		<TagList><Tag><Key>Environment</Key><Value>production</Value></Tag></TagList>
+		</DBInstance></DBInstances></DescribeDBInstancesResult></DescribeDBInstancesResponse>`,
+	"/2015-03-31/functions/thumbnailer": `{"Configuration": {"FunctionArn": "arn:aws:lambda:us-east-1:123456789012:function:thumbnailer",
+		"Runtime": "python3.12", "Handler": "app.handler", "Role": "arn:aws:iam::123456789012:role/thumbnailer", "MemorySize": 512,
+		"Timeout": 30, "PackageType": "Zip", "Architectures": ["arm64"], "State": "Active"}, "Tags": {"Environment": "production"}}`,
+	"TrentService.DescribeKey": `{"KeyMetadata": {"KeyId": "1234abcd-12ab-34cd-56ef-1234567890ab", "Arn": "arn:aws:kms:us-east-1:123456789012:key/1234abcd-12ab-34cd-56ef-1234567890ab",
+		"Description": "Orders", "Enabled": true, "KeyState": "Enabled", "KeyUsage": "ENCRYPT_DECRYPT", "KeySpec": "SYMMETRIC_DEFAULT", "MultiRegion": false}}`,
+	"TrentService.GetKeyRotationStatus": `{"KeyRotationEnabled": false}`,
+	"TrentService.ListResourceTags":     `{"Tags": [{"TagKey": "Environment", "TagValue": "production"}]}`,
+	"DynamoDB_20120810.DescribeTable": `{"Table": {"TableName": "sessions", "TableArn": "arn:aws:dynamodb:us-east-1:123456789012:table/sessions",
+		"TableStatus": "ACTIVE", "KeySchema": [{"AttributeName": "id", "KeyType": "HASH"}, {"AttributeName": "expires", "KeyType": "RANGE"}],
+		"BillingModeSummary": {"BillingMode": "PAY_PER_REQUEST"}, "ProvisionedThroughput": {"ReadCapacityUnits": 0, "WriteCapacityUnits": 0},
+		"SSEDescription": {"Status": "ENABLED", "SSEType": "KMS"}}}`,
+	"DynamoDB_20120810.DescribeContinuousBackups": `{"ContinuousBackupsDescription": {"PointInTimeRecoveryDescription": {"PointInTimeRecoveryStatus": "DISABLED"}}}`,
+	"DynamoDB_20120810.ListTagsOfResource":        `{"Tags": [{"Key": "Environment", "Value": "production"}]}`,
+	"CloudTrail_20131101.DescribeTrails": `{"trailList": [{"Name": "audit",
+		"TrailARN": "arn:aws:cloudtrail:us-east-1:123456789012:trail/audit", "S3BucketName": "acme-audit-logs",
+		"IncludeGlobalServiceEvents": true, "IsMultiRegionTrail": true, "LogFileValidationEnabled": false}]}`,
+	"CloudTrail_20131101.GetTrailStatus": `{"IsLogging": true}`,
+	"CloudTrail_20131101.ListTags": `{"ResourceTagList": [{"ResourceId": "arn:aws:cloudtrail:us-east-1:123456789012:trail/audit",
+		"TagsList": [{"Key": "Environment", "Value": "production"}]}]}`,
+}
+
+// standInNotFound are the error codes the stand-in answers requests for
+// resources named "missing" with
+var standInNotFound = map[string]string{
+	"DescribeVolumes":                 "InvalidVolume.NotFound",
+	"DescribeDBInstances":             "DBInstanceNotFound",
+	"/2015-03-31/functions/missing":   "ResourceNotFoundException",
+	"TrentService.DescribeKey":        "NotFoundException",
+	"DynamoDB_20120810.DescribeTable": "ResourceNotFoundException",
+	"DescribeSecurityGroups":          "InvalidGroup.NotFound",
+	"DescribeVpcs":                    "InvalidVpcID.NotFound",
+	"DescribeSubnets":                 "InvalidSubnetID.NotFound",
+}
+
+// standInAWS serves AWS APIs from standInResponses, recording the
+// credential scope each request was signed for
+type standInAWS struct {
+	mu     sync.Mutex
+	scopes []string
+}
+
+func (s *standInAWS) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	form, _ := url.ParseQuery(string(body))
+
+	s.mu.Lock()
+	if _, credential, ok := strings.Cut(r.Header.Get("Authorization"), "Credential="); ok {
+		parts := strings.Split(strings.Split(credential, ",")[0], "/")
+		s.scopes = append(s.scopes, strings.Join(parts[2:4], "/"))
+	}
+	s.mu.Unlock()
+
+	key := r.URL.Path
+	switch {
+	case r.Header.Get("X-Amz-Target") != "":
+		key = r.Header.Get("X-Amz-Target")
+	case form.Get("Action") != "":
+		key = form.Get("Action")
+	}
+
+	if code, ok := standInNotFound[key]; ok && strings.Contains(string(body)+r.URL.Path, "missing") {
+		switch {
+		case strings.HasPrefix(key, "/"):
+			w.Header().Set("X-Amzn-ErrorType", code)
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprintf(w, `{"Type": "User", "Message": "Function not found"}`)
+		case r.Header.Get("X-Amz-Target") != "":
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprintf(w, `{"__type": "com.amazonaws.service#%s", "message": "not found"}`, code)
+		case form.Get("Version") == "2014-10-31":
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprintf(w, `<ErrorResponse><Error><Type>Sender</Type><Code>%s</Code><Message>not found</Message></Error></ErrorResponse>`, code)
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprintf(w, `<Response><Errors><Error><Code>%s</Code><Message>not found</Message></Error></Errors></Response>`, code)
+		}
+		return
+	}
+
+	response, ok := standInResponses[key]
+	if key == "DescribeInstances" {
+		response, ok = describeStandInInstances(form), true
+	}
+	if !ok {
+		http.Error(w, "unexpected request "+key, http.StatusNotImplemented)
+		return
+	}
+	if key == "CloudTrail_20131101.DescribeTrails" && strings.Contains(string(body), "missing") {
+		// Unknown trails are left out of the trail list
+		response = `{"trailList": []}`
+	}
+	if strings.HasPrefix(response, "<") {
+		w.Header().Set("Content-Type", "text/xml")
+	} else {
+		// DynamoDB clients check the CRC32 checksum of every response
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("X-Amz-Crc32", strconv.FormatUint(uint64(crc32.ChecksumIEEE([]byte(response))), 10))
+	}
+	_, _ = io.WriteString(w, response)
+}
+
+// standInInstances are the stand-in's EC2 instances by DescribeInstances page
+var standInInstances = [][]struct{ id, state string }{
+	{{"i-0running", "running"}, {"i-0terminated", "terminated"}},
+	{{"i-0stopped", "stopped"}, {"i-0shutting", "shutting-down"}},
+}
+
+// describeStandInInstances answers a DescribeInstances request with the page
+// of standInInstances its NextToken names, filtered by instance-state-name
+func describeStandInInstances(form url.Values) string {
+	states := map[string]bool{}
+	for i := 1; form.Get(fmt.Sprintf("Filter.%d.Name", i)) != ""; i++ {
+		if form.Get(fmt.Sprintf("Filter.%d.Name", i)) != "instance-state-name" {
+			continue
+		}
+		for j := 1; form.Get(fmt.Sprintf("Filter.%d.Value.%d", i, j)) != ""; j++ {
+			states[form.Get(fmt.Sprintf("Filter.%d.Value.%d", i, j))] = true
+		}
+	}
+
+	page, _ := strconv.Atoi(strings.TrimPrefix(form.Get("NextToken"), "page-"))
+	var items strings.Builder
+	for _, instance := range standInInstances[page] {
+		if len(states) == 0 || states[instance.state] {
+			fmt.Fprintf(&items, `<item><instanceId>%s</instanceId><instanceState><name>%s</name></instanceState></item>`, instance.id, instance.state)
+		}
+	}
+	nextToken := ""
+	if page+1 < len(standInInstances) {
+		nextToken = fmt.Sprintf("<nextToken>page-%d</nextToken>", page+1)
+	}
+	return fmt.Sprintf(`<DescribeInstancesResponse xmlns="http://ec2.amazonaws.com/doc/2016-11-15/"><reservationSet><item>
+		<reservationId>r-0a1b2c3d</reservationId><instancesSet>%s</instancesSet></item></reservationSet>%s</DescribeInstancesResponse>`, items.String(), nextToken)
+}
+
+// newStandInAdapter returns an adapter calling every service at a stand-in
+func newStandInAdapter(t *testing.T, standIn http.Handler) *Adapter {
+	t.Helper()
+	server := httptest.NewServer(standIn)
+	t.Cleanup(server.Close)
+
+	t.Setenv("AWS_PROFILE", "")
+	t.Setenv("AWS_CONFIG_FILE", "testdata/no-such-config")
+	t.Setenv("AWS_SHARED_CREDENTIALS_FILE", "testdata/no-such-credentials")
+	t.Setenv("AWS_ENDPOINT_URL", "")
+	t.Setenv("AWS_ACCESS_KEY_ID", "test")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "test")
+
+	adapter := NewAdapter()
+	require.NoError(t, adapter.Initialize(context.Background(), cloud.CloudConfig{
+		Provider:    cloud.ProviderAWS,
+		AWSRegion:   "us-east-1",
+		EndpointURL: server.URL,
+	}))
+	t.Cleanup(func() { assert.NoError(t, adapter.Close()) })
+
+	return adapter
+}
+
+func TestAdapter_ResourceCoverage(t *testing.T) {
+	tests := []struct {
+		resourceType string
+		resourceID   string
+		planned      map[string]interface{}
+		state        string
+		properties   map[string]interface{}
+		drift        []string
+	}{
+		{
+			resourceType: "aws_ebs_volume",
+			resourceID:   "vol-0a1b2c3d",
+			planned:      map[string]interface{}{"size": float64(100), "type": "gp3", "encrypted": true},
+			state:        "in-use",
+			properties:   map[string]interface{}{"availability_zone": "us-east-1a", "size": int32(100), "iops": int32(3000)},
+			drift:        []string{"Attribute 'encrypted' differs: planned=true, actual=false"},
+		},
+		{
+			resourceType: "aws_security_group",
+			resourceID:   "sg-0a1b2c3d",
+			planned: map[string]interface{}{"name": "web", "ingress": []interface{}{
+				map[string]interface{}{"from_port": float64(443), "cidr_blocks": []interface{}{"0.0.0.0/0"}},
+			}},
+			properties: map[string]interface{}{"vpc_id": "vpc-0a1b2c3d", "ingress_rules": 2},
+		},
+		{
+			resourceType: "aws_vpc",
+			resourceID:   "vpc-0a1b2c3d",
+			planned:      map[string]interface{}{"cidr_block": "10.0.0.0/16", "enable_dns_hostnames": true},
+			state:        "available",
+			properties:   map[string]interface{}{"enable_dns_support": true, "instance_tenancy": "default"},
+			drift:        []string{"Attribute 'enable_dns_hostnames' differs: planned=true, actual=false"},
+		},
+		{
+			resourceType: "aws_subnet",
+			resourceID:   "subnet-0a1b2c3d",
+			planned:      map[string]interface{}{"cidr_block": "10.0.1.0/24", "map_public_ip_on_launch": false},
+			state:        "available",
+			drift:        []string{"Attribute 'map_public_ip_on_launch' differs: planned=false, actual=true"},
+		},
+		{
+			resourceType: "aws_db_instance",
+			resourceID:   "orders",
+			planned:      map[string]interface{}{"instance_class": "db.t3.micro", "engine_version": "15", "storage_encrypted": true},
+			state:        "available",
+			properties:   map[string]interface{}{"engine": "postgres", "deletion_protection": true},
+			drift:        []string{"Attribute 'instance_class' differs: planned=db.t3.micro, actual=db.t3.large"},
+		},
+		{
+			resourceType: "aws_lambda_function",
+			resourceID:   "thumbnailer",
+			planned:      map[string]interface{}{"runtime": "python3.12", "memory_size": float64(256), "architectures": []interface{}{"arm64"}},
+			state:        "Active",
+			drift:        []string{"Attribute 'memory_size' differs: planned=256, actual=512"},
+		},
+		{
+			resourceType: "aws_kms_key",
+			resourceID:   "1234abcd-12ab-34cd-56ef-1234567890ab",
+			planned:      map[string]interface{}{"is_enabled": true, "enable_key_rotation": true},
+			state:        "Enabled",
+			properties:   map[string]interface{}{"key_usage": "ENCRYPT_DECRYPT"},
+			drift:        []string{"Attribute 'enable_key_rotation' differs: planned=true, actual=false"},
+		},
+		{
+			resourceType: "aws_dynamodb_table",
+			resourceID:   "sessions",
+			planned: map[string]interface{}{
+				"billing_mode":           "PAY_PER_REQUEST",
+				"hash_key":               "id",
+				"point_in_time_recovery": []interface{}{map[string]interface{}{"enabled": true}},
+			},
+			state:      "ACTIVE",
+			properties: map[string]interface{}{"range_key": "expires", "encryption_enabled": true},
+			drift:      []string{"Attribute 'point_in_time_recovery[0].enabled' differs: planned=true, actual=false"},
+		},
+		{
+			resourceType: "aws_cloudtrail",
+			resourceID:   "audit",
+			planned:      map[string]interface{}{"s3_bucket_name": "acme-audit-logs", "enable_log_file_validation": true},
+			state:        "logging",
+			properties:   map[string]interface{}{"is_multi_region_trail": true, "enable_logging": true},
+			drift:        []string{"Attribute 'enable_log_file_validation' differs: planned=true, actual=false"},
+		},
+	}
+
+	ctx := context.Background()
+	adapter := newStandInAdapter(t, &standInAWS{})
+	for _, tt := range tests {
+		t.Run(tt.resourceType, func(t *testing.T) {
+			status, err := adapter.DetectDrift(ctx, tt.planned, tt.resourceType, tt.resourceID)
+			require.NoError(t, err)
+			assert.True(t, status.Exists)
+			assert.Equal(t, tt.state, status.State)
+			for property, value := range tt.properties {
+				assert.Equal(t, value, status.Properties[property], property)
+			}
+			if tt.resourceType != "aws_vpc" && tt.resourceType != "aws_subnet" {
+				assert.Equal(t, "production", status.Tags["Environment"])
+			}
+			assert.Equal(t, tt.drift, status.DriftDetails)
+		})
+	}
+}
+
+func TestAdapter_ResourceCoverageMissing(t *testing.T) {
+	ctx := context.Background()
+	standIn := &standInAWS{}
+	adapter := newStandInAdapter(t, standIn)
+
+	for _, resourceType := range []string{"aws_ebs_volume", "aws_db_instance", "aws_lambda_function", "aws_kms_key", "aws_dynamodb_table", "aws_cloudtrail"} {
+		status, err := adapter.DetectDrift(ctx, nil, resourceType, "missing")
+		require.NoError(t, err, resourceType)
+		assert.False(t, status.Exists, resourceType)
+		assert.Equal(t, []string{"Resource does not exist in AWS"}, status.DriftDetails, resourceType)
+	}
+
+	// Requests are signed for the service they call
+	assert.Contains(t, standIn.scopes, "us-east-1/kms")
+	assert.Contains(t, standIn.scopes, "us-east-1/rds")
+	assert.Contains(t, standIn.scopes, "us-east-1/lambda")
+}
+
+// standInFailure answers one EC2 action or JSON protocol target with an error
+// and passes every other request to the stand-in
+type standInFailure struct {
+	standIn http.Handler
+	key     string
+	code    string
+}
+
+func (s standInFailure) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	form, _ := url.ParseQuery(string(body))
+	r.Body = io.NopCloser(strings.NewReader(string(body)))
+
+	switch s.key {
+	case r.Header.Get("X-Amz-Target"):
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprintf(w, `{"__type": "com.amazonaws.service#%s", "message": "failed"}`, s.code)
+	case form.Get("Action"):
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprintf(w, `<Response><Errors><Error><Code>%s</Code><Message>failed</Message></Error></Errors></Response>`, s.code)
+	default:
+		s.standIn.ServeHTTP(w, r)
+	}
+}
+
+func TestAdapter_ResourceCoverageErrors(t *testing.T) {
+	ctx := context.Background()
+
+	// A failed lookup of a property fails the read instead of answering it wrongly
+	failures := []struct {
+		resourceType string
+		resourceID   string
+		key          string
+		err          string
+	}{
+		{"aws_vpc", "vpc-0a1b2c3d", "DescribeVpcAttribute", "failed to describe VPC attribute"},
+		{"aws_kms_key", "1234abcd-12ab-34cd-56ef-1234567890ab", "TrentService.ListResourceTags", "failed to list KMS key tags"},
+		{"aws_dynamodb_table", "sessions", "DynamoDB_20120810.DescribeContinuousBackups", "failed to describe DynamoDB continuous backups"},
+		{"aws_dynamodb_table", "sessions", "DynamoDB_20120810.ListTagsOfResource", "failed to list DynamoDB table tags"},
+		{"aws_cloudtrail", "audit", "CloudTrail_20131101.GetTrailStatus", "failed to get CloudTrail trail status"},
+		{"aws_cloudtrail", "audit", "CloudTrail_20131101.ListTags", "failed to list CloudTrail trail tags"},
+	}
+	for _, failure := range failures {
+		adapter := newStandInAdapter(t, standInFailure{standIn: &standInAWS{}, key: failure.key, code: "AccessDeniedException"})
+		_, err := adapter.GetResourceStatus(ctx, failure.resourceType, failure.resourceID)
+		assert.ErrorContains(t, err, failure.err, failure.key)
+	}
+
+	// Keys that do not rotate leave their rotation unanswered
+	adapter := newStandInAdapter(t, standInFailure{standIn: &standInAWS{}, key: "TrentService.GetKeyRotationStatus", code: "UnsupportedOperationException"})
+	status, err := adapter.GetResourceStatus(ctx, "aws_kms_key", "1234abcd-12ab-34cd-56ef-1234567890ab")
+	require.NoError(t, err)
+	assert.NotContains(t, status.Properties, "enable_key_rotation")
+	assert.Equal(t, "production", status.Tags["Environment"])
+}
+
+// standInBucket is an S3 bucket served by standInS3: its public access block
+// and bucket policy status, which are not configured when empty
+type standInBucket struct {
+	publicAccessBlock string
+	policyStatus      string
+}
+
+// standInS3 serves the buckets, by name, and their configuration
+type standInS3 map[string]standInBucket
+
+func (s standInS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	bucket, ok := s[strings.Trim(r.URL.Path, "/")]
+	query := r.URL.Query()
+	notFound := func(code string) {
+		w.Header().Set("Content-Type", "application/xml")
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprintf(w, `<Error><Code>%s</Code><Message>not found</Message></Error>`, code)
+	}
+
+	switch {
+	case !ok:
+		notFound("NoSuchBucket")
+	case query.Has("publicAccessBlock") && bucket.publicAccessBlock == "":
+		notFound("NoSuchPublicAccessBlockConfiguration")
+	case query.Has("publicAccessBlock"):
+		_, _ = io.WriteString(w, `<PublicAccessBlockConfiguration>`+bucket.publicAccessBlock+`</PublicAccessBlockConfiguration>`)
+	case query.Has("policyStatus") && bucket.policyStatus == "":
+		notFound("NoSuchBucketPolicy")
+	case query.Has("policyStatus"):
+		_, _ = io.WriteString(w, `<PolicyStatus><IsPublic>`+bucket.policyStatus+`</IsPublic></PolicyStatus>`)
+	case query.Has("versioning"):
+		_, _ = io.WriteString(w, `<VersioningConfiguration><Status>Enabled</Status></VersioningConfiguration>`)
+	case query.Has("encryption"):
+		notFound("ServerSideEncryptionConfigurationNotFoundError")
+	case query.Has("tagging"):
+		notFound("NoSuchTagSet")
+	}
+}
+
+func TestAdapter_ValidateResourceCompliance(t *testing.T) {
+	blocked := `<BlockPublicAcls>true</BlockPublicAcls><IgnorePublicAcls>true</IgnorePublicAcls>
+		<BlockPublicPolicy>true</BlockPublicPolicy><RestrictPublicBuckets>true</RestrictPublicBuckets>`
+	adapter := newStandInAdapter(t, standInS3{
+		"acme-private": {publicAccessBlock: blocked},
+		"acme-website": {publicAccessBlock: `<BlockPublicAcls>true</BlockPublicAcls><IgnorePublicAcls>true</IgnorePublicAcls>`, policyStatus: "true"},
+		"acme-legacy":  {},
+	})
+
+	ctx := context.Background()
+	rules := []cloud.ValidationRule{
+		{Name: "s3-public-access-blocked", Severity: "error", Conditions: map[string]interface{}{"public_access.blocked": true}},
+		{Name: "s3-versioning", Severity: "warning", Conditions: map[string]interface{}{"versioning.enabled": true}},
+		{Name: "s3-block-public-acls", Severity: "error", Conditions: map[string]interface{}{"block_public_acls": true}},
+		{Name: "s3-required-tags", Severity: "error", Conditions: map[string]interface{}{"tags.required": []interface{}{"Owner"}}},
+	}
+
+	results, err := adapter.ValidateResourceCompliance(ctx, "aws_s3_bucket", "acme-private", rules)
+	require.NoError(t, err)
+	require.Len(t, results, 3, "tags are checked against the plan only")
+	for _, result := range results {
+		assert.True(t, result.Passed, result.RuleName)
+		assert.Equal(t, cloud.SourceLive, result.Source)
+	}
+
+	results, err = adapter.ValidateResourceCompliance(ctx, "aws_s3_bucket", "acme-website", rules)
+	require.NoError(t, err)
+	assert.False(t, results[0].Passed)
+	assert.Equal(t, []string{
+		"Public access block setting 'block_public_policy' is off",
+		"Public access block setting 'restrict_public_buckets' is off",
+		"Bucket policy grants public access",
+	}, results[0].Details)
+
+	results, err = adapter.ValidateResourceCompliance(ctx, "aws_s3_bucket", "acme-legacy", rules)
+	require.NoError(t, err)
+	assert.False(t, results[0].Passed)
+	assert.Len(t, results[0].Details, 4)
+	assert.Equal(t, []string{"Deployed attribute 'block_public_acls' has value 'false', expected 'true'"}, results[2].Details)
+
+	results, err = adapter.ValidateResourceCompliance(ctx, "aws_s3_bucket", "missing", rules)
+	require.NoError(t, err)
+	assert.Empty(t, results)
+
+	// The public access block resource is checked attribute by attribute
+	results, err = adapter.ValidateResourceCompliance(ctx, "aws_s3_bucket_public_access_block", "acme-website", []cloud.ValidationRule{
+		{Name: "block-public-policy", Severity: "error", Conditions: map[string]interface{}{"block_public_policy": true, "block_public_acls": true}},
+	})
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, []string{"Deployed attribute 'block_public_policy' has value 'false', expected 'true'"}, results[0].Details)
+
+	status, err := adapter.GetResourceStatus(ctx, "aws_s3_bucket_public_access_block", "acme-legacy")
+	require.NoError(t, err)
+	assert.False(t, status.Exists)
+}
+
+func TestAdapter_CheckResource(t *testing.T) {
+	var requests atomic.Int32
+	standIn := standInS3{"acme-website": {publicAccessBlock: `<BlockPublicAcls>true</BlockPublicAcls>`}}
+	adapter := newStandInAdapter(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		standIn.ServeHTTP(w, r)
+	}))
+
+	ctx := context.Background()
+	rules := []cloud.ValidationRule{
+		{Name: "s3-block-public-acls", Severity: "error", Conditions: map[string]interface{}{"block_public_acls": true}},
+		{Name: "s3-encryption", Severity: "error", Conditions: map[string]interface{}{"encryption.enabled": true}},
+	}
+	planned := map[string]interface{}{"versioning": []interface{}{map[string]interface{}{"enabled": false}}}
+
+	// The bucket is read once, with its exposure, for the rules and for drift
+	status, results, err := adapter.CheckResource(ctx, planned, "aws_s3_bucket", "acme-website", rules)
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+	assert.True(t, results[0].Passed, results[0].RuleName)
+	assert.False(t, results[1].Passed, results[1].RuleName)
+	assert.Equal(t, []string{"Attribute 'versioning[0].enabled' differs: planned=false, actual=true"}, status.DriftDetails)
+	assert.Equal(t, int32(6), requests.Load(), "bucket, tagging, encryption, versioning, public access block and policy status")
+
+	// Without live rules the bucket is read for drift only
+	requests.Store(0)
+	_, results, err = adapter.CheckResource(ctx, planned, "aws_s3_bucket", "acme-website", nil)
+	require.NoError(t, err)
+	assert.Empty(t, results)
+	assert.Equal(t, int32(4), requests.Load())
+}
+
+func TestAdapter_ListInstances(t *testing.T) {
+	adapter := newStandInAdapter(t, &standInAWS{})
+
+	// Every page is listed; terminated and shutting-down instances are not
+	instanceIDs, err := adapter.ListResources(context.Background(), "aws_instance")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"i-0running", "i-0stopped"}, instanceIDs)
+}
@@ -4,24 +4,51 @@ package azure
 import (
 	"context"
 	"fmt"
+	"net/http"
+	"net/url"
 	"os"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	azcloud "github.com/Azure/azure-sdk-for-go/sdk/azcore/cloud"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
 	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute/v5"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/containerservice/armcontainerservice/v4"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/keyvault/armkeyvault"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork/v4"
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armresources"
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/storage/armstorage"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/bloberror"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/service"
 	"github.com/vijayaxai/terraship/internal/cloud"
+	"github.com/vijayaxai/terraship/internal/cloud/cassette"
 )
 
 // Adapter implements cloud.Adapter for Azure
 type Adapter struct {
-	cred            azcore.TokenCredential
-	subscriptionID  string
-	resourcesClient *armresources.Client
-	computeClient   *armcompute.VirtualMachinesClient
-	storageClient   *armstorage.AccountsClient
+	cred                  azcore.TokenCredential
+	subscriptionID        string
+	resourcesClient       *armresources.Client
+	groupsClient          *armresources.ResourceGroupsClient
+	computeClient         *armcompute.VirtualMachinesClient
+	disksClient           *armcompute.DisksClient
+	storageClient         *armstorage.AccountsClient
+	keyVaultClient        *armkeyvault.VaultsClient
+	securityGroupsClient  *armnetwork.SecurityGroupsClient
+	virtualNetworksClient *armnetwork.VirtualNetworksClient
+	clustersClient        *armcontainerservice.ManagedClustersClient
+	recorder              *cassette.Recorder
+	httpClient            *http.Client                 // rate limited or recorded client; nil keeps the SDK's own
+	blobEndpoint          string                       // blob service override, e.g. Azurite
+	blobCred              *service.SharedKeyCredential // shared key of AZURE_STORAGE_ACCOUNT, if set
+}
+
+func init() {
+	cloud.Register(cloud.ProviderAzure, func() cloud.Adapter { return NewAdapter() }, "hashicorp/azurerm")
 }
 
 // NewAdapter creates a new Azure adapter
@@ -38,17 +65,46 @@ func (a *Adapter) Name() cloud.Provider {
 func (a *Adapter) Initialize(ctx context.Context, cloudConfig cloud.CloudConfig) error {
 	var err error
 
+	// Blob storage emulators like Azurite need no subscription
+	a.blobEndpoint = cloudConfig.Endpoint("blob")
+
 	// Get subscription ID
 	a.subscriptionID = cloudConfig.AzureSubscriptionID
 	if a.subscriptionID == "" {
 		a.subscriptionID = os.Getenv("AZURE_SUBSCRIPTION_ID")
 	}
-	if a.subscriptionID == "" {
+	if a.subscriptionID == "" && a.blobEndpoint == "" {
 		return fmt.Errorf("Azure subscription ID is required")
 	}
 
+	// Rate limit, record or replay API traffic
+	transport, recorder, err := cloudConfig.Transport(nil)
+	if err != nil {
+		return err
+	}
+	a.recorder = recorder
+	if transport != nil {
+		a.httpClient = &http.Client{Transport: transport}
+	}
+	clientOptions := &arm.ClientOptions{}
+	if a.httpClient != nil {
+		clientOptions.Transport = a.httpClient
+	}
+
+	// Storage accounts are accessed with their shared key when it is set,
+	// like emulators require
+	if account, key := os.Getenv("AZURE_STORAGE_ACCOUNT"), os.Getenv("AZURE_STORAGE_KEY"); account != "" && key != "" {
+		a.blobCred, err = service.NewSharedKeyCredential(account, key)
+		if err != nil {
+			return fmt.Errorf("invalid Azure storage shared key: %w", err)
+		}
+	}
+
 	// Create credential
-	if cloudConfig.AzureClientID != "" && cloudConfig.AzureClientSecret != "" && cloudConfig.AzureTenantID != "" {
+	if a.recorder.Replaying() {
+		// Replays need no real credentials
+		a.cred = placeholderCredential{}
+	} else if cloudConfig.AzureClientID != "" && cloudConfig.AzureClientSecret != "" && cloudConfig.AzureTenantID != "" {
 		// Use client secret credential
 		a.cred, err = azidentity.NewClientSecretCredential(
 			cloudConfig.AzureTenantID,
@@ -65,22 +121,72 @@ func (a *Adapter) Initialize(ctx context.Context, cloudConfig cloud.CloudConfig)
 		return fmt.Errorf("failed to create Azure credential: %w", err)
 	}
 
+	// Point the resource manager clients at an endpoint override. Plain HTTP
+	// endpoints are emulators, which get a placeholder token rather than
+	// real credentials.
+	armCred := a.cred
+	if endpoint := cloudConfig.Endpoint("resourcemanager"); endpoint != "" {
+		clientOptions.Cloud = azcloud.Configuration{
+			ActiveDirectoryAuthorityHost: azcloud.AzurePublic.ActiveDirectoryAuthorityHost,
+			Services: map[azcloud.ServiceName]azcloud.ServiceConfiguration{
+				azcloud.ResourceManager: {
+					Endpoint: endpoint,
+					Audience: azcloud.AzurePublic.Services[azcloud.ResourceManager].Audience,
+				},
+			},
+		}
+		if strings.HasPrefix(endpoint, "http://") {
+			armCred = placeholderCredential{}
+			clientOptions.InsecureAllowCredentialWithHTTP = true
+		}
+	}
+
 	// Initialize clients
-	a.resourcesClient, err = armresources.NewClient(a.subscriptionID, a.cred, nil)
+	a.resourcesClient, err = armresources.NewClient(a.subscriptionID, armCred, clientOptions)
 	if err != nil {
 		return fmt.Errorf("failed to create resources client: %w", err)
 	}
 
-	a.computeClient, err = armcompute.NewVirtualMachinesClient(a.subscriptionID, a.cred, nil)
+	a.computeClient, err = armcompute.NewVirtualMachinesClient(a.subscriptionID, armCred, clientOptions)
 	if err != nil {
 		return fmt.Errorf("failed to create compute client: %w", err)
 	}
 
-	a.storageClient, err = armstorage.NewAccountsClient(a.subscriptionID, a.cred, nil)
+	a.storageClient, err = armstorage.NewAccountsClient(a.subscriptionID, armCred, clientOptions)
 	if err != nil {
 		return fmt.Errorf("failed to create storage client: %w", err)
 	}
 
+	a.groupsClient, err = armresources.NewResourceGroupsClient(a.subscriptionID, armCred, clientOptions)
+	if err != nil {
+		return fmt.Errorf("failed to create resource groups client: %w", err)
+	}
+
+	a.disksClient, err = armcompute.NewDisksClient(a.subscriptionID, armCred, clientOptions)
+	if err != nil {
+		return fmt.Errorf("failed to create disks client: %w", err)
+	}
+
+	a.keyVaultClient, err = armkeyvault.NewVaultsClient(a.subscriptionID, armCred, clientOptions)
+	if err != nil {
+		return fmt.Errorf("failed to create key vault client: %w", err)
+	}
+
+	a.securityGroupsClient, err = armnetwork.NewSecurityGroupsClient(a.subscriptionID, armCred, clientOptions)
+	if err != nil {
+		return fmt.Errorf("failed to create network security groups client: %w", err)
+	}
+
+	a.virtualNetworksClient, err = armnetwork.NewVirtualNetworksClient(a.subscriptionID, armCred, clientOptions)
+	if err != nil {
+		return fmt.Errorf("failed to create virtual networks client: %w", err)
+	}
+
+	a.clustersClient, err = armcontainerservice.NewManagedClustersClient(a.subscriptionID, armCred, clientOptions)
+	if err != nil {
+		return fmt.Errorf("failed to create managed clusters client: %w", err)
+	}
+
 	return nil
 }
 
@@ -105,8 +211,21 @@ func (a *Adapter) DetectProvider(ctx context.Context) (bool, float64, error) {
 	return confidence > 0.5, confidence, nil
 }
 
-// ValidateCredentials checks if Azure credentials are valid
+// ValidateCredentials checks if Azure credentials are valid. With a blob
+// endpoint override the blob service is checked instead, since storage
+// emulators have no resource manager API.
 func (a *Adapter) ValidateCredentials(ctx context.Context) error {
+	if a.blobEndpoint != "" {
+		client, err := a.blobService(a.blobEndpoint)
+		if err != nil {
+			return err
+		}
+		if _, err := client.NewListContainersPager(nil).NextPage(ctx); err != nil {
+			return fmt.Errorf("Azure blob endpoint validation failed: %w", err)
+		}
+		return nil
+	}
+
 	// Try to list resource groups as a lightweight validation
 	pager := a.resourcesClient.NewListPager(nil)
 	if !pager.More() {
@@ -134,8 +253,22 @@ func (a *Adapter) GetResourceStatus(ctx context.Context, resourceType, resourceI
 		return a.getVMStatus(ctx, resourceID)
 	case strings.HasPrefix(resourceType, "azurerm_storage_account"):
 		return a.getStorageAccountStatus(ctx, resourceID)
+	case strings.HasPrefix(resourceType, "azurerm_storage_container"):
+		return a.getStorageContainerStatus(ctx, resourceID)
 	case strings.HasPrefix(resourceType, "azurerm_resource_group"):
 		return a.getResourceGroupStatus(ctx, resourceID)
+	case resourceType == "azurerm_key_vault":
+		return a.getKeyVaultStatus(ctx, resourceID)
+	case resourceType == "azurerm_sql_server", resourceType == "azurerm_mssql_server":
+		return a.getSQLServerStatus(ctx, resourceType, resourceID)
+	case resourceType == "azurerm_network_security_group":
+		return a.getNetworkSecurityGroupStatus(ctx, resourceID)
+	case resourceType == "azurerm_virtual_network":
+		return a.getVirtualNetworkStatus(ctx, resourceID)
+	case resourceType == "azurerm_managed_disk":
+		return a.getManagedDiskStatus(ctx, resourceID)
+	case resourceType == "azurerm_kubernetes_cluster":
+		return a.getKubernetesClusterStatus(ctx, resourceID)
 	default:
 		return status, fmt.Errorf("unsupported resource type: %s", resourceType)
 	}
@@ -224,29 +357,138 @@ func (a *Adapter) getStorageAccountStatus(ctx context.Context, resourceID string
 		Properties:   make(map[string]interface{}),
 	}
 
-	if account.Properties != nil {
-		if account.Properties.Encryption != nil {
+	if props := account.Properties; props != nil {
+		if props.Encryption != nil {
 			status.Properties["encryption_enabled"] = true
 		}
-		if account.Properties.ProvisioningState != nil {
-			status.State = string(*account.Properties.ProvisioningState)
+		if props.ProvisioningState != nil {
+			status.State = string(*props.ProvisioningState)
+		}
+		if props.MinimumTLSVersion != nil {
+			status.Properties["min_tls_version"] = string(*props.MinimumTLSVersion)
+		}
+		if props.AllowBlobPublicAccess != nil {
+			status.Properties["allow_nested_items_to_be_public"] = *props.AllowBlobPublicAccess
+		}
+		if props.EnableHTTPSTrafficOnly != nil {
+			status.Properties["https_traffic_only_enabled"] = *props.EnableHTTPSTrafficOnly
+		}
+		if props.AllowSharedKeyAccess != nil {
+			status.Properties["shared_access_key_enabled"] = *props.AllowSharedKeyAccess
+		}
+		if props.PublicNetworkAccess != nil {
+			status.Properties["public_network_access_enabled"] = *props.PublicNetworkAccess != armstorage.PublicNetworkAccessDisabled
 		}
 	}
 
 	return status, nil
 }
 
+func (a *Adapter) getStorageContainerStatus(ctx context.Context, resourceID string) (*cloud.ResourceStatus, error) {
+	serviceURL, name, err := a.containerLocation(resourceID)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := a.blobService(serviceURL)
+	if err != nil {
+		return nil, err
+	}
+
+	props, err := client.NewContainerClient(name).GetProperties(ctx, nil)
+	if bloberror.HasCode(err, bloberror.ContainerNotFound) {
+		return &cloud.ResourceStatus{
+			ResourceID:   resourceID,
+			ResourceType: "azurerm_storage_container",
+			Exists:       false,
+		}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get storage container properties: %w", err)
+	}
+
+	// Metadata names are case-insensitive; azurerm requires lowercase ones
+	tags := make(map[string]string)
+	for key, value := range props.Metadata {
+		if value != nil {
+			tags[strings.ToLower(key)] = *value
+		}
+	}
+
+	accessType := "private"
+	if props.BlobPublicAccess != nil {
+		accessType = string(*props.BlobPublicAccess)
+	}
+
+	return &cloud.ResourceStatus{
+		ResourceID:   resourceID,
+		ResourceType: "azurerm_storage_container",
+		Exists:       true,
+		Tags:         tags,
+		Properties: map[string]interface{}{
+			"name":                  name,
+			"container_access_type": accessType,
+		},
+	}, nil
+}
+
+// containerLocation returns the blob service URL and name of a storage
+// container from its ID: its URL, e.g. https://acme.blob.core.windows.net/logs,
+// or its resource manager ID ending in
+// /storageAccounts/acme/blobServices/default/containers/logs. A blob endpoint
+// override replaces the storage account's service URL.
+func (a *Adapter) containerLocation(resourceID string) (string, string, error) {
+	var serviceURL, name string
+
+	if strings.HasPrefix(resourceID, "/subscriptions/") {
+		parts := strings.Split(resourceID, "/")
+		for i := 0; i+1 < len(parts); i++ {
+			if strings.EqualFold(parts[i], "storageAccounts") {
+				serviceURL = fmt.Sprintf("https://%s.blob.core.windows.net/", parts[i+1])
+			}
+		}
+		name = parts[len(parts)-1]
+	} else if u, err := url.Parse(resourceID); err == nil && u.Host != "" {
+		path := strings.Trim(u.Path, "/")
+		name = path[strings.LastIndex(path, "/")+1:]
+		u.Path = strings.TrimSuffix(path, name)
+		serviceURL = u.String()
+	}
+
+	if serviceURL == "" || name == "" {
+		return "", "", fmt.Errorf("invalid Azure storage container ID format")
+	}
+	if a.blobEndpoint != "" {
+		serviceURL = a.blobEndpoint
+	}
+	return serviceURL, name, nil
+}
+
+// blobService returns a client of the blob service at serviceURL,
+// authenticated with the shared key when one is set
+func (a *Adapter) blobService(serviceURL string) (*service.Client, error) {
+	options := &service.ClientOptions{}
+	if a.httpClient != nil {
+		options.Transport = a.httpClient
+	}
+
+	if a.blobCred != nil {
+		return service.NewClientWithSharedKeyCredential(serviceURL, a.blobCred, options)
+	}
+	if strings.HasPrefix(serviceURL, "http://") && !a.recorder.Replaying() {
+		return nil, fmt.Errorf("blob endpoint %s needs a shared key: set AZURE_STORAGE_ACCOUNT and AZURE_STORAGE_KEY", serviceURL)
+	}
+	options.InsecureAllowCredentialWithHTTP = a.recorder.Replaying()
+	return service.NewClient(serviceURL, a.cred, options)
+}
+
 func (a *Adapter) getResourceGroupStatus(ctx context.Context, resourceID string) (*cloud.ResourceStatus, error) {
 	parts := strings.Split(resourceID, "/")
 	if len(parts) < 9 {
 		// Assume it's a resource group if path is short
 		if len(parts) >= 5 {
 			// Format: /subscriptions/{subscription}/resourceGroups/{rg}
-			return &cloud.ResourceStatus{
-				ResourceID:   resourceID,
-				ResourceType: "azurerm_resource_group",
-				Exists:       true, // Assume exists for now
-			}, nil
+			return a.getGroupStatus(ctx, resourceID, parts[4])
 		}
 		return nil, fmt.Errorf("invalid Azure resource ID format")
 	}
@@ -288,9 +530,167 @@ func (a *Adapter) getResourceGroupStatus(ctx context.Context, resourceID string)
 	}, nil
 }
 
-// ValidateResourceCompliance checks resource compliance with policies
-func (a *Adapter) ValidateResourceCompliance(ctx context.Context, resourceType string, resource map[string]interface{}, rules []cloud.ValidationRule) ([]cloud.ValidationResult, error) {
-	return []cloud.ValidationResult{}, nil
+// getGroupStatus reads a resource group
+func (a *Adapter) getGroupStatus(ctx context.Context, resourceID, name string) (*cloud.ResourceStatus, error) {
+	group, err := a.groupsClient.Get(ctx, name, nil)
+	if notFound(err) {
+		return missing(resourceID, "azurerm_resource_group"), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get resource group: %w", err)
+	}
+
+	status := &cloud.ResourceStatus{
+		ResourceID:   resourceID,
+		ResourceType: "azurerm_resource_group",
+		Exists:       true,
+		Tags:         azureTags(group.Tags),
+		Properties: map[string]interface{}{
+			"location": deref(group.Location),
+			"name":     name,
+		},
+	}
+	if group.Properties != nil {
+		status.State = deref(group.Properties.ProvisioningState)
+	}
+
+	return status, nil
+}
+
+// differs map the planned attributes of each resource type to the properties
+// GetResourceStatus reads; other types are compared by tags only
+var differs = map[string]cloud.Differ{
+	"azurerm_virtual_machine": {Attributes: []cloud.AttributeMapping{
+		{Attribute: "vm_size", Property: "vm_size"},
+	}},
+	"azurerm_resource_group": {Attributes: []cloud.AttributeMapping{
+		{Attribute: "location", Property: "location", Equal: cloud.EqualFold},
+	}},
+	"azurerm_storage_account": {Attributes: []cloud.AttributeMapping{
+		{Attribute: "min_tls_version", Property: "min_tls_version"},
+		{Attribute: "allow_nested_items_to_be_public", Property: "allow_nested_items_to_be_public"},
+		{Attribute: "https_traffic_only_enabled", Property: "https_traffic_only_enabled"},
+		{Attribute: "enable_https_traffic_only", Property: "https_traffic_only_enabled"}, // azurerm 3.x
+		{Attribute: "shared_access_key_enabled", Property: "shared_access_key_enabled"},
+		{Attribute: "public_network_access_enabled", Property: "public_network_access_enabled"},
+	}},
+	"azurerm_storage_container": {Tags: "metadata", Attributes: []cloud.AttributeMapping{
+		{Attribute: "container_access_type", Property: "container_access_type"},
+	}},
+	"azurerm_key_vault": {Attributes: []cloud.AttributeMapping{
+		{Attribute: "location", Property: "location", Equal: cloud.EqualFold},
+		{Attribute: "sku_name", Property: "sku_name", Equal: cloud.EqualFold},
+		{Attribute: "tenant_id", Property: "tenant_id"},
+		{Attribute: "soft_delete_retention_days", Property: "soft_delete_retention_days"},
+		{Attribute: "purge_protection_enabled", Property: "purge_protection_enabled"},
+		{Attribute: "enable_rbac_authorization", Property: "enable_rbac_authorization"},
+		{Attribute: "enabled_for_deployment", Property: "enabled_for_deployment"},
+		{Attribute: "enabled_for_disk_encryption", Property: "enabled_for_disk_encryption"},
+		{Attribute: "enabled_for_template_deployment", Property: "enabled_for_template_deployment"},
+		{Attribute: "public_network_access_enabled", Property: "public_network_access_enabled"},
+	}},
+	"azurerm_sql_server":   {Attributes: sqlServerAttributes},
+	"azurerm_mssql_server": {Attributes: sqlServerAttributes},
+	"azurerm_network_security_group": {Attributes: []cloud.AttributeMapping{
+		{Attribute: "location", Property: "location", Equal: cloud.EqualFold},
+		{Attribute: "security_rule", Property: "security_rules", Equal: sameSecurityRules},
+	}},
+	"azurerm_virtual_network": {Attributes: []cloud.AttributeMapping{
+		{Attribute: "location", Property: "location", Equal: cloud.EqualFold},
+		{Attribute: "address_space", Property: "address_space"},
+		{Attribute: "dns_servers", Property: "dns_servers"},
+	}},
+	"azurerm_managed_disk": {Attributes: []cloud.AttributeMapping{
+		{Attribute: "location", Property: "location", Equal: cloud.EqualFold},
+		{Attribute: "storage_account_type", Property: "storage_account_type"},
+		{Attribute: "disk_size_gb", Property: "disk_size_gb"},
+		{Attribute: "create_option", Property: "create_option"},
+		{Attribute: "zone", Property: "zone"},
+		{Attribute: "disk_encryption_set_id", Property: "disk_encryption_set_id", Equal: cloud.EqualFold},
+		{Attribute: "public_network_access_enabled", Property: "public_network_access_enabled"},
+	}},
+	"azurerm_kubernetes_cluster": {Attributes: []cloud.AttributeMapping{
+		{Attribute: "location", Property: "location", Equal: cloud.EqualFold},
+		{Attribute: "kubernetes_version", Property: "kubernetes_version", Equal: versionPrefix},
+		{Attribute: "dns_prefix", Property: "dns_prefix"},
+		{Attribute: "sku_tier", Property: "sku_tier"},
+		{Attribute: "private_cluster_enabled", Property: "private_cluster_enabled"},
+		{Attribute: "role_based_access_control_enabled", Property: "role_based_access_control_enabled"},
+		{Attribute: "local_account_disabled", Property: "local_account_disabled"},
+		{Attribute: "default_node_pool[0].name", Property: "default_node_pool_name"},
+		{Attribute: "default_node_pool[0].vm_size", Property: "default_node_pool_vm_size", Equal: cloud.EqualFold},
+		{Attribute: "default_node_pool[0].node_count", Property: "default_node_pool_node_count"},
+	}},
+}
+
+// sqlServerAttributes are compared for azurerm_sql_server and its successor
+// azurerm_mssql_server
+var sqlServerAttributes = []cloud.AttributeMapping{
+	{Attribute: "location", Property: "location", Equal: cloud.EqualFold},
+	{Attribute: "version", Property: "version"},
+	{Attribute: "administrator_login", Property: "administrator_login"},
+	{Attribute: "minimum_tls_version", Property: "minimum_tls_version"},
+	{Attribute: "public_network_access_enabled", Property: "public_network_access_enabled"},
+}
+
+// versionPrefix compares a planned version such as 1.29 with the deployed
+// version, e.g. 1.29.2, which Azure patches automatically
+func versionPrefix(planned, actual interface{}) bool {
+	version, deployed := fmt.Sprint(planned), fmt.Sprint(actual)
+	return deployed == version || strings.HasPrefix(deployed, version+".")
+}
+
+// sameSecurityRules compares the name, priority, direction and access of
+// planned inline security rules with the deployed rules. Groups without
+// inline rules are not compared, since their rules are usually
+// azurerm_network_security_rule resources of their own.
+func sameSecurityRules(planned, actual interface{}) bool {
+	blocks, _ := planned.([]interface{})
+	deployed, _ := actual.([]string)
+	if len(blocks) == 0 {
+		return true
+	}
+
+	rules := make([]string, 0, len(blocks))
+	for _, block := range blocks {
+		rule, _ := block.(map[string]interface{})
+		priority, _ := rule["priority"].(float64)
+		rules = append(rules, securityRuleKey(fmt.Sprint(rule["name"]), int32(priority),
+			fmt.Sprint(rule["direction"]), fmt.Sprint(rule["access"])))
+	}
+	sort.Strings(rules)
+	return strings.Join(rules, ",") == strings.Join(deployed, ",")
+}
+
+// liveChecks answer built-in policy conditions for deployed resources;
+// attribute conditions are answered with the properties of differs
+var liveChecks = map[string]cloud.LiveChecks{
+	"azurerm_storage_account": {
+		"public_access.blocked": blobPublicAccessBlocked,
+		"encryption.enabled":    cloud.EnabledCheck("encryption_enabled", "Storage account encryption is not enabled"),
+	},
+}
+
+// blobPublicAccessBlocked answers public_access.blocked for a deployed storage
+// account: it does not allow anonymous access to its blobs
+func blobPublicAccessBlocked(live map[string]interface{}, expected interface{}) (bool, []string, bool) {
+	public, ok := live["allow_nested_items_to_be_public"].(bool)
+	if !ok {
+		return false, nil, false
+	}
+	if required, _ := expected.(bool); !required || !public {
+		return true, nil, true
+	}
+	return false, []string{"Storage account allows public access to blobs"}, true
+}
+
+// ValidateResourceCompliance evaluates the rules against the live
+// configuration of a deployed Azure resource
+func (a *Adapter) ValidateResourceCompliance(ctx context.Context, resourceType, resourceID string, rules []cloud.ValidationRule) ([]cloud.ValidationResult, error) {
+	checks := differs[resourceType].LiveChecks(liveChecks[resourceType])
+	return checks.Check(rules, func() (*cloud.ResourceStatus, error) {
+		return a.GetResourceStatus(ctx, resourceType, resourceID)
+	})
 }
 
 // DetectDrift compares planned state with actual cloud resources
@@ -299,40 +699,91 @@ func (a *Adapter) DetectDrift(ctx context.Context, plannedState map[string]inter
 	if err != nil {
 		return nil, err
 	}
+	return setDrift(plannedState, resourceType, actualStatus), nil
+}
 
+// CheckResource reads a deployed Azure resource once to evaluate the rules
+// against it and detect drift
+func (a *Adapter) CheckResource(ctx context.Context, plannedState map[string]interface{}, resourceType, resourceID string, rules []cloud.ValidationRule) (*cloud.ResourceStatus, []cloud.ValidationResult, error) {
+	actualStatus, err := a.GetResourceStatus(ctx, resourceType, resourceID)
+	if err != nil {
+		return nil, nil, err
+	}
+	results := differs[resourceType].LiveChecks(liveChecks[resourceType]).Results(rules, actualStatus)
+	return setDrift(plannedState, resourceType, actualStatus), results, nil
+}
+
+// setDrift records the drift of a deployed resource of resourceType from the
+// planned state
+func setDrift(plannedState map[string]interface{}, resourceType string, actualStatus *cloud.ResourceStatus) *cloud.ResourceStatus {
 	if !actualStatus.Exists {
 		actualStatus.DriftDetected = true
 		actualStatus.DriftDetails = []string{"Resource does not exist in Azure"}
-		return actualStatus, nil
+		return actualStatus
 	}
 
-	driftDetails := []string{}
+	actualStatus.SetDrift(differs[resourceType].Diff(plannedState, actualStatus))
+	return actualStatus
+}
 
-	// Check tags
-	if plannedTags, ok := plannedState["tags"].(map[string]interface{}); ok {
-		for key, value := range plannedTags {
-			if actualValue, exists := actualStatus.Tags[key]; !exists {
-				driftDetails = append(driftDetails, fmt.Sprintf("Tag '%s' missing", key))
-			} else if fmt.Sprint(value) != actualValue {
-				driftDetails = append(driftDetails, fmt.Sprintf("Tag '%s' differs: planned=%v, actual=%v", key, value, actualValue))
-			}
-		}
+// ListResources lists Azure resources of a given type
+func (a *Adapter) ListResources(ctx context.Context, resourceType string) ([]string, error) {
+	if resourceType == "azurerm_storage_container" && a.blobEndpoint != "" {
+		return a.listStorageContainers(ctx)
 	}
-
-	if len(driftDetails) > 0 {
-		actualStatus.DriftDetected = true
-		actualStatus.DriftDetails = driftDetails
+	if resourceType == "azurerm_resource_group" {
+		return a.listResourceGroups(ctx)
 	}
 
-	return actualStatus, nil
+	// Resource manager types such as Microsoft.Web/sites are listed as well
+	armType := armResourceTypes[resourceType]
+	if armType == "" && strings.Contains(resourceType, "/") {
+		armType = resourceType
+	}
+	if armType == "" {
+		return nil, fmt.Errorf("listing not supported for resource type: %s", resourceType)
+	}
+	return a.listResourcesOfType(ctx, armType)
 }
 
-// ListResources lists Azure resources of a given type
-func (a *Adapter) ListResources(ctx context.Context, resourceType string) ([]string, error) {
-	return nil, fmt.Errorf("listing not yet implemented for Azure")
+// listStorageContainers lists the containers of the blob endpoint override
+// by URL, the ID azurerm gives them
+func (a *Adapter) listStorageContainers(ctx context.Context) ([]string, error) {
+	client, err := a.blobService(a.blobEndpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	var containerIDs []string
+	pager := client.NewListContainersPager(nil)
+	for pager.More() {
+		page, err := pager.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range page.ContainerItems {
+			if item.Name != nil {
+				containerIDs = append(containerIDs, strings.TrimSuffix(a.blobEndpoint, "/")+"/"+*item.Name)
+			}
+		}
+	}
+
+	return containerIDs, nil
 }
 
 // Close cleans up Azure adapter resources
 func (a *Adapter) Close() error {
+	if a.recorder != nil {
+		return a.recorder.Save()
+	}
 	return nil
 }
+
+// placeholderCredential supplies a placeholder token when replaying a
+// cassette or calling an emulator
+type placeholderCredential struct{}
+
+// GetToken returns the placeholder token
+func (placeholderCredential) GetToken(ctx context.Context, options policy.TokenRequestOptions) (azcore.AccessToken, error) {
+	return azcore.AccessToken{Token: "replay", ExpiresOn: time.Now().Add(time.Hour)}, nil
+}
@@ -0,0 +1,71 @@
+package azure
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vijayaxai/terraship/internal/cloud"
+)
+
+// azuriteKey is the well-known shared key of Azurite's devstoreaccount1
+const azuriteKey = "Eby8vdM02xNOcqFlqUwJPLlmEtlCDXJ1OUzFT50uSRZ6IFsuFq2UVErCz4I6tq/K1SZFPTOtr/KBHBeksoGMGw=="
+
+func TestAdapter_BlobEndpointOverride(t *testing.T) {
+	// Serves the blob API like Azurite
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.True(t, strings.HasPrefix(r.Header.Get("Authorization"), "SharedKey devstoreaccount1:"))
+		switch {
+		case r.URL.Path == "/devstoreaccount1" && r.URL.Query().Get("comp") == "list":
+			w.Header().Set("Content-Type", "application/xml")
+			_, _ = io.WriteString(w, `<?xml version="1.0" encoding="utf-8"?><EnumerationResults><Containers>`+
+				`<Container><Name>logs</Name><Properties></Properties></Container>`+
+				`</Containers><NextMarker/></EnumerationResults>`)
+		case r.URL.Path == "/devstoreaccount1/logs":
+			w.Header().Set("X-Ms-Meta-Env", "dev")
+			w.Header().Set("X-Ms-Blob-Public-Access", "blob")
+		default:
+			w.Header().Set("X-Ms-Error-Code", "ContainerNotFound")
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	t.Setenv("AZURE_SUBSCRIPTION_ID", "")
+	t.Setenv("AZURE_STORAGE_ACCOUNT", "devstoreaccount1")
+	t.Setenv("AZURE_STORAGE_KEY", azuriteKey)
+	endpoint := server.URL + "/devstoreaccount1"
+
+	ctx := context.Background()
+	adapter := NewAdapter()
+	require.NoError(t, adapter.Initialize(ctx, cloud.CloudConfig{
+		Provider:  cloud.ProviderAzure,
+		Endpoints: map[string]string{"blob": endpoint},
+	}))
+	defer adapter.Close()
+	require.NoError(t, adapter.ValidateCredentials(ctx))
+
+	containers, err := adapter.ListResources(ctx, "azurerm_storage_container")
+	require.NoError(t, err)
+	assert.Equal(t, []string{endpoint + "/logs"}, containers)
+
+	// IDs name the public service; the override replaces it
+	drift, err := adapter.DetectDrift(ctx, map[string]interface{}{
+		"metadata": map[string]interface{}{"env": "prod"},
+	}, "azurerm_storage_container", "https://devstoreaccount1.blob.core.windows.net/logs")
+	require.NoError(t, err)
+	assert.True(t, drift.Exists)
+	assert.Equal(t, "blob", drift.Properties["container_access_type"])
+	assert.Equal(t, []string{"Metadata 'env' differs: planned=prod, actual=dev"}, drift.DriftDetails)
+
+	missing, err := adapter.DetectDrift(ctx, nil, "azurerm_storage_container",
+		"/subscriptions/sub/resourceGroups/rg/providers/Microsoft.Storage/storageAccounts/devstoreaccount1/blobServices/default/containers/backups")
+	require.NoError(t, err)
+	assert.False(t, missing.Exists)
+	assert.True(t, missing.DriftDetected)
+}
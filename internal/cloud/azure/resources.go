@@ -0,0 +1,413 @@
+package azure
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armresources"
+	"github.com/vijayaxai/terraship/internal/cloud"
+)
+
+// armResourceTypes are the resource manager types of terraform resource types,
+// for listing them
+var armResourceTypes = map[string]string{
+	"azurerm_virtual_machine":         "Microsoft.Compute/virtualMachines",
+	"azurerm_linux_virtual_machine":   "Microsoft.Compute/virtualMachines",
+	"azurerm_windows_virtual_machine": "Microsoft.Compute/virtualMachines",
+	"azurerm_managed_disk":            "Microsoft.Compute/disks",
+	"azurerm_storage_account":         "Microsoft.Storage/storageAccounts",
+	"azurerm_key_vault":               "Microsoft.KeyVault/vaults",
+	"azurerm_sql_server":              "Microsoft.Sql/servers",
+	"azurerm_mssql_server":            "Microsoft.Sql/servers",
+	"azurerm_network_security_group":  "Microsoft.Network/networkSecurityGroups",
+	"azurerm_virtual_network":         "Microsoft.Network/virtualNetworks",
+	"azurerm_public_ip":               "Microsoft.Network/publicIPAddresses",
+	"azurerm_network_interface":       "Microsoft.Network/networkInterfaces",
+	"azurerm_kubernetes_cluster":      "Microsoft.ContainerService/managedClusters",
+	"azurerm_container_registry":      "Microsoft.ContainerRegistry/registries",
+	"azurerm_user_assigned_identity":  "Microsoft.ManagedIdentity/userAssignedIdentities",
+	"azurerm_log_analytics_workspace": "Microsoft.OperationalInsights/workspaces",
+}
+
+// sqlServerAPIVersion is the API version SQL servers are read with; the
+// adapter reads them as generic resources
+const sqlServerAPIVersion = "2021-11-01"
+
+// notFound reports whether a resource manager call failed because the
+// resource does not exist
+func notFound(err error) bool {
+	var responseErr *azcore.ResponseError
+	return errors.As(err, &responseErr) && responseErr.StatusCode == http.StatusNotFound
+}
+
+// missing returns the status of a resource that does not exist
+func missing(resourceID, resourceType string) *cloud.ResourceStatus {
+	return &cloud.ResourceStatus{
+		ResourceID:   resourceID,
+		ResourceType: resourceType,
+		Exists:       false,
+	}
+}
+
+// azureTags converts resource manager tags to a map
+func azureTags(tags map[string]*string) map[string]string {
+	result := make(map[string]string)
+	for key, value := range tags {
+		if value != nil {
+			result[key] = *value
+		}
+	}
+	return result
+}
+
+func deref[T any](value *T) T {
+	var zero T
+	if value == nil {
+		return zero
+	}
+	return *value
+}
+
+func (a *Adapter) getKeyVaultStatus(ctx context.Context, resourceID string) (*cloud.ResourceStatus, error) {
+	id, err := arm.ParseResourceID(resourceID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid Azure resource ID format: %w", err)
+	}
+
+	vault, err := a.keyVaultClient.Get(ctx, id.ResourceGroupName, id.Name, nil)
+	if notFound(err) {
+		return missing(resourceID, "azurerm_key_vault"), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get key vault: %w", err)
+	}
+
+	status := &cloud.ResourceStatus{
+		ResourceID:   resourceID,
+		ResourceType: "azurerm_key_vault",
+		Exists:       true,
+		Tags:         azureTags(vault.Tags),
+		Properties: map[string]interface{}{
+			"location": deref(vault.Location),
+		},
+	}
+
+	if props := vault.Properties; props != nil {
+		if props.SKU != nil {
+			status.Properties["sku_name"] = string(deref(props.SKU.Name))
+		}
+		if props.ProvisioningState != nil {
+			status.State = string(*props.ProvisioningState)
+		}
+		status.Properties["tenant_id"] = deref(props.TenantID)
+		status.Properties["soft_delete_retention_days"] = deref(props.SoftDeleteRetentionInDays)
+		status.Properties["purge_protection_enabled"] = deref(props.EnablePurgeProtection)
+		status.Properties["enable_rbac_authorization"] = deref(props.EnableRbacAuthorization)
+		status.Properties["enabled_for_deployment"] = deref(props.EnabledForDeployment)
+		status.Properties["enabled_for_disk_encryption"] = deref(props.EnabledForDiskEncryption)
+		status.Properties["enabled_for_template_deployment"] = deref(props.EnabledForTemplateDeployment)
+		status.Properties["public_network_access_enabled"] = !strings.EqualFold(deref(props.PublicNetworkAccess), "Disabled")
+	}
+
+	return status, nil
+}
+
+// getSQLServerStatus reads an Azure SQL server (azurerm_sql_server or
+// azurerm_mssql_server) as a generic resource
+func (a *Adapter) getSQLServerStatus(ctx context.Context, resourceType, resourceID string) (*cloud.ResourceStatus, error) {
+	res, err := a.resourcesClient.GetByID(ctx, resourceID, sqlServerAPIVersion, nil)
+	if notFound(err) {
+		return missing(resourceID, resourceType), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get SQL server: %w", err)
+	}
+
+	status := &cloud.ResourceStatus{
+		ResourceID:   resourceID,
+		ResourceType: resourceType,
+		Exists:       true,
+		Tags:         azureTags(res.Tags),
+		Properties: map[string]interface{}{
+			"location": deref(res.Location),
+		},
+	}
+
+	props, _ := res.Properties.(map[string]interface{})
+	if state, ok := props["state"].(string); ok {
+		status.State = state
+	}
+	status.Properties["version"] = props["version"]
+	status.Properties["administrator_login"] = props["administratorLogin"]
+	status.Properties["fully_qualified_domain_name"] = props["fullyQualifiedDomainName"]
+	if tls, ok := props["minimalTlsVersion"].(string); ok {
+		status.Properties["minimum_tls_version"] = tls
+	}
+	if access, ok := props["publicNetworkAccess"].(string); ok {
+		status.Properties["public_network_access_enabled"] = !strings.EqualFold(access, "Disabled")
+	}
+
+	return status, nil
+}
+
+func (a *Adapter) getNetworkSecurityGroupStatus(ctx context.Context, resourceID string) (*cloud.ResourceStatus, error) {
+	id, err := arm.ParseResourceID(resourceID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid Azure resource ID format: %w", err)
+	}
+
+	group, err := a.securityGroupsClient.Get(ctx, id.ResourceGroupName, id.Name, nil)
+	if notFound(err) {
+		return missing(resourceID, "azurerm_network_security_group"), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get network security group: %w", err)
+	}
+
+	status := &cloud.ResourceStatus{
+		ResourceID:   resourceID,
+		ResourceType: "azurerm_network_security_group",
+		Exists:       true,
+		Tags:         azureTags(group.Tags),
+		Properties: map[string]interface{}{
+			"location": deref(group.Location),
+		},
+	}
+
+	if props := group.Properties; props != nil {
+		if props.ProvisioningState != nil {
+			status.State = string(*props.ProvisioningState)
+		}
+
+		var rules []string
+		var openPorts []string
+		for _, rule := range props.SecurityRules {
+			if rule == nil || rule.Properties == nil {
+				continue
+			}
+			rules = append(rules, securityRuleKey(deref(rule.Name), deref(rule.Properties.Priority),
+				string(deref(rule.Properties.Direction)), string(deref(rule.Properties.Access))))
+
+			// Inbound rules allowing any source, for compliance checks
+			source := strings.ToLower(deref(rule.Properties.SourceAddressPrefix))
+			if deref(rule.Properties.Direction) == "Inbound" && deref(rule.Properties.Access) == "Allow" &&
+				(source == "*" || source == "internet" || source == "0.0.0.0/0") {
+				openPorts = append(openPorts, deref(rule.Properties.DestinationPortRange))
+			}
+		}
+		sort.Strings(rules)
+		status.Properties["security_rules"] = rules
+		status.Properties["internet_inbound_ports"] = openPorts
+	}
+
+	return status, nil
+}
+
+// securityRuleKey identifies a security rule by the settings drift is
+// checked for
+func securityRuleKey(name string, priority int32, direction, access string) string {
+	return fmt.Sprintf("%s:%d:%s:%s", name, priority, direction, access)
+}
+
+func (a *Adapter) getVirtualNetworkStatus(ctx context.Context, resourceID string) (*cloud.ResourceStatus, error) {
+	id, err := arm.ParseResourceID(resourceID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid Azure resource ID format: %w", err)
+	}
+
+	network, err := a.virtualNetworksClient.Get(ctx, id.ResourceGroupName, id.Name, nil)
+	if notFound(err) {
+		return missing(resourceID, "azurerm_virtual_network"), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get virtual network: %w", err)
+	}
+
+	status := &cloud.ResourceStatus{
+		ResourceID:   resourceID,
+		ResourceType: "azurerm_virtual_network",
+		Exists:       true,
+		Tags:         azureTags(network.Tags),
+		Properties: map[string]interface{}{
+			"location": deref(network.Location),
+		},
+	}
+
+	if props := network.Properties; props != nil {
+		if props.ProvisioningState != nil {
+			status.State = string(*props.ProvisioningState)
+		}
+		addressSpace := make([]string, 0)
+		if props.AddressSpace != nil {
+			for _, prefix := range props.AddressSpace.AddressPrefixes {
+				addressSpace = append(addressSpace, deref(prefix))
+			}
+		}
+		dnsServers := make([]string, 0)
+		if props.DhcpOptions != nil {
+			for _, server := range props.DhcpOptions.DNSServers {
+				dnsServers = append(dnsServers, deref(server))
+			}
+		}
+		status.Properties["address_space"] = addressSpace
+		status.Properties["dns_servers"] = dnsServers
+		status.Properties["subnet_count"] = len(props.Subnets)
+	}
+
+	return status, nil
+}
+
+func (a *Adapter) getManagedDiskStatus(ctx context.Context, resourceID string) (*cloud.ResourceStatus, error) {
+	id, err := arm.ParseResourceID(resourceID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid Azure resource ID format: %w", err)
+	}
+
+	disk, err := a.disksClient.Get(ctx, id.ResourceGroupName, id.Name, nil)
+	if notFound(err) {
+		return missing(resourceID, "azurerm_managed_disk"), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get managed disk: %w", err)
+	}
+
+	status := &cloud.ResourceStatus{
+		ResourceID:   resourceID,
+		ResourceType: "azurerm_managed_disk",
+		Exists:       true,
+		Tags:         azureTags(disk.Tags),
+		Properties: map[string]interface{}{
+			"location": deref(disk.Location),
+		},
+	}
+
+	if disk.SKU != nil {
+		status.Properties["storage_account_type"] = string(deref(disk.SKU.Name))
+	}
+	if len(disk.Zones) > 0 {
+		status.Properties["zone"] = deref(disk.Zones[0])
+	}
+	if props := disk.Properties; props != nil {
+		if props.DiskState != nil {
+			status.State = string(*props.DiskState)
+		}
+		status.Properties["disk_size_gb"] = deref(props.DiskSizeGB)
+		if props.CreationData != nil {
+			status.Properties["create_option"] = string(deref(props.CreationData.CreateOption))
+		}
+		if props.OSType != nil {
+			status.Properties["os_type"] = string(*props.OSType)
+		}
+		if props.Encryption != nil {
+			status.Properties["encryption_type"] = string(deref(props.Encryption.Type))
+			status.Properties["disk_encryption_set_id"] = deref(props.Encryption.DiskEncryptionSetID)
+		}
+		if props.PublicNetworkAccess != nil {
+			status.Properties["public_network_access_enabled"] = *props.PublicNetworkAccess != "Disabled"
+		}
+	}
+
+	return status, nil
+}
+
+func (a *Adapter) getKubernetesClusterStatus(ctx context.Context, resourceID string) (*cloud.ResourceStatus, error) {
+	id, err := arm.ParseResourceID(resourceID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid Azure resource ID format: %w", err)
+	}
+
+	cluster, err := a.clustersClient.Get(ctx, id.ResourceGroupName, id.Name, nil)
+	if notFound(err) {
+		return missing(resourceID, "azurerm_kubernetes_cluster"), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get Kubernetes cluster: %w", err)
+	}
+
+	status := &cloud.ResourceStatus{
+		ResourceID:   resourceID,
+		ResourceType: "azurerm_kubernetes_cluster",
+		Exists:       true,
+		Tags:         azureTags(cluster.Tags),
+		Properties: map[string]interface{}{
+			"location": deref(cluster.Location),
+		},
+	}
+
+	if cluster.SKU != nil {
+		status.Properties["sku_tier"] = string(deref(cluster.SKU.Tier))
+	}
+	if props := cluster.Properties; props != nil {
+		status.State = deref(props.ProvisioningState)
+		if props.PowerState != nil && props.PowerState.Code != nil {
+			status.State = string(*props.PowerState.Code)
+		}
+		status.Properties["kubernetes_version"] = deref(props.KubernetesVersion)
+		status.Properties["dns_prefix"] = deref(props.DNSPrefix)
+		status.Properties["role_based_access_control_enabled"] = deref(props.EnableRBAC)
+		status.Properties["local_account_disabled"] = deref(props.DisableLocalAccounts)
+		status.Properties["private_cluster_enabled"] = false
+		if access := props.APIServerAccessProfile; access != nil {
+			status.Properties["private_cluster_enabled"] = deref(access.EnablePrivateCluster)
+		}
+
+		// The default node pool is the first system pool
+		for _, pool := range props.AgentPoolProfiles {
+			if pool != nil && pool.Mode != nil && *pool.Mode == "System" {
+				status.Properties["default_node_pool_name"] = deref(pool.Name)
+				status.Properties["default_node_pool_vm_size"] = deref(pool.VMSize)
+				status.Properties["default_node_pool_node_count"] = deref(pool.Count)
+				break
+			}
+		}
+	}
+
+	return status, nil
+}
+
+// listResourceGroups lists the resource groups of the subscription by ID
+func (a *Adapter) listResourceGroups(ctx context.Context) ([]string, error) {
+	var groupIDs []string
+	pager := a.groupsClient.NewListPager(nil)
+	for pager.More() {
+		page, err := pager.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, group := range page.Value {
+			if group.ID != nil {
+				groupIDs = append(groupIDs, *group.ID)
+			}
+		}
+	}
+
+	return groupIDs, nil
+}
+
+// listResourcesOfType lists the resources of a resource manager type, e.g.
+// Microsoft.KeyVault/vaults, by ID
+func (a *Adapter) listResourcesOfType(ctx context.Context, armType string) ([]string, error) {
+	filter := fmt.Sprintf("resourceType eq '%s'", armType)
+	pager := a.resourcesClient.NewListPager(&armresources.ClientListOptions{Filter: &filter})
+
+	var resourceIDs []string
+	for pager.More() {
+		page, err := pager.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, resource := range page.Value {
+			if resource.ID != nil {
+				resourceIDs = append(resourceIDs, *resource.ID)
+			}
+		}
+	}
+
+	return resourceIDs, nil
+}
@@ -0,0 +1,243 @@
+package azure
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vijayaxai/terraship/internal/cloud"
+)
+
+const groupID = "/subscriptions/sub/resourceGroups/rg"
+
+// standInResources are the stand-in resource manager's responses by path
+var standInResources = map[string]string{
+	groupID: `{"id": "/subscriptions/sub/resourceGroups/rg", "name": "rg", "location": "westeurope",
+		"tags": {"env": "prod"}, "properties": {"provisioningState": "Succeeded"}}`,
+	groupID + "/providers/Microsoft.KeyVault/vaults/kv": `{"location": "westeurope", "tags": {"env": "prod"},
+		"properties": {"tenantId": "00000000-0000-0000-0000-000000000001", "sku": {"family": "A", "name": "standard"},
+		"enableSoftDelete": true, "softDeleteRetentionInDays": 90, "publicNetworkAccess": "Enabled", "provisioningState": "Succeeded"}}`,
+	groupID + "/providers/Microsoft.Sql/servers/sql": `{"location": "westeurope", "tags": {"env": "prod"},
+		"properties": {"version": "12.0", "administratorLogin": "sqladmin", "state": "Ready",
+		"minimalTlsVersion": "1.0", "publicNetworkAccess": "Disabled"}}`,
+	groupID + "/providers/Microsoft.Network/networkSecurityGroups/web": `{"location": "westeurope", "tags": {"env": "prod"},
+		"properties": {"provisioningState": "Succeeded", "securityRules": [
+		{"name": "https", "properties": {"priority": 100, "direction": "Inbound", "access": "Allow", "protocol": "Tcp",
+			"sourceAddressPrefix": "*", "destinationPortRange": "443"}},
+		{"name": "ssh", "properties": {"priority": 110, "direction": "Inbound", "access": "Allow", "protocol": "Tcp",
+			"sourceAddressPrefix": "Internet", "destinationPortRange": "22"}}]}}`,
+	groupID + "/providers/Microsoft.Network/virtualNetworks/vnet": `{"location": "westeurope", "tags": {"env": "prod"},
+		"properties": {"provisioningState": "Succeeded", "addressSpace": {"addressPrefixes": ["10.0.0.0/16"]},
+		"subnets": [{"name": "default"}]}}`,
+	groupID + "/providers/Microsoft.Compute/disks/data": `{"location": "westeurope", "tags": {"env": "prod"},
+		"sku": {"name": "Premium_LRS"}, "zones": ["1"], "properties": {"diskSizeGB": 128, "diskState": "Attached",
+		"creationData": {"createOption": "Empty"}, "encryption": {"type": "EncryptionAtRestWithPlatformKey"}}}`,
+	groupID + "/providers/Microsoft.ContainerService/managedClusters/aks": `{"location": "westeurope", "tags": {"env": "prod"},
+		"sku": {"name": "Base", "tier": "Free"}, "properties": {"provisioningState": "Succeeded", "powerState": {"code": "Running"},
+		"kubernetesVersion": "1.29.2", "dnsPrefix": "aks", "enableRBAC": true,
+		"agentPoolProfiles": [{"name": "user", "mode": "User", "count": 5, "vmSize": "Standard_D8s_v5"},
+		{"name": "system", "mode": "System", "count": 3, "vmSize": "Standard_D4s_v5"}]}}`,
+	groupID + "/providers/Microsoft.Storage/storageAccounts/acct": `{"location": "westeurope", "tags": {"env": "prod"}, "kind": "StorageV2",
+		"properties": {"provisioningState": "Succeeded", "minimumTlsVersion": "TLS1_0", "allowBlobPublicAccess": true,
+		"supportsHttpsTrafficOnly": true, "publicNetworkAccess": "Enabled", "encryption": {"keySource": "Microsoft.Storage"}}}`,
+	"/subscriptions/sub/resourcegroups": `{"value": [{"id": "/subscriptions/sub/resourceGroups/rg"}]}`,
+	"/subscriptions/sub/resources": `{"value": [{"id": "/subscriptions/sub/resourceGroups/rg/providers/Microsoft.KeyVault/vaults/kv"},
+		{"id": "/subscriptions/sub/resourceGroups/rg/providers/Microsoft.KeyVault/vaults/unmanaged"}]}`,
+}
+
+// newStandInAdapter returns an adapter calling a stand-in resource manager
+func newStandInAdapter(t *testing.T) (*Adapter, *[]string) {
+	t.Helper()
+	var filters []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if filter := r.URL.Query().Get("$filter"); filter != "" {
+			filters = append(filters, filter)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		response, ok := "", false
+		for path, resource := range standInResources {
+			if strings.EqualFold(path, r.URL.Path) { // resource manager paths are case-insensitive
+				response, ok = resource, true
+			}
+		}
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = io.WriteString(w, `{"error": {"code": "ResourceNotFound", "message": "not found"}}`)
+			return
+		}
+		_, _ = io.WriteString(w, response)
+	}))
+	t.Cleanup(server.Close)
+
+	t.Setenv("AZURE_STORAGE_ACCOUNT", "")
+	adapter := NewAdapter()
+	require.NoError(t, adapter.Initialize(context.Background(), cloud.CloudConfig{
+		Provider:            cloud.ProviderAzure,
+		AzureSubscriptionID: "sub",
+		Endpoints:           map[string]string{"resourcemanager": server.URL},
+	}))
+	t.Cleanup(func() { assert.NoError(t, adapter.Close()) })
+
+	return adapter, &filters
+}
+
+func TestAdapter_ResourceCoverage(t *testing.T) {
+	tests := []struct {
+		resourceType string
+		name         string
+		planned      map[string]interface{}
+		state        string
+		properties   map[string]interface{}
+		drift        []string
+	}{
+		{
+			resourceType: "azurerm_resource_group",
+			name:         "",
+			planned:      map[string]interface{}{"location": "West Europe"},
+			state:        "Succeeded",
+		},
+		{
+			resourceType: "azurerm_storage_account",
+			name:         "/providers/Microsoft.Storage/storageAccounts/acct",
+			planned:      map[string]interface{}{"min_tls_version": "TLS1_2", "enable_https_traffic_only": true},
+			state:        "Succeeded",
+			properties:   map[string]interface{}{"allow_nested_items_to_be_public": true, "public_network_access_enabled": true},
+			drift:        []string{"Attribute 'min_tls_version' differs: planned=TLS1_2, actual=TLS1_0"},
+		},
+		{
+			resourceType: "azurerm_key_vault",
+			name:         "/providers/Microsoft.KeyVault/vaults/kv",
+			planned:      map[string]interface{}{"sku_name": "premium", "purge_protection_enabled": false},
+			state:        "Succeeded",
+			properties:   map[string]interface{}{"soft_delete_retention_days": int32(90), "public_network_access_enabled": true},
+			drift:        []string{"Attribute 'sku_name' differs: planned=premium, actual=standard"},
+		},
+		{
+			resourceType: "azurerm_mssql_server",
+			name:         "/providers/Microsoft.Sql/servers/sql",
+			planned:      map[string]interface{}{"version": "12.0", "minimum_tls_version": "1.2"},
+			state:        "Ready",
+			properties:   map[string]interface{}{"administrator_login": "sqladmin", "public_network_access_enabled": false},
+			drift:        []string{"Attribute 'minimum_tls_version' differs: planned=1.2, actual=1.0"},
+		},
+		{
+			resourceType: "azurerm_network_security_group",
+			name:         "/providers/Microsoft.Network/networkSecurityGroups/web",
+			planned: map[string]interface{}{"security_rule": []interface{}{
+				map[string]interface{}{"name": "https", "priority": float64(100), "direction": "Inbound", "access": "Allow"},
+			}},
+			state:      "Succeeded",
+			properties: map[string]interface{}{"internet_inbound_ports": []string{"443", "22"}},
+			drift: []string{"Attribute 'security_rule' differs: planned=[map[access:Allow direction:Inbound name:https priority:100]], " +
+				"actual=[https:100:Inbound:Allow ssh:110:Inbound:Allow]"},
+		},
+		{
+			resourceType: "azurerm_virtual_network",
+			name:         "/providers/Microsoft.Network/virtualNetworks/vnet",
+			planned:      map[string]interface{}{"address_space": []interface{}{"10.0.0.0/16"}, "location": "westeurope"},
+			state:        "Succeeded",
+			properties:   map[string]interface{}{"subnet_count": 1},
+		},
+		{
+			resourceType: "azurerm_managed_disk",
+			name:         "/providers/Microsoft.Compute/disks/data",
+			planned:      map[string]interface{}{"disk_size_gb": float64(256), "storage_account_type": "Premium_LRS", "zone": "1"},
+			state:        "Attached",
+			properties:   map[string]interface{}{"encryption_type": "EncryptionAtRestWithPlatformKey"},
+			drift:        []string{"Attribute 'disk_size_gb' differs: planned=256, actual=128"},
+		},
+		{
+			resourceType: "azurerm_kubernetes_cluster",
+			name:         "/providers/Microsoft.ContainerService/managedClusters/aks",
+			planned: map[string]interface{}{
+				"kubernetes_version": "1.29",
+				"sku_tier":           "Standard",
+				"default_node_pool":  []interface{}{map[string]interface{}{"vm_size": "Standard_D4s_v5", "node_count": float64(3)}},
+			},
+			state:      "Running",
+			properties: map[string]interface{}{"role_based_access_control_enabled": true, "private_cluster_enabled": false},
+			drift:      []string{"Attribute 'sku_tier' differs: planned=Standard, actual=Free"},
+		},
+	}
+
+	ctx := context.Background()
+	adapter, _ := newStandInAdapter(t)
+	for _, tt := range tests {
+		t.Run(tt.resourceType, func(t *testing.T) {
+			status, err := adapter.DetectDrift(ctx, tt.planned, tt.resourceType, groupID+tt.name)
+			require.NoError(t, err)
+			assert.True(t, status.Exists)
+			assert.Equal(t, tt.state, status.State)
+			assert.Equal(t, "prod", status.Tags["env"])
+			for property, value := range tt.properties {
+				assert.Equal(t, value, status.Properties[property], property)
+			}
+			assert.Equal(t, tt.drift, status.DriftDetails)
+		})
+	}
+}
+
+func TestAdapter_ResourceCoverageMissing(t *testing.T) {
+	ctx := context.Background()
+	adapter, _ := newStandInAdapter(t)
+
+	for _, resourceType := range []string{"azurerm_key_vault", "azurerm_sql_server", "azurerm_managed_disk", "azurerm_kubernetes_cluster"} {
+		status, err := adapter.DetectDrift(ctx, nil, resourceType, groupID+"/providers/Microsoft.Test/things/missing")
+		require.NoError(t, err, resourceType)
+		assert.False(t, status.Exists, resourceType)
+		assert.Equal(t, []string{"Resource does not exist in Azure"}, status.DriftDetails, resourceType)
+	}
+
+	group, err := adapter.GetResourceStatus(ctx, "azurerm_resource_group", "/subscriptions/sub/resourceGroups/gone")
+	require.NoError(t, err)
+	assert.False(t, group.Exists)
+}
+
+func TestAdapter_ValidateResourceCompliance(t *testing.T) {
+	ctx := context.Background()
+	adapter, _ := newStandInAdapter(t)
+
+	rules := []cloud.ValidationRule{
+		{Name: "storage-min-tls", Severity: "error", Conditions: map[string]interface{}{"min_tls_version": "TLS1_2"}},
+		{Name: "storage-public-access-blocked", Severity: "error", Conditions: map[string]interface{}{"public_access.blocked": true}},
+		{Name: "storage-https-only", Severity: "warning", Conditions: map[string]interface{}{"https_traffic_only_enabled": true}},
+	}
+	results, err := adapter.ValidateResourceCompliance(ctx, "azurerm_storage_account", groupID+"/providers/Microsoft.Storage/storageAccounts/acct", rules)
+	require.NoError(t, err)
+	require.Len(t, results, 3)
+	assert.Equal(t, []string{"Deployed attribute 'min_tls_version' has value 'TLS1_0', expected 'TLS1_2'"}, results[0].Details)
+	assert.Equal(t, []string{"Storage account allows public access to blobs"}, results[1].Details)
+	assert.True(t, results[2].Passed)
+	for _, result := range results {
+		assert.Equal(t, cloud.SourceLive, result.Source)
+	}
+
+	results, err = adapter.ValidateResourceCompliance(ctx, "azurerm_storage_account", groupID+"/providers/Microsoft.Storage/storageAccounts/missing", rules)
+	require.NoError(t, err)
+	assert.Empty(t, results)
+}
+
+func TestAdapter_ListResources(t *testing.T) {
+	ctx := context.Background()
+	adapter, filters := newStandInAdapter(t)
+
+	groups, err := adapter.ListResources(ctx, "azurerm_resource_group")
+	require.NoError(t, err)
+	assert.Equal(t, []string{groupID}, groups)
+
+	vaults, err := adapter.ListResources(ctx, "azurerm_key_vault")
+	require.NoError(t, err)
+	assert.Len(t, vaults, 2)
+
+	_, err = adapter.ListResources(ctx, "Microsoft.Web/sites")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"resourceType eq 'Microsoft.KeyVault/vaults'", "resourceType eq 'Microsoft.Web/sites'"}, *filters)
+
+	_, err = adapter.ListResources(ctx, "azurerm_role_assignment")
+	assert.True(t, err != nil && strings.Contains(err.Error(), "listing not supported"))
+}
@@ -0,0 +1,286 @@
+// Package cassette records the HTTP traffic of cloud SDK clients to cassette
+// files and replays it, so adapters can be regression tested without network
+// access or cloud accounts. Credentials, signatures and tokens are redacted
+// before anything is written.
+package cassette
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"sync"
+	"unicode/utf8"
+)
+
+// Mode selects whether a Recorder records or replays traffic
+type Mode string
+
+const (
+	// ModeRecord forwards requests to the network and records them
+	ModeRecord Mode = "record"
+	// ModeReplay serves requests from a cassette without network access
+	ModeReplay Mode = "replay"
+)
+
+// Cassette is the file format of recorded traffic
+type Cassette struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+// Interaction is a recorded request and its response
+type Interaction struct {
+	Request  Request  `json:"request"`
+	Response Response `json:"response"`
+}
+
+// Request is a sanitized recorded request
+type Request struct {
+	Method       string      `json:"method"`
+	URL          string      `json:"url"`
+	Headers      http.Header `json:"headers,omitempty"`
+	Body         string      `json:"body,omitempty"`
+	BodyEncoding string      `json:"body_encoding,omitempty"` // "base64" for non-UTF-8 bodies
+}
+
+// Response is a sanitized recorded response
+type Response struct {
+	StatusCode   int         `json:"status_code"`
+	Headers      http.Header `json:"headers,omitempty"`
+	Body         string      `json:"body,omitempty"`
+	BodyEncoding string      `json:"body_encoding,omitempty"` // "base64" for non-UTF-8 bodies
+}
+
+// Recorder is an http.RoundTripper that records or replays a cassette
+type Recorder struct {
+	mode Mode
+	path string
+	base http.RoundTripper
+
+	mu       sync.Mutex
+	cassette Cassette
+	used     []bool // replayed interactions
+}
+
+// namePattern matches the characters not allowed in cassette file names
+var namePattern = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// Path returns the cassette file of the named client in dir, e.g.
+// dir/aws.west.json for the adapter of the aws.west provider configuration
+func Path(dir, name string) string {
+	return filepath.Join(dir, namePattern.ReplaceAllString(name, "_")+".json")
+}
+
+// Open creates a recorder for the cassette at path. In replay mode the
+// cassette must exist; in record mode it is written by Save, replacing any
+// previous recording. base sends recorded requests and defaults to
+// http.DefaultTransport.
+func Open(mode Mode, path string, base http.RoundTripper) (*Recorder, error) {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	r := &Recorder{mode: mode, path: path, base: base}
+
+	switch mode {
+	case ModeRecord:
+	case ModeReplay:
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read cassette: %w", err)
+		}
+		if err := json.Unmarshal(data, &r.cassette); err != nil {
+			return nil, fmt.Errorf("failed to parse cassette %s: %w", path, err)
+		}
+		r.used = make([]bool, len(r.cassette.Interactions))
+	default:
+		return nil, fmt.Errorf("unknown cassette mode: %s", mode)
+	}
+
+	return r, nil
+}
+
+// Replaying reports whether the recorder serves requests from a cassette.
+// Adapters use it to skip credential lookups; it is false for a nil recorder.
+func (r *Recorder) Replaying() bool {
+	return r != nil && r.mode == ModeReplay
+}
+
+// Client returns an HTTP client that sends requests through the recorder
+func (r *Recorder) Client() *http.Client {
+	return &http.Client{Transport: r}
+}
+
+// Doer is an HTTP client, such as the one an SDK configures for itself
+type Doer interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// Transport adapts client to the http.RoundTripper a recorder sends
+// recorded requests with, keeping its TLS and proxy settings
+func Transport(client Doer) http.RoundTripper {
+	return doerTransport{client}
+}
+
+type doerTransport struct {
+	client Doer
+}
+
+func (t doerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	return t.client.Do(req)
+}
+
+// RoundTrip records or replays a request
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := readBody(req)
+	if err != nil {
+		return nil, fmt.Errorf("cassette: failed to read request body: %w", err)
+	}
+	recorded := sanitizeRequest(req, body)
+
+	if r.mode == ModeReplay {
+		return r.replay(req, recorded)
+	}
+
+	resp, err := r.base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	respBody, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("cassette: failed to read response body: %w", err)
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+
+	r.mu.Lock()
+	r.cassette.Interactions = append(r.cassette.Interactions, Interaction{
+		Request:  recorded,
+		Response: sanitizeResponse(resp, respBody),
+	})
+	r.mu.Unlock()
+
+	return resp, nil
+}
+
+// replay serves the first unused interaction recorded for an identical
+// request. Once all of them are used, the last one is served again, so
+// retried and repeated requests keep working.
+func (r *Recorder) replay(req *http.Request, recorded Request) (*http.Response, error) {
+	key := matchKey(recorded)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	match := -1
+	for i, interaction := range r.cassette.Interactions {
+		if matchKey(interaction.Request) != key {
+			continue
+		}
+		match = i
+		if !r.used[i] {
+			break
+		}
+	}
+	if match < 0 {
+		return nil, fmt.Errorf("cassette: no interaction recorded for %s %s in %s", recorded.Method, recorded.URL, r.path)
+	}
+	r.used[match] = true
+
+	response := r.cassette.Interactions[match].Response
+	body, err := decodeBody(response.Body, response.BodyEncoding)
+	if err != nil {
+		return nil, fmt.Errorf("cassette: invalid response body in %s: %w", r.path, err)
+	}
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", response.StatusCode, http.StatusText(response.StatusCode)),
+		StatusCode:    response.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        response.Headers.Clone(),
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}, nil
+}
+
+// Save writes the recorded interactions to the cassette file. Interactions are
+// sorted by request so that re-recording produces small diffs. Save does
+// nothing in replay mode.
+func (r *Recorder) Save() error {
+	if r.mode != ModeRecord {
+		return nil
+	}
+
+	r.mu.Lock()
+	cassette := Cassette{Interactions: append([]Interaction{}, r.cassette.Interactions...)}
+	r.mu.Unlock()
+
+	sort.SliceStable(cassette.Interactions, func(i, j int) bool {
+		return matchKey(cassette.Interactions[i].Request) < matchKey(cassette.Interactions[j].Request)
+	})
+
+	// Keep XML and HTML bodies readable
+	var data bytes.Buffer
+	encoder := json.NewEncoder(&data)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(cassette); err != nil {
+		return fmt.Errorf("failed to encode cassette: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(r.path), 0o755); err != nil {
+		return fmt.Errorf("failed to create cassette directory: %w", err)
+	}
+	if err := os.WriteFile(r.path, data.Bytes(), 0o644); err != nil {
+		return fmt.Errorf("failed to write cassette: %w", err)
+	}
+
+	return nil
+}
+
+// matchKey identifies the requests a recorded interaction answers
+func matchKey(req Request) string {
+	return req.Method + " " + req.URL + " " + req.Headers.Get("X-Amz-Target") + "\n" + req.Body
+}
+
+// readBody reads a request body and restores it for sending
+func readBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+
+	body, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	return body, nil
+}
+
+// encodeBody stores a body as text, or as base64 when it is not valid UTF-8
+func encodeBody(body []byte) (string, string) {
+	if utf8.Valid(body) {
+		return string(body), ""
+	}
+	return base64.StdEncoding.EncodeToString(body), "base64"
+}
+
+func decodeBody(body, encoding string) ([]byte, error) {
+	switch encoding {
+	case "":
+		return []byte(body), nil
+	case "base64":
+		return base64.StdEncoding.DecodeString(body)
+	default:
+		return nil, fmt.Errorf("unknown body encoding: %s", encoding)
+	}
+}
@@ -0,0 +1,138 @@
+package cassette
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func get(t *testing.T, client *http.Client, url string) (int, string) {
+	t.Helper()
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	require.NoError(t, err)
+	req.Header.Set("Authorization", "Bearer live-token")
+
+	resp, err := client.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return resp.StatusCode, string(body)
+}
+
+func TestRecorder_RecordAndReplay(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("Set-Cookie", "session=live")
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/buckets":
+			_, _ = io.WriteString(w, `{"items": ["logs"], "nextPageToken": "page-2"}`)
+		case "/token":
+			_, _ = io.WriteString(w, `{"access_token": "live-token", "expires_in": 3600}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	path := Path(t.TempDir(), "aws.west")
+	assert.Equal(t, "aws.west.json", filepath.Base(path))
+
+	recorder, err := Open(ModeRecord, path, nil)
+	require.NoError(t, err)
+	assert.False(t, recorder.Replaying())
+
+	client := recorder.Client()
+	status, body := get(t, client, server.URL+"/buckets?X-Amz-Signature=live-signature")
+	assert.Equal(t, http.StatusOK, status)
+	assert.Contains(t, body, "page-2")
+	_, body = get(t, client, server.URL+"/token")
+	assert.Contains(t, body, "live-token", "the live response is passed through unredacted")
+	status, _ = get(t, client, server.URL+"/missing")
+	assert.Equal(t, http.StatusNotFound, status)
+	require.NoError(t, recorder.Save())
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "live-token")
+	assert.NotContains(t, string(data), "live-signature")
+	assert.NotContains(t, string(data), "session=live")
+	assert.Contains(t, string(data), "page-2", "pagination tokens are kept")
+
+	// Replay without the server
+	server.Close()
+	callsBefore := calls
+
+	replayer, err := Open(ModeReplay, path, nil)
+	require.NoError(t, err)
+	assert.True(t, replayer.Replaying())
+
+	client = replayer.Client()
+	status, body = get(t, client, server.URL+"/buckets?X-Amz-Signature=other-signature")
+	assert.Equal(t, http.StatusOK, status)
+	assert.JSONEq(t, `{"items": ["logs"], "nextPageToken": "page-2"}`, body)
+
+	// Repeated requests replay the last matching interaction
+	status, _ = get(t, client, server.URL+"/buckets?X-Amz-Signature=other-signature")
+	assert.Equal(t, http.StatusOK, status)
+
+	_, body = get(t, client, server.URL+"/token")
+	assert.JSONEq(t, `{"access_token": "REDACTED", "expires_in": 3600}`, body)
+
+	status, _ = get(t, client, server.URL+"/missing")
+	assert.Equal(t, http.StatusNotFound, status)
+
+	_, err = client.Get(server.URL + "/unrecorded")
+	assert.ErrorContains(t, err, "no interaction recorded for GET")
+	assert.Equal(t, callsBefore, calls)
+	assert.NoError(t, replayer.Save(), "Save does nothing when replaying")
+}
+
+func TestRecorder_ReplayMatchesBody(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ec2.json")
+	cassette := `{"interactions": [
+  {"request": {"method": "POST", "url": "https://ec2.us-east-1.amazonaws.com/", "body": "Action=DescribeInstances&Version=2016-11-15"},
+   "response": {"status_code": 200, "body": "<DescribeInstancesResponse/>"}},
+  {"request": {"method": "POST", "url": "https://ec2.us-east-1.amazonaws.com/", "body": "Action=DescribeRegions&Version=2016-11-15"},
+   "response": {"status_code": 200, "body": "<DescribeRegionsResponse/>"}}
+]}`
+	require.NoError(t, os.WriteFile(path, []byte(cassette), 0o644))
+
+	replayer, err := Open(ModeReplay, path, nil)
+	require.NoError(t, err)
+
+	resp, err := replayer.Client().Post("https://ec2.us-east-1.amazonaws.com/", "application/x-www-form-urlencoded",
+		strings.NewReader("Action=DescribeRegions&Version=2016-11-15"))
+	require.NoError(t, err)
+	body, _ := io.ReadAll(resp.Body)
+	assert.Equal(t, "<DescribeRegionsResponse/>", string(body))
+
+	_, err = Open(ModeReplay, filepath.Join(t.TempDir(), "missing.json"), nil)
+	assert.ErrorContains(t, err, "failed to read cassette")
+}
+
+func TestSanitizeBody(t *testing.T) {
+	xml := `<Credentials><AccessKeyId>AKIA</AccessKeyId><SessionToken>secret</SessionToken><Expiration>soon</Expiration></Credentials>`
+	assert.Equal(t,
+		`<Credentials><AccessKeyId>REDACTED</AccessKeyId><SessionToken>REDACTED</SessionToken><Expiration>soon</Expiration></Credentials>`,
+		string(sanitizeBody("text/xml", []byte(xml))))
+
+	form := "Action=AssumeRole&Password=hunter2"
+	assert.Equal(t, "Action=AssumeRole&Password=REDACTED", string(sanitizeBody("application/x-www-form-urlencoded", []byte(form))))
+
+	unchanged := `{"name": "logs",  "tags": {"Owner": "platform"}}`
+	assert.Equal(t, unchanged, string(sanitizeBody("application/json", []byte(unchanged))))
+
+	nested := `{"properties": {"adminPassword": "hunter2", "size": 3}}`
+	assert.JSONEq(t, `{"properties": {"adminPassword": "REDACTED", "size": 3}}`, string(sanitizeBody("application/json", []byte(nested))))
+}
@@ -0,0 +1,179 @@
+package cassette
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// redacted replaces secrets in cassettes
+const redacted = "REDACTED"
+
+// recordedRequestHeaders are the request headers kept in cassettes. The rest,
+// including Authorization and the request signing headers, are dropped.
+var recordedRequestHeaders = []string{"Accept", "Content-Type", "X-Amz-Target"}
+
+// droppedResponseHeaders are the response headers left out of cassettes
+var droppedResponseHeaders = []string{"Set-Cookie", "Authorization", "X-Amz-Security-Token"}
+
+// xmlElementPattern matches XML elements holding text, e.g. <SessionToken>..</SessionToken>
+var xmlElementPattern = regexp.MustCompile(`<([A-Za-z0-9_:]+)>([^<]*)</([A-Za-z0-9_:]+)>`)
+
+// sensitiveName reports whether a JSON field or XML element holds a secret.
+// Pagination tokens are kept so that paged listings replay faithfully.
+func sensitiveName(name string) bool {
+	name = strings.ToLower(strings.TrimPrefix(name, "$"))
+	for _, marker := range []string{"next", "page", "continuation", "skip"} {
+		if strings.Contains(name, marker) {
+			return false
+		}
+	}
+	for _, marker := range []string{"secret", "password", "token", "signature", "credential", "accesskey", "privatekey", "connectionstring"} {
+		if strings.Contains(name, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+// sensitiveParam reports whether a query parameter or form field holds a
+// secret, like the API key of a URL or the signature of a SAS token
+func sensitiveParam(name string) bool {
+	switch strings.ToLower(name) {
+	case "sig", "key", "code":
+		return true
+	}
+	return sensitiveName(name)
+}
+
+func sanitizeRequest(req *http.Request, body []byte) Request {
+	recorded := Request{
+		Method: req.Method,
+		URL:    sanitizeURL(req.URL),
+	}
+
+	for _, name := range recordedRequestHeaders {
+		if value := req.Header.Get(name); value != "" {
+			if recorded.Headers == nil {
+				recorded.Headers = make(http.Header)
+			}
+			recorded.Headers.Set(name, value)
+		}
+	}
+
+	recorded.Body, recorded.BodyEncoding = encodeBody(sanitizeBody(req.Header.Get("Content-Type"), body))
+	return recorded
+}
+
+func sanitizeResponse(resp *http.Response, body []byte) Response {
+	recorded := Response{
+		StatusCode: resp.StatusCode,
+		Headers:    resp.Header.Clone(),
+	}
+	for _, name := range droppedResponseHeaders {
+		recorded.Headers.Del(name)
+	}
+
+	recorded.Body, recorded.BodyEncoding = encodeBody(sanitizeBody(resp.Header.Get("Content-Type"), body))
+	return recorded
+}
+
+// sanitizeURL drops user info and redacts secret query parameters, such as
+// the signature of a presigned URL
+func sanitizeURL(u *url.URL) string {
+	clean := *u
+	clean.User = nil
+	if query, changed := redactValues(clean.RawQuery); changed {
+		clean.RawQuery = query
+	}
+	return clean.String()
+}
+
+// sanitizeBody redacts secrets in JSON, XML and form encoded bodies. Bodies
+// without secrets are kept byte for byte.
+func sanitizeBody(contentType string, body []byte) []byte {
+	if len(body) == 0 {
+		return body
+	}
+
+	if strings.HasPrefix(contentType, "application/x-www-form-urlencoded") {
+		if form, changed := redactValues(string(body)); changed {
+			return []byte(form)
+		}
+		return body
+	}
+
+	trimmed := bytes.TrimSpace(body)
+	if len(trimmed) > 0 && (trimmed[0] == '{' || trimmed[0] == '[') {
+		decoder := json.NewDecoder(bytes.NewReader(body))
+		decoder.UseNumber()
+		var value interface{}
+		if err := decoder.Decode(&value); err == nil && redactJSON(value) {
+			if data, err := json.Marshal(value); err == nil {
+				return data
+			}
+		}
+		return body
+	}
+
+	if len(trimmed) > 0 && trimmed[0] == '<' {
+		return xmlElementPattern.ReplaceAllFunc(body, func(element []byte) []byte {
+			parts := xmlElementPattern.FindSubmatch(element)
+			if string(parts[1]) != string(parts[3]) || !sensitiveName(string(parts[1])) {
+				return element
+			}
+			return []byte("<" + string(parts[1]) + ">" + redacted + "</" + string(parts[1]) + ">")
+		})
+	}
+
+	return body
+}
+
+// redactValues redacts the secret values of a URL encoded query or form
+func redactValues(encoded string) (string, bool) {
+	values, err := url.ParseQuery(encoded)
+	if err != nil {
+		return encoded, false
+	}
+
+	changed := false
+	for name, list := range values {
+		if !sensitiveParam(name) {
+			continue
+		}
+		for i := range list {
+			list[i] = redacted
+		}
+		changed = true
+	}
+	if !changed {
+		return encoded, false
+	}
+	return values.Encode(), true
+}
+
+// redactJSON redacts the secret fields of a decoded JSON value in place
+func redactJSON(value interface{}) bool {
+	changed := false
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for name, field := range v {
+			if _, isString := field.(string); isString && sensitiveName(name) {
+				v[name] = redacted
+				changed = true
+			} else if redactJSON(field) {
+				changed = true
+			}
+		}
+	case []interface{}:
+		for _, item := range v {
+			if redactJSON(item) {
+				changed = true
+			}
+		}
+	}
+	return changed
+}
@@ -0,0 +1,165 @@
+package cloud
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Sources of rule results: policies evaluated against the planned values of a
+// resource, or by its cloud adapter against the deployed resource
+const (
+	SourcePlanned = "planned"
+	SourceLive    = "live"
+)
+
+// LiveCheck answers a policy condition from the live configuration an adapter
+// read for a deployed resource: whether the resource meets the expected value
+// of the condition, and why not. ok is false when the live configuration does
+// not tell, e.g. the adapter could not read the property.
+type LiveCheck func(live map[string]interface{}, expected interface{}) (passed bool, details []string, ok bool)
+
+// LiveChecks are the policy conditions an adapter answers for a resource
+// type, by condition: a built-in condition such as public_access.blocked, or
+// a Terraform attribute such as min_tls_version
+type LiveChecks map[string]LiveCheck
+
+// Check evaluates the rules against the live configuration of a deployed
+// resource: the properties read by status, which is only called when a rule
+// has a condition the checks answer. A resource that does not exist has no
+// results; drift detection reports it.
+func (c LiveChecks) Check(rules []ValidationRule, status func() (*ResourceStatus, error)) ([]ValidationResult, error) {
+	if !c.Applies(rules) {
+		return []ValidationResult{}, nil
+	}
+
+	actual, err := status()
+	if err != nil {
+		return nil, err
+	}
+	return c.Results(rules, actual), nil
+}
+
+// Applies reports whether any of the rules has a condition the checks answer
+func (c LiveChecks) Applies(rules []ValidationRule) bool {
+	for _, rule := range rules {
+		if len(c.conditions(rule)) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// Results evaluates the rules against the live configuration of a resource
+// already read; a resource that does not exist has no results
+func (c LiveChecks) Results(rules []ValidationRule, status *ResourceStatus) []ValidationResult {
+	if !status.Exists {
+		return []ValidationResult{}
+	}
+	return c.Evaluate(rules, status.Properties)
+}
+
+// Evaluate checks the rules against the live configuration of a resource.
+// Only the top-level conditions of a rule that have a live check are
+// evaluated; operators and nested all/any/not blocks are left to the planned
+// evaluation, and rules none of whose conditions the live configuration
+// answers have no live result.
+func (c LiveChecks) Evaluate(rules []ValidationRule, live map[string]interface{}) []ValidationResult {
+	results := make([]ValidationResult, 0)
+	for _, rule := range rules {
+		conditions := c.conditions(rule)
+		if len(conditions) == 0 {
+			continue
+		}
+
+		result := ValidationResult{
+			RuleName:    rule.Name,
+			Severity:    rule.Severity,
+			Passed:      true,
+			Message:     rule.Message,
+			Remediation: rule.Remediation,
+			Source:      SourceLive,
+		}
+		answered := false
+		for _, condition := range conditions {
+			passed, details, ok := c[condition](live, rule.Conditions[condition])
+			if !ok {
+				continue
+			}
+			answered = true
+			if !passed {
+				result.Passed = false
+				result.Details = append(result.Details, details...)
+			}
+		}
+		if answered {
+			results = append(results, result)
+		}
+	}
+	return results
+}
+
+// conditions returns the sorted conditions of a rule the checks answer
+func (c LiveChecks) conditions(rule ValidationRule) []string {
+	var conditions []string
+	for condition, expected := range rule.Conditions {
+		if _, ok := c[condition]; !ok {
+			continue
+		}
+		switch expected.(type) {
+		case map[string]interface{}, []interface{}:
+			continue
+		}
+		conditions = append(conditions, condition)
+	}
+	sort.Strings(conditions)
+	return conditions
+}
+
+// LiveChecks returns checks of the attribute conditions of a resource type
+// against the properties the differ maps them to, compared like drift, along
+// with the adapter's checks of built-in conditions
+func (d Differ) LiveChecks(checks LiveChecks) LiveChecks {
+	result := make(LiveChecks, len(d.Attributes)+len(checks))
+	for _, mapping := range d.Attributes {
+		result[mapping.Attribute] = attributeCheck(mapping)
+	}
+	for condition, check := range checks {
+		result[condition] = check
+	}
+	return result
+}
+
+// attributeCheck answers an attribute condition with the live property the
+// attribute is mapped to
+func attributeCheck(mapping AttributeMapping) LiveCheck {
+	equal := mapping.Equal
+	if equal == nil {
+		equal = EqualValues
+	}
+	return func(live map[string]interface{}, expected interface{}) (bool, []string, bool) {
+		actual, ok := live[mapping.Property]
+		if !ok {
+			return false, nil, false
+		}
+		actual = normalizeValue(actual)
+		if equal(expected, actual) {
+			return true, nil, true
+		}
+		return false, []string{fmt.Sprintf("Deployed attribute '%s' has value '%v', expected '%v'", mapping.Attribute, displayValue(actual), expected)}, true
+	}
+}
+
+// EnabledCheck answers a built-in condition such as encryption.enabled with a
+// live boolean property; the condition only requires anything when true
+func EnabledCheck(property, failure string) LiveCheck {
+	return func(live map[string]interface{}, expected interface{}) (bool, []string, bool) {
+		enabled, ok := live[property].(bool)
+		if !ok {
+			return false, nil, false
+		}
+		if required, _ := expected.(bool); !required || enabled {
+			return true, nil, true
+		}
+		return false, []string{failure}, true
+	}
+}
@@ -0,0 +1,69 @@
+package cloud
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLiveChecks_Check(t *testing.T) {
+	checks := Differ{Attributes: []AttributeMapping{
+		{Attribute: "min_tls_version", Property: "min_tls_version"},
+		{Attribute: "location", Property: "location", Equal: EqualFold},
+		{Attribute: "sku", Property: "sku"}, // not read by the adapter
+	}}.LiveChecks(LiveChecks{
+		"encryption.enabled": EnabledCheck("encryption_enabled", "Encryption is not enabled"),
+	})
+
+	rules := []ValidationRule{
+		{Name: "tls", Severity: "error", Message: "TLS 1.2 required", Conditions: map[string]interface{}{
+			"min_tls_version": "TLS1_2",
+			"location":        "West Europe",
+		}},
+		{Name: "encrypted", Severity: "warning", Conditions: map[string]interface{}{"encryption.enabled": true}},
+		{Name: "tls-in", Conditions: map[string]interface{}{"min_tls_version": map[string]interface{}{"in": []interface{}{"TLS1_2"}}}},
+		{Name: "tagged", Conditions: map[string]interface{}{"tags.required": []interface{}{"Owner"}}},
+		{Name: "sku", Conditions: map[string]interface{}{"sku": "Standard"}},
+	}
+
+	reads := 0
+	results, err := checks.Check(rules, func() (*ResourceStatus, error) {
+		reads++
+		return &ResourceStatus{Exists: true, Properties: map[string]interface{}{
+			"min_tls_version":    "TLS1_0",
+			"location":           "westeurope",
+			"encryption_enabled": true,
+		}}, nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 1, reads)
+
+	// Operators, built-in conditions without a live check and properties the
+	// adapter did not read are left to the planned evaluation
+	assert.Equal(t, []ValidationResult{
+		{RuleName: "tls", Severity: "error", Message: "TLS 1.2 required", Source: SourceLive,
+			Details: []string{"Deployed attribute 'min_tls_version' has value 'TLS1_0', expected 'TLS1_2'"}},
+		{RuleName: "encrypted", Severity: "warning", Passed: true, Source: SourceLive},
+	}, results)
+}
+
+func TestLiveChecks_CheckSkipsRead(t *testing.T) {
+	checks := LiveChecks{"encryption.enabled": EnabledCheck("encryption_enabled", "Encryption is not enabled")}
+	read := func() (*ResourceStatus, error) { return nil, errors.New("unexpected read") }
+
+	// No rule the checks answer, so the resource is not read
+	results, err := checks.Check([]ValidationRule{{Name: "tagged", Conditions: map[string]interface{}{"tags.required": []interface{}{"Owner"}}}}, read)
+	require.NoError(t, err)
+	assert.Empty(t, results)
+
+	rules := []ValidationRule{{Name: "encrypted", Conditions: map[string]interface{}{"encryption.enabled": true}}}
+	_, err = checks.Check(rules, read)
+	assert.EqualError(t, err, "unexpected read")
+
+	// A resource that does not exist has no results
+	results, err = checks.Check(rules, func() (*ResourceStatus, error) { return &ResourceStatus{Exists: false}, nil })
+	require.NoError(t, err)
+	assert.Empty(t, results)
+}
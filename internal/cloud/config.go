@@ -0,0 +1,148 @@
+package cloud
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/vijayaxai/terraship/internal/cloud/cassette"
+)
+
+// EndpointServices are the services whose endpoints each provider's adapter
+// can override, the keys of CloudConfig.Endpoints
+var EndpointServices = map[Provider][]string{
+	ProviderAWS:   {"ec2", "s3", "iam", "rds", "lambda", "kms", "dynamodb", "cloudtrail"},
+	ProviderAzure: {"resourcemanager", "blob"},
+	ProviderGCP:   {"compute", "storage", "sql", "kms", "container", "resource_manager"},
+}
+
+// Endpoint returns the base URL a service is called at, or "" for its public
+// endpoint: the service's entry of Endpoints, or else EndpointURL
+func (c CloudConfig) Endpoint(service string) string {
+	if endpoint := c.Endpoints[service]; endpoint != "" {
+		return endpoint
+	}
+	return c.EndpointURL
+}
+
+// WithProviderBlock returns the adapter configuration for a terraform provider
+// block: c, with the settings the block's constant arguments name overriding
+// it, so that aliased blocks pointing at other regions, projects or
+// subscriptions get matching adapters. Arguments the adapter has no setting
+// for are ignored.
+func (c CloudConfig) WithProviderBlock(provider Provider, settings map[string]interface{}) CloudConfig {
+	set := func(field *string, name string) {
+		if value, _ := settings[name].(string); value != "" {
+			*field = value
+		}
+	}
+
+	config := c
+	config.Provider = provider
+	set(&config.Region, "region")
+
+	switch provider {
+	case ProviderAWS:
+		set(&config.AWSRegion, "region")
+		set(&config.AWSProfile, "profile")
+		if config.AWSRegion == "" {
+			config.AWSRegion = config.Region
+		}
+		// endpoints { s3 = "http://localhost:4566" }
+		if blocks, ok := settings["endpoints"].([]interface{}); ok {
+			for _, block := range blocks {
+				endpoints, _ := block.(map[string]interface{})
+				for service, value := range endpoints {
+					config.setEndpoint(service, value)
+				}
+			}
+		}
+	case ProviderAzure:
+		set(&config.AzureSubscriptionID, "subscription_id")
+		set(&config.AzureTenantID, "tenant_id")
+	case ProviderGCP:
+		set(&config.GCPProject, "project")
+		// compute_custom_endpoint, storage_custom_endpoint
+		for name, value := range settings {
+			if service := strings.TrimSuffix(name, "_custom_endpoint"); service != name {
+				config.setEndpoint(service, value)
+			}
+		}
+	}
+
+	return config
+}
+
+// setEndpoint overrides the endpoint of a service the adapter calls. The
+// Endpoints map is copied first, since configurations share it.
+func (c *CloudConfig) setEndpoint(service string, value interface{}) {
+	endpoint, _ := value.(string)
+	if endpoint == "" || !supportsEndpoint(c.Provider, service) {
+		return
+	}
+
+	endpoints := make(map[string]string, len(c.Endpoints)+1)
+	for name, url := range c.Endpoints {
+		endpoints[name] = url
+	}
+	endpoints[service] = endpoint
+	c.Endpoints = endpoints
+}
+
+// supportsEndpoint reports whether a provider's adapter calls a service
+func supportsEndpoint(provider Provider, service string) bool {
+	for _, name := range EndpointServices[provider] {
+		if name == service {
+			return true
+		}
+	}
+	return false
+}
+
+// Transport returns the HTTP transport adapters send their SDK traffic
+// through, or nil to keep the SDK's own: base, waiting for the RateLimiter
+// before each request, under the cassette recorder when RecordDir or
+// ReplayDir is set. Replayed requests are not sent, so they do not wait. base
+// nil uses http.DefaultTransport.
+func (c CloudConfig) Transport(base http.RoundTripper) (http.RoundTripper, *cassette.Recorder, error) {
+	if c.RateLimiter != nil {
+		if base == nil {
+			base = http.DefaultTransport
+		}
+		base = rateLimitedTransport{base: base, limiter: c.RateLimiter}
+	}
+
+	recorder, err := c.Recorder(base)
+	if err != nil {
+		return nil, nil, err
+	}
+	switch {
+	case recorder != nil:
+		return recorder, recorder, nil
+	case c.RateLimiter != nil:
+		return base, nil, nil
+	default:
+		return nil, nil, nil
+	}
+}
+
+// Recorder returns the cassette recorder adapters send their SDK HTTP traffic
+// through, or nil when neither RecordDir nor ReplayDir is set. base sends
+// recorded requests; nil uses http.DefaultTransport.
+func (c CloudConfig) Recorder(base http.RoundTripper) (*cassette.Recorder, error) {
+	name := c.CassetteName
+	if name == "" {
+		name = string(c.Provider)
+	}
+
+	switch {
+	case c.RecordDir != "" && c.ReplayDir != "":
+		return nil, fmt.Errorf("cloud API traffic cannot be recorded and replayed at the same time")
+	case c.RecordDir != "":
+		return cassette.Open(cassette.ModeRecord, cassette.Path(c.RecordDir, name), base)
+	case c.ReplayDir != "":
+		return cassette.Open(cassette.ModeReplay, cassette.Path(c.ReplayDir, name), base)
+	default:
+		return nil, nil
+	}
+}
@@ -0,0 +1,65 @@
+package cloud
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCloudConfig_WithProviderBlock(t *testing.T) {
+	aws := CloudConfig{}.WithProviderBlock(ProviderAWS, map[string]interface{}{"region": "us-west-2", "profile": "prod"})
+	assert.Equal(t, "us-west-2", aws.AWSRegion)
+	assert.Equal(t, "prod", aws.AWSProfile)
+
+	gcp := CloudConfig{}.WithProviderBlock(ProviderGCP, map[string]interface{}{"project": "acme", "region": "europe-west1"})
+	assert.Equal(t, "acme", gcp.GCPProject)
+	assert.Equal(t, "europe-west1", gcp.Region)
+	assert.Empty(t, gcp.AWSRegion)
+
+	azure := CloudConfig{}.WithProviderBlock(ProviderAzure, map[string]interface{}{"subscription_id": "sub", "features": []interface{}{}})
+	assert.Equal(t, "sub", azure.AzureSubscriptionID)
+
+	empty := CloudConfig{}.WithProviderBlock(ProviderAWS, nil)
+	assert.Equal(t, CloudConfig{Provider: ProviderAWS}, empty)
+}
+
+func TestCloudConfig_WithProviderBlock_Base(t *testing.T) {
+	base := CloudConfig{Region: "eu-west-1", FakeFixture: "fixture.yaml"}
+
+	// The base region applies when the block sets none
+	aws := base.WithProviderBlock(ProviderAWS, nil)
+	assert.Equal(t, "eu-west-1", aws.AWSRegion)
+	assert.Equal(t, "fixture.yaml", aws.FakeFixture)
+
+	// The block's arguments take precedence
+	west := base.WithProviderBlock(ProviderAWS, map[string]interface{}{"region": "us-west-2"})
+	assert.Equal(t, "us-west-2", west.AWSRegion)
+	assert.Equal(t, "us-west-2", west.Region)
+}
+
+func TestCloudConfig_Endpoint(t *testing.T) {
+	config := CloudConfig{
+		EndpointURL: "http://localhost:4566",
+		Endpoints:   map[string]string{"s3": "http://localhost:9000"},
+	}
+	assert.Equal(t, "http://localhost:9000", config.Endpoint("s3"))
+	assert.Equal(t, "http://localhost:4566", config.Endpoint("ec2"))
+	assert.Empty(t, CloudConfig{}.Endpoint("ec2"))
+}
+
+func TestCloudConfig_WithProviderBlock_Endpoints(t *testing.T) {
+	base := CloudConfig{Endpoints: map[string]string{"ec2": "http://localhost:4566"}}
+
+	// terraform show -json renders the endpoints block as a list of blocks
+	aws := base.WithProviderBlock(ProviderAWS, map[string]interface{}{
+		"endpoints": []interface{}{map[string]interface{}{"s3": "http://localhost:9000", "sqs": "http://localhost:4566"}},
+	})
+	assert.Equal(t, map[string]string{"ec2": "http://localhost:4566", "s3": "http://localhost:9000"}, aws.Endpoints)
+	assert.Equal(t, map[string]string{"ec2": "http://localhost:4566"}, base.Endpoints, "the base configuration is not modified")
+
+	gcp := CloudConfig{}.WithProviderBlock(ProviderGCP, map[string]interface{}{
+		"storage_custom_endpoint": "http://localhost:4443/storage/v1/",
+	})
+	assert.Equal(t, "http://localhost:4443/storage/v1/", gcp.Endpoint("storage"))
+	assert.Empty(t, gcp.Endpoint("compute"))
+}
@@ -0,0 +1,227 @@
+package cloud
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// AttributeDrift is a planned attribute whose deployed value differs
+type AttributeDrift struct {
+	Attribute string      `json:"attribute"` // attribute path, e.g. instance_type or tags.Owner
+	Planned   interface{} `json:"planned"`
+	Actual    interface{} `json:"actual"` // nil for a missing tag
+	Message   string      `json:"message"`
+}
+
+// AttributeMapping maps a planned Terraform attribute to the property of
+// ResourceStatus.Properties an adapter reads its deployed value into
+type AttributeMapping struct {
+	Attribute string                                 // planned attribute path, e.g. versioning[0].enabled
+	Property  string                                 // key in ResourceStatus.Properties
+	Equal     func(planned, actual interface{}) bool // compares the values; nil compares them as strings
+}
+
+// Differ compares the planned attributes of a resource type with the tags and
+// properties of the deployed resource
+type Differ struct {
+	Tags       string // attribute path compared with ResourceStatus.Tags: tags (the default), labels or metadata
+	Attributes []AttributeMapping
+}
+
+// tagLabels name tag attributes in drift messages
+var tagLabels = map[string]string{
+	"tags":                    "Tag",
+	"labels":                  "Label",
+	"resource_labels":         "Label",
+	"settings[0].user_labels": "Label",
+	"metadata":                "Metadata",
+}
+
+// Diff returns the drifted attributes of a deployed resource, sorted by
+// attribute path. Planned tags that are missing or differ are drift; tags
+// only the deployed resource has are not, since clouds and tag policies add
+// their own. Mapped attributes are compared when the plan knows their value
+// and the adapter read the property.
+func (d Differ) Diff(planned map[string]interface{}, status *ResourceStatus) []AttributeDrift {
+	var drifts []AttributeDrift
+
+	tagAttribute := d.Tags
+	if tagAttribute == "" {
+		tagAttribute = "tags"
+	}
+	label := tagLabels[tagAttribute]
+	if label == "" {
+		label = tagAttribute
+	}
+	plannedValue, _ := AttributeValue(planned, tagAttribute)
+	if plannedTags, ok := plannedValue.(map[string]interface{}); ok {
+		for key, value := range plannedTags {
+			drift := AttributeDrift{Attribute: tagAttribute + "." + key, Planned: value}
+			if actual, exists := status.Tags[key]; !exists {
+				drift.Message = fmt.Sprintf("%s '%s' missing", label, key)
+			} else if fmt.Sprint(value) != actual {
+				drift.Actual = actual
+				drift.Message = fmt.Sprintf("%s '%s' differs: planned=%v, actual=%v", label, key, value, actual)
+			} else {
+				continue
+			}
+			drifts = append(drifts, drift)
+		}
+	}
+
+	for _, mapping := range d.Attributes {
+		plannedValue, ok := AttributeValue(planned, mapping.Attribute)
+		if !ok || plannedValue == nil {
+			continue // unknown until apply, or unset
+		}
+		actualValue, ok := status.Properties[mapping.Property]
+		if !ok {
+			continue
+		}
+
+		actualValue = normalizeValue(actualValue)
+		equal := mapping.Equal
+		if equal == nil {
+			equal = EqualValues
+		}
+		if equal(plannedValue, actualValue) {
+			continue
+		}
+
+		drifts = append(drifts, AttributeDrift{
+			Attribute: mapping.Attribute,
+			Planned:   plannedValue,
+			Actual:    actualValue,
+			Message:   fmt.Sprintf("Attribute '%s' differs: planned=%v, actual=%v", mapping.Attribute, displayValue(plannedValue), displayValue(actualValue)),
+		})
+	}
+
+	sort.SliceStable(drifts, func(i, j int) bool { return drifts[i].Attribute < drifts[j].Attribute })
+	return drifts
+}
+
+// SetDrift records the drifted attributes of a deployed resource
+func (s *ResourceStatus) SetDrift(drifts []AttributeDrift) {
+	s.Drifts = drifts
+	s.DriftDetails = nil
+	for _, drift := range drifts {
+		s.DriftDetails = append(s.DriftDetails, drift.Message)
+	}
+	s.DriftDetected = len(drifts) > 0
+}
+
+// IgnoreDrift drops the drifted attributes ignored reports true for. A
+// missing resource stays drifted.
+func (s *ResourceStatus) IgnoreDrift(ignored func(attribute string) bool) {
+	if !s.Exists {
+		return
+	}
+
+	kept := make([]AttributeDrift, 0, len(s.Drifts))
+	for _, drift := range s.Drifts {
+		if !ignored(drift.Attribute) {
+			kept = append(kept, drift)
+		}
+	}
+	if len(kept) < len(s.Drifts) {
+		s.SetDrift(kept)
+	}
+}
+
+// AttributeValue resolves an attribute path such as versioning[0].enabled in
+// planned attributes
+func AttributeValue(attributes map[string]interface{}, path string) (interface{}, bool) {
+	var current interface{} = attributes
+	for _, part := range strings.Split(path, ".") {
+		name, brackets := part, ""
+		if i := strings.Index(part, "["); i >= 0 {
+			name, brackets = part[:i], part[i:]
+		}
+
+		fields, ok := current.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		if current, ok = fields[name]; !ok {
+			return nil, false
+		}
+
+		for brackets != "" {
+			end := strings.Index(brackets, "]")
+			if brackets[0] != '[' || end < 0 {
+				return nil, false
+			}
+			index, err := strconv.Atoi(brackets[1:end])
+			list, ok := current.([]interface{})
+			if err != nil || !ok || index < 0 || index >= len(list) {
+				return nil, false
+			}
+			current = list[index]
+			brackets = brackets[end+1:]
+		}
+	}
+	return current, true
+}
+
+// EqualValues compares a planned and an actual value by their string forms,
+// so that e.g. the float64 numbers of plan JSON match integer properties.
+// nil equals the empty string.
+func EqualValues(planned, actual interface{}) bool {
+	return valueString(planned) == valueString(actual)
+}
+
+// EqualFold compares values case-insensitively, ignoring spaces, like Azure
+// locations (West Europe, westeurope)
+func EqualFold(planned, actual interface{}) bool {
+	normalize := func(value interface{}) string {
+		return strings.ReplaceAll(strings.ToLower(valueString(value)), " ", "")
+	}
+	return normalize(planned) == normalize(actual)
+}
+
+// EqualBaseName compares values by their last path segment, like the machine
+// type e2-medium and the URL of the machine type GCP returns
+func EqualBaseName(planned, actual interface{}) bool {
+	base := func(value interface{}) string {
+		s := valueString(value)
+		return s[strings.LastIndex(s, "/")+1:]
+	}
+	return base(planned) == base(actual)
+}
+
+// normalizeValue dereferences pointers and converts named string types, such
+// as SDK enums, to strings
+func normalizeValue(value interface{}) interface{} {
+	v := reflect.ValueOf(value)
+	for v.IsValid() && v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+	if !v.IsValid() {
+		return nil
+	}
+	if v.Kind() == reflect.String {
+		return v.String()
+	}
+	return v.Interface()
+}
+
+func valueString(value interface{}) string {
+	value = normalizeValue(value)
+	if value == nil {
+		return ""
+	}
+	return fmt.Sprint(value)
+}
+
+func displayValue(value interface{}) interface{} {
+	if value == nil {
+		return "null"
+	}
+	return value
+}
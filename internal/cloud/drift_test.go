@@ -0,0 +1,94 @@
+package cloud
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type instanceType string
+
+func TestDiffer_Diff(t *testing.T) {
+	privateIP := "10.0.1.12"
+	status := &ResourceStatus{
+		Exists: true,
+		Tags:   map[string]string{"Environment": "staging", "CreatedBy": "autoscaler"},
+		Properties: map[string]interface{}{
+			"instance_type":      instanceType("t3.large"),
+			"private_ip":         &privateIP,
+			"public_ip":          (*string)(nil),
+			"versioning_enabled": true,
+			"cpu_count":          int32(2),
+		},
+	}
+	planned := map[string]interface{}{
+		"instance_type": "t3.micro",
+		"private_ip":    "10.0.1.12",
+		"public_ip":     "",
+		"cpu_count":     float64(2),
+		"ami":           "ami-123",
+		"versioning":    []interface{}{map[string]interface{}{"enabled": false}},
+		"tags":          map[string]interface{}{"Environment": "production", "Owner": "platform"},
+	}
+
+	differ := Differ{Attributes: []AttributeMapping{
+		{Attribute: "instance_type", Property: "instance_type"},
+		{Attribute: "private_ip", Property: "private_ip"},
+		{Attribute: "public_ip", Property: "public_ip"},
+		{Attribute: "cpu_count", Property: "cpu_count"},
+		{Attribute: "versioning[0].enabled", Property: "versioning_enabled"},
+		{Attribute: "subnet_id", Property: "subnet_id"}, // unknown until apply
+	}}
+
+	drifts := differ.Diff(planned, status)
+	assert.Equal(t, []AttributeDrift{
+		{Attribute: "instance_type", Planned: "t3.micro", Actual: "t3.large",
+			Message: "Attribute 'instance_type' differs: planned=t3.micro, actual=t3.large"},
+		{Attribute: "tags.Environment", Planned: "production", Actual: "staging",
+			Message: "Tag 'Environment' differs: planned=production, actual=staging"},
+		{Attribute: "tags.Owner", Planned: "platform",
+			Message: "Tag 'Owner' missing"},
+		{Attribute: "versioning[0].enabled", Planned: false, Actual: true,
+			Message: "Attribute 'versioning[0].enabled' differs: planned=false, actual=true"},
+	}, drifts)
+
+	labels := Differ{Tags: "labels"}.Diff(map[string]interface{}{
+		"labels": map[string]interface{}{"env": "prod"},
+	}, &ResourceStatus{Exists: true})
+	assert.Equal(t, "Label 'env' missing", labels[0].Message)
+}
+
+func TestResourceStatus_IgnoreDrift(t *testing.T) {
+	status := &ResourceStatus{Exists: true, Properties: map[string]interface{}{"public_ip": "3.3.3.3"}}
+	status.SetDrift(Differ{Attributes: []AttributeMapping{{Attribute: "public_ip", Property: "public_ip"}}}.Diff(
+		map[string]interface{}{"public_ip": "1.1.1.1", "tags": map[string]interface{}{"Owner": "platform"}}, status))
+	assert.True(t, status.DriftDetected)
+	assert.Len(t, status.DriftDetails, 2)
+
+	status.IgnoreDrift(func(attribute string) bool { return attribute == "public_ip" })
+	assert.True(t, status.DriftDetected)
+	assert.Equal(t, []string{"Tag 'Owner' missing"}, status.DriftDetails)
+
+	status.IgnoreDrift(func(attribute string) bool { return strings.HasPrefix(attribute, "tags.") })
+	assert.False(t, status.DriftDetected)
+	assert.Empty(t, status.DriftDetails)
+
+	missing := &ResourceStatus{DriftDetected: true, DriftDetails: []string{"Resource does not exist in AWS"}}
+	missing.IgnoreDrift(func(string) bool { return true })
+	assert.True(t, missing.DriftDetected)
+}
+
+func TestEqualValues(t *testing.T) {
+	assert.True(t, EqualFold("West Europe", "westeurope"))
+	assert.True(t, EqualBaseName("e2-medium", "https://www.googleapis.com/compute/v1/projects/acme/zones/europe-west1-b/machineTypes/e2-medium"))
+	assert.False(t, EqualValues("t3.micro", "t3.large"))
+
+	value, ok := AttributeValue(map[string]interface{}{
+		"rule": []interface{}{map[string]interface{}{"days": float64(30)}},
+	}, "rule[0].days")
+	assert.True(t, ok)
+	assert.Equal(t, float64(30), value)
+	_, ok = AttributeValue(map[string]interface{}{"rule": []interface{}{}}, "rule[0].days")
+	assert.False(t, ok)
+}
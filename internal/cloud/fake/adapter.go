@@ -0,0 +1,248 @@
+// Package fake implements an in-memory cloud adapter for tests and demos.
+// Resources are seeded from a YAML or JSON fixture, so drift detection can run
+// without cloud credentials.
+package fake
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+
+	"github.com/vijayaxai/terraship/internal/cloud"
+	"gopkg.in/yaml.v3"
+)
+
+// Name is the provider name of the fake adapter, selected with --provider fake
+const Name cloud.Provider = "fake"
+
+// Resource is a cloud resource served by the fake adapter
+type Resource struct {
+	Type       string                 `yaml:"type" json:"type"`
+	ID         string                 `yaml:"id" json:"id"`
+	State      string                 `yaml:"state,omitempty" json:"state,omitempty"`
+	Tags       map[string]string      `yaml:"tags,omitempty" json:"tags,omitempty"`
+	Properties map[string]interface{} `yaml:"properties,omitempty" json:"properties,omitempty"`
+}
+
+// Fixture is the file format the fake adapter is seeded from:
+//
+//	resources:
+//	  - type: aws_s3_bucket
+//	    id: app-logs
+//	    tags:
+//	      Environment: production
+//	    properties:
+//	      acl: private
+type Fixture struct {
+	Resources []Resource `yaml:"resources" json:"resources"`
+}
+
+// Adapter implements cloud.Adapter over an in-memory set of resources
+type Adapter struct {
+	mu        sync.RWMutex
+	resources map[string]map[string]Resource // resource type -> ID -> resource
+}
+
+func init() {
+	cloud.Register(Name, func() cloud.Adapter { return NewAdapter() })
+}
+
+// NewAdapter creates a fake adapter serving resources
+func NewAdapter(resources ...Resource) *Adapter {
+	a := &Adapter{resources: make(map[string]map[string]Resource)}
+	for _, resource := range resources {
+		a.Add(resource)
+	}
+	return a
+}
+
+// LoadFixture reads the resources of a YAML or JSON fixture file
+func LoadFixture(path string) ([]Resource, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read fake fixture: %w", err)
+	}
+
+	// JSON is valid YAML, so one decoder handles both formats
+	var fixture Fixture
+	if err := yaml.Unmarshal(data, &fixture); err != nil {
+		return nil, fmt.Errorf("failed to parse fake fixture %s: %w", path, err)
+	}
+
+	for i, resource := range fixture.Resources {
+		if resource.Type == "" || resource.ID == "" {
+			return nil, fmt.Errorf("fake fixture %s: resource %d needs a type and an id", path, i)
+		}
+	}
+
+	return fixture.Resources, nil
+}
+
+// Add adds a resource, replacing any resource with the same type and ID
+func (a *Adapter) Add(resource Resource) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.resources[resource.Type] == nil {
+		a.resources[resource.Type] = make(map[string]Resource)
+	}
+	a.resources[resource.Type][resource.ID] = resource
+}
+
+// Remove deletes a resource, so drift detection reports it missing
+func (a *Adapter) Remove(resourceType, resourceID string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	delete(a.resources[resourceType], resourceID)
+}
+
+// Name returns the provider name
+func (a *Adapter) Name() cloud.Provider {
+	return Name
+}
+
+// Initialize seeds the adapter from the fixture named by the config, if any
+func (a *Adapter) Initialize(ctx context.Context, cloudConfig cloud.CloudConfig) error {
+	if cloudConfig.FakeFixture == "" {
+		return nil
+	}
+
+	resources, err := LoadFixture(cloudConfig.FakeFixture)
+	if err != nil {
+		return err
+	}
+	for _, resource := range resources {
+		a.Add(resource)
+	}
+
+	return nil
+}
+
+// DetectProvider never detects the fake adapter; it must be selected explicitly
+func (a *Adapter) DetectProvider(ctx context.Context) (bool, float64, error) {
+	return false, 0, nil
+}
+
+// ValidateCredentials always succeeds; the fake adapter needs no credentials
+func (a *Adapter) ValidateCredentials(ctx context.Context) error {
+	return nil
+}
+
+// GetResourceStatus returns the status of a fixture resource. Resources not in
+// the fixture are reported as not existing.
+func (a *Adapter) GetResourceStatus(ctx context.Context, resourceType, resourceID string) (*cloud.ResourceStatus, error) {
+	a.mu.RLock()
+	resource, ok := a.resources[resourceType][resourceID]
+	a.mu.RUnlock()
+
+	if !ok {
+		return &cloud.ResourceStatus{
+			ResourceID:   resourceID,
+			ResourceType: resourceType,
+			Exists:       false,
+		}, nil
+	}
+
+	status := &cloud.ResourceStatus{
+		ResourceID:   resourceID,
+		ResourceType: resourceType,
+		Exists:       true,
+		State:        resource.State,
+		Tags:         make(map[string]string, len(resource.Tags)),
+		Properties:   make(map[string]interface{}, len(resource.Properties)),
+	}
+	for key, value := range resource.Tags {
+		status.Tags[key] = value
+	}
+	for key, value := range resource.Properties {
+		status.Properties[key] = value
+	}
+
+	return status, nil
+}
+
+// ValidateResourceCompliance evaluates the attribute conditions of the rules
+// against the properties of the fixture resource
+func (a *Adapter) ValidateResourceCompliance(ctx context.Context, resourceType, resourceID string, rules []cloud.ValidationRule) ([]cloud.ValidationResult, error) {
+	a.mu.RLock()
+	resource := a.resources[resourceType][resourceID]
+	a.mu.RUnlock()
+
+	return propertyDiffer(resource.Properties).LiveChecks(nil).Check(rules, func() (*cloud.ResourceStatus, error) {
+		return a.GetResourceStatus(ctx, resourceType, resourceID)
+	})
+}
+
+// DetectDrift compares planned state with the fixture resource: planned tags
+// against its tags, and planned attributes against the properties it sets
+func (a *Adapter) DetectDrift(ctx context.Context, plannedState map[string]interface{}, resourceType, resourceID string) (*cloud.ResourceStatus, error) {
+	actualStatus, err := a.GetResourceStatus(ctx, resourceType, resourceID)
+	if err != nil {
+		return nil, err
+	}
+	return setDrift(plannedState, actualStatus), nil
+}
+
+// CheckResource reads the fixture resource once to evaluate the rules against
+// its properties and detect drift
+func (a *Adapter) CheckResource(ctx context.Context, plannedState map[string]interface{}, resourceType, resourceID string, rules []cloud.ValidationRule) (*cloud.ResourceStatus, []cloud.ValidationResult, error) {
+	actualStatus, err := a.GetResourceStatus(ctx, resourceType, resourceID)
+	if err != nil {
+		return nil, nil, err
+	}
+	results := propertyDiffer(actualStatus.Properties).LiveChecks(nil).Results(rules, actualStatus)
+	return setDrift(plannedState, actualStatus), results, nil
+}
+
+// setDrift records the drift of a fixture resource from the planned state
+func setDrift(plannedState map[string]interface{}, actualStatus *cloud.ResourceStatus) *cloud.ResourceStatus {
+	if !actualStatus.Exists {
+		actualStatus.DriftDetected = true
+		actualStatus.DriftDetails = []string{"Resource does not exist in fake cloud"}
+		return actualStatus
+	}
+
+	actualStatus.SetDrift(propertyDiffer(actualStatus.Properties).Diff(plannedState, actualStatus))
+	return actualStatus
+}
+
+// ListResources lists the IDs of the fixture resources of a given type, sorted
+func (a *Adapter) ListResources(ctx context.Context, resourceType string) ([]string, error) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	ids := make([]string, 0, len(a.resources[resourceType]))
+	for id := range a.resources[resourceType] {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	return ids, nil
+}
+
+// Close cleans up fake adapter resources
+func (a *Adapter) Close() error {
+	return nil
+}
+
+// propertyDiffer maps every property a fixture resource sets to the planned
+// attribute of that name or path
+func propertyDiffer(properties map[string]interface{}) cloud.Differ {
+	var differ cloud.Differ
+	for _, key := range sortedKeys(properties) {
+		differ.Attributes = append(differ.Attributes, cloud.AttributeMapping{Attribute: key, Property: key})
+	}
+	return differ
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,159 @@
+package fake
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vijayaxai/terraship/internal/cloud"
+)
+
+func writeFixture(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	return path
+}
+
+func TestLoadFixture(t *testing.T) {
+	yamlPath := writeFixture(t, "fixture.yaml", `
+resources:
+  - type: aws_s3_bucket
+    id: app-logs
+    tags:
+      Environment: production
+    properties:
+      acl: private
+`)
+	resources, err := LoadFixture(yamlPath)
+	require.NoError(t, err)
+	require.Len(t, resources, 1)
+	assert.Equal(t, "app-logs", resources[0].ID)
+	assert.Equal(t, "production", resources[0].Tags["Environment"])
+	assert.Equal(t, "private", resources[0].Properties["acl"])
+
+	jsonPath := writeFixture(t, "fixture.json", `{"resources": [{"type": "aws_instance", "id": "i-123", "state": "running"}]}`)
+	resources, err = LoadFixture(jsonPath)
+	require.NoError(t, err)
+	require.Len(t, resources, 1)
+	assert.Equal(t, "running", resources[0].State)
+
+	invalid := writeFixture(t, "invalid.yaml", "resources:\n  - type: aws_instance\n")
+	_, err = LoadFixture(invalid)
+	assert.ErrorContains(t, err, "needs a type and an id")
+}
+
+func TestAdapter_InitializeFromRegistry(t *testing.T) {
+	path := writeFixture(t, "fixture.yaml", "resources:\n  - {type: aws_instance, id: i-123}\n  - {type: aws_instance, id: i-001}\n")
+
+	adapter, err := cloud.New(Name)
+	require.NoError(t, err)
+	require.NoError(t, adapter.Initialize(context.Background(), cloud.CloudConfig{Provider: Name, FakeFixture: path}))
+	require.NoError(t, adapter.ValidateCredentials(context.Background()))
+
+	ids, err := adapter.ListResources(context.Background(), "aws_instance")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"i-001", "i-123"}, ids)
+
+	ids, err = adapter.ListResources(context.Background(), "aws_s3_bucket")
+	require.NoError(t, err)
+	assert.Empty(t, ids)
+}
+
+func TestAdapter_DetectDrift(t *testing.T) {
+	ctx := context.Background()
+	adapter := NewAdapter(Resource{
+		Type:       "aws_s3_bucket",
+		ID:         "app-logs",
+		Tags:       map[string]string{"Environment": "staging"},
+		Properties: map[string]interface{}{"acl": "public-read", "force_destroy": false},
+	})
+
+	planned := map[string]interface{}{
+		"bucket":        "app-logs",
+		"acl":           "private",
+		"force_destroy": false,
+		"tags":          map[string]interface{}{"Environment": "production", "Owner": "platform"},
+	}
+	status, err := adapter.DetectDrift(ctx, planned, "aws_s3_bucket", "app-logs")
+	require.NoError(t, err)
+	assert.True(t, status.Exists)
+	assert.True(t, status.DriftDetected)
+	assert.Equal(t, []string{
+		"Attribute 'acl' differs: planned=private, actual=public-read",
+		"Tag 'Environment' differs: planned=production, actual=staging",
+		"Tag 'Owner' missing",
+	}, status.DriftDetails)
+	assert.Equal(t, cloud.AttributeDrift{
+		Attribute: "acl",
+		Planned:   "private",
+		Actual:    "public-read",
+		Message:   "Attribute 'acl' differs: planned=private, actual=public-read",
+	}, status.Drifts[0])
+
+	inSync := map[string]interface{}{
+		"acl":  "public-read",
+		"tags": map[string]interface{}{"Environment": "staging"},
+	}
+	status, err = adapter.DetectDrift(ctx, inSync, "aws_s3_bucket", "app-logs")
+	require.NoError(t, err)
+	assert.False(t, status.DriftDetected)
+
+	adapter.Remove("aws_s3_bucket", "app-logs")
+	status, err = adapter.DetectDrift(ctx, inSync, "aws_s3_bucket", "app-logs")
+	require.NoError(t, err)
+	assert.False(t, status.Exists)
+	assert.True(t, status.DriftDetected)
+}
+
+func TestAdapter_ValidateResourceCompliance(t *testing.T) {
+	ctx := context.Background()
+	adapter := NewAdapter(Resource{
+		Type:       "aws_s3_bucket",
+		ID:         "app-logs",
+		Properties: map[string]interface{}{"acl": "public-read"},
+	})
+
+	rules := []cloud.ValidationRule{
+		{Name: "private-acl", Severity: "error", Conditions: map[string]interface{}{"acl": "private"}},
+		{Name: "versioned", Severity: "warning", Conditions: map[string]interface{}{"versioning.enabled": true}},
+	}
+	results, err := adapter.ValidateResourceCompliance(ctx, "aws_s3_bucket", "app-logs", rules)
+	require.NoError(t, err)
+	require.Len(t, results, 1, "only properties of the fixture are checked")
+	assert.Equal(t, "private-acl", results[0].RuleName)
+	assert.False(t, results[0].Passed)
+	assert.Equal(t, cloud.SourceLive, results[0].Source)
+	assert.Equal(t, []string{"Deployed attribute 'acl' has value 'public-read', expected 'private'"}, results[0].Details)
+
+	results, err = adapter.ValidateResourceCompliance(ctx, "aws_s3_bucket", "missing", rules)
+	require.NoError(t, err)
+	assert.Empty(t, results)
+}
+
+func TestAdapter_CheckResource(t *testing.T) {
+	ctx := context.Background()
+	adapter := NewAdapter(Resource{
+		Type:       "aws_s3_bucket",
+		ID:         "app-logs",
+		Properties: map[string]interface{}{"acl": "public-read"},
+	})
+
+	rules := []cloud.ValidationRule{
+		{Name: "private-acl", Severity: "error", Conditions: map[string]interface{}{"acl": "private"}},
+	}
+	status, results, err := adapter.CheckResource(ctx, map[string]interface{}{"acl": "private"}, "aws_s3_bucket", "app-logs", rules)
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.False(t, results[0].Passed)
+	assert.True(t, status.DriftDetected)
+	assert.Equal(t, []string{"Attribute 'acl' differs: planned=private, actual=public-read"}, status.DriftDetails)
+
+	status, results, err = adapter.CheckResource(ctx, nil, "aws_s3_bucket", "missing", rules)
+	require.NoError(t, err)
+	assert.Empty(t, results)
+	assert.Equal(t, []string{"Resource does not exist in fake cloud"}, status.DriftDetails)
+}
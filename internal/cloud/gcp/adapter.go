@@ -4,22 +4,49 @@ package gcp
 import (
 	"context"
 	"fmt"
+	"net/http"
+	"net/url"
 	"os"
+	"sort"
 	"strings"
 
 	compute "cloud.google.com/go/compute/apiv1"
 	"cloud.google.com/go/compute/apiv1/computepb"
 	"cloud.google.com/go/storage"
 	"github.com/vijayaxai/terraship/internal/cloud"
+	"github.com/vijayaxai/terraship/internal/cloud/cassette"
+	cloudkms "google.golang.org/api/cloudkms/v1"
+	"google.golang.org/api/cloudresourcemanager/v1"
+	container "google.golang.org/api/container/v1"
+	"google.golang.org/api/iterator"
 	"google.golang.org/api/option"
+	sqladmin "google.golang.org/api/sqladmin/v1"
+	htransport "google.golang.org/api/transport/http"
 )
 
+// cloudPlatformScope covers the APIs the adapter calls
+const cloudPlatformScope = "https://www.googleapis.com/auth/cloud-platform"
+
 // Adapter implements cloud.Adapter for GCP
 type Adapter struct {
-	projectID       string
-	computeClient   *compute.InstancesClient
-	storageClient   *storage.Client
-	credentialsFile string
+	projectID              string
+	computeClient          *compute.InstancesClient
+	firewallsClient        *compute.FirewallsClient
+	disksClient            *compute.DisksClient
+	storageClient          *storage.Client
+	sqlService             *sqladmin.Service
+	kmsService             *cloudkms.Service
+	containerService       *container.Service
+	resourceManagerService *cloudresourcemanager.Service
+	credentialsFile        string
+	recorder               *cassette.Recorder
+	transport              http.RoundTripper // rate limited or recorded transport; nil keeps the default
+	storageEndpoint        string            // storage endpoint override, e.g. fake-gcs-server
+	clientErrs             map[string]error  // why the clients of a service are nil, by service
+}
+
+func init() {
+	cloud.Register(cloud.ProviderGCP, func() cloud.Adapter { return NewAdapter() }, "hashicorp/google", "hashicorp/google-beta")
 }
 
 // NewAdapter creates a new GCP adapter
@@ -58,21 +85,145 @@ func (a *Adapter) Initialize(ctx context.Context, cloudConfig cloud.CloudConfig)
 		opts = append(opts, option.WithCredentialsFile(credFile))
 	}
 
-	// Initialize compute client
-	a.computeClient, err = compute.NewInstancesRESTClient(ctx, opts...)
+	// Rate limit, record or replay API traffic
+	a.transport, a.recorder, err = cloudConfig.Transport(nil)
 	if err != nil {
-		return fmt.Errorf("failed to create GCP compute client: %w", err)
+		return err
 	}
 
 	// Initialize storage client
-	a.storageClient, err = storage.NewClient(ctx, opts...)
+	a.storageEndpoint = storageEndpoint(cloudConfig.Endpoint("storage"))
+	storageOpts, err := a.clientOptions(ctx, opts, a.storageEndpoint)
+	if err != nil {
+		return err
+	}
+	a.storageClient, err = storage.NewClient(ctx, storageOpts...)
 	if err != nil {
 		return fmt.Errorf("failed to create GCP storage client: %w", err)
 	}
 
+	// Initialize the clients of the other services. Storage emulators need no
+	// credentials, so without them these clients are left out and their calls
+	// fail.
+	a.clientErrs = make(map[string]error)
+	newClients := map[string]func(opts []option.ClientOption) error{
+		"compute": func(opts []option.ClientOption) (err error) {
+			if a.computeClient, err = compute.NewInstancesRESTClient(ctx, opts...); err != nil {
+				return err
+			}
+			if a.firewallsClient, err = compute.NewFirewallsRESTClient(ctx, opts...); err != nil {
+				return err
+			}
+			a.disksClient, err = compute.NewDisksRESTClient(ctx, opts...)
+			return err
+		},
+		"sql": func(opts []option.ClientOption) (err error) {
+			a.sqlService, err = sqladmin.NewService(ctx, opts...)
+			return err
+		},
+		"kms": func(opts []option.ClientOption) (err error) {
+			a.kmsService, err = cloudkms.NewService(ctx, opts...)
+			return err
+		},
+		"container": func(opts []option.ClientOption) (err error) {
+			a.containerService, err = container.NewService(ctx, opts...)
+			return err
+		},
+		"resource_manager": func(opts []option.ClientOption) (err error) {
+			a.resourceManagerService, err = cloudresourcemanager.NewService(ctx, opts...)
+			return err
+		},
+	}
+	for _, service := range cloud.EndpointServices[cloud.ProviderGCP] {
+		newClient, ok := newClients[service]
+		if !ok {
+			continue
+		}
+		endpoint := apiEndpoint(cloudConfig.Endpoint(service))
+		if service == "compute" {
+			endpoint = computeEndpoint(cloudConfig.Endpoint(service))
+		}
+		serviceOpts, err := a.clientOptions(ctx, opts, endpoint)
+		if err == nil {
+			err = newClient(serviceOpts)
+		}
+		if err != nil {
+			err = fmt.Errorf("failed to create GCP %s client: %w", service, err)
+			if a.storageEndpoint == "" {
+				return err
+			}
+			a.clientErrs[service] = err
+		}
+	}
+
 	return nil
 }
 
+// clientOptions returns the options of a client calling endpoint, or the
+// public endpoint when it is empty. Plain HTTP endpoints are emulators,
+// which are called without credentials. Rate limited and recorded traffic
+// authenticates above the transport, so the recorder never sees tokens;
+// replays need no credentials.
+func (a *Adapter) clientOptions(ctx context.Context, opts []option.ClientOption, endpoint string) ([]option.ClientOption, error) {
+	var endpointOpts []option.ClientOption
+	if endpoint != "" {
+		endpointOpts = append(endpointOpts, option.WithEndpoint(endpoint))
+		if strings.HasPrefix(endpoint, "http://") {
+			opts = []option.ClientOption{option.WithoutAuthentication()}
+		}
+	}
+
+	if a.recorder.Replaying() {
+		return append(endpointOpts, option.WithHTTPClient(a.recorder.Client())), nil
+	}
+	if a.transport != nil {
+		transportOpts := append(append([]option.ClientOption{}, opts...), option.WithScopes(cloudPlatformScope))
+		transport, err := htransport.NewTransport(ctx, a.transport, transportOpts...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create GCP transport: %w", err)
+		}
+		return append(endpointOpts, option.WithHTTPClient(&http.Client{Transport: transport})), nil
+	}
+
+	return append(append([]option.ClientOption{}, opts...), endpointOpts...), nil
+}
+
+// storageEndpoint completes a storage endpoint override given as a base URL,
+// like fake-gcs-server's http://localhost:4443, with the JSON API path
+func storageEndpoint(endpoint string) string {
+	if endpoint == "" {
+		return ""
+	}
+	if u, err := url.Parse(endpoint); err == nil && strings.Trim(u.Path, "/") == "" {
+		u.Path = "/storage/v1/"
+		return u.String()
+	}
+	return endpoint
+}
+
+// computeEndpoint strips the API path from a compute endpoint override, as
+// given to the google provider's compute_custom_endpoint; the client adds it
+func computeEndpoint(endpoint string) string {
+	endpoint = strings.TrimSuffix(endpoint, "/")
+	return strings.TrimSuffix(endpoint, "/compute/v1")
+}
+
+// apiEndpoint completes an endpoint override of a discovery-based API, as
+// given to the google provider's sql_custom_endpoint, kms_custom_endpoint and
+// so on: the API version path the provider's default endpoints end with is
+// stripped, since the client adds it, and the base URL gets the trailing
+// slash the client's paths are resolved against
+func apiEndpoint(endpoint string) string {
+	if endpoint == "" {
+		return ""
+	}
+	endpoint = strings.TrimSuffix(endpoint, "/")
+	for _, path := range []string{"/sql/v1beta4", "/v1beta1", "/v1"} {
+		endpoint = strings.TrimSuffix(endpoint, path)
+	}
+	return endpoint + "/"
+}
+
 // DetectProvider attempts to detect if GCP is the provider
 func (a *Adapter) DetectProvider(ctx context.Context) (bool, float64, error) {
 	confidence := 0.0
@@ -91,8 +242,18 @@ func (a *Adapter) DetectProvider(ctx context.Context) (bool, float64, error) {
 	return confidence > 0.5, confidence, nil
 }
 
-// ValidateCredentials checks if GCP credentials are valid
+// ValidateCredentials checks if GCP credentials are valid. With a storage
+// endpoint override the storage API is checked instead, since storage
+// emulators have no compute API.
 func (a *Adapter) ValidateCredentials(ctx context.Context) error {
+	if a.storageEndpoint != "" {
+		_, err := a.storageClient.Buckets(ctx, a.projectID).Next()
+		if err != nil && err != iterator.Done {
+			return fmt.Errorf("GCP storage endpoint validation failed: %w", err)
+		}
+		return nil
+	}
+
 	// Try to list instances as a lightweight validation
 	req := &computepb.AggregatedListInstancesRequest{
 		Project:    a.projectID,
@@ -121,6 +282,18 @@ func (a *Adapter) GetResourceStatus(ctx context.Context, resourceType, resourceI
 		return a.getComputeInstanceStatus(ctx, resourceID)
 	case strings.HasPrefix(resourceType, "google_storage_bucket"):
 		return a.getStorageBucketStatus(ctx, resourceID)
+	case resourceType == "google_compute_firewall":
+		return a.getFirewallStatus(ctx, resourceID)
+	case resourceType == "google_compute_disk":
+		return a.getDiskStatus(ctx, resourceID)
+	case resourceType == "google_sql_database_instance":
+		return a.getSQLInstanceStatus(ctx, resourceID)
+	case resourceType == "google_kms_crypto_key":
+		return a.getCryptoKeyStatus(ctx, resourceID)
+	case resourceType == "google_container_cluster":
+		return a.getContainerClusterStatus(ctx, resourceID)
+	case resourceType == "google_project_iam_binding", resourceType == "google_project_iam_member":
+		return a.getProjectIAMStatus(ctx, resourceType, resourceID)
 	default:
 		return status, fmt.Errorf("unsupported resource type: %s", resourceType)
 	}
@@ -136,6 +309,10 @@ func (a *Adapter) getComputeInstanceStatus(ctx context.Context, resourceID strin
 	zone := parts[3]
 	instanceName := parts[5]
 
+	if a.computeClient == nil {
+		return nil, a.clientErrs["compute"]
+	}
+
 	req := &computepb.GetInstanceRequest{
 		Project:  a.projectID,
 		Zone:     zone,
@@ -206,6 +383,10 @@ func (a *Adapter) getStorageBucketStatus(ctx context.Context, bucketName string)
 	status.Properties["location"] = attrs.Location
 	status.Properties["storage_class"] = attrs.StorageClass
 	status.Properties["versioning_enabled"] = attrs.VersioningEnabled
+	status.Properties["uniform_bucket_level_access"] = attrs.UniformBucketLevelAccess.Enabled
+	if attrs.PublicAccessPrevention != storage.PublicAccessPreventionUnknown {
+		status.Properties["public_access_prevention"] = attrs.PublicAccessPrevention.String()
+	}
 
 	// Check encryption
 	if attrs.Encryption != nil {
@@ -216,9 +397,153 @@ func (a *Adapter) getStorageBucketStatus(ctx context.Context, bucketName string)
 	return status, nil
 }
 
-// ValidateResourceCompliance checks resource compliance with policies
-func (a *Adapter) ValidateResourceCompliance(ctx context.Context, resourceType string, resource map[string]interface{}, rules []cloud.ValidationRule) ([]cloud.ValidationResult, error) {
-	return []cloud.ValidationResult{}, nil
+// differs map the planned attributes of each resource type to the properties
+// GetResourceStatus reads; other types are compared by labels only, GCP's
+// version of tags
+var differs = map[string]cloud.Differ{
+	"google_compute_instance": {Tags: "labels", Attributes: []cloud.AttributeMapping{
+		{Attribute: "machine_type", Property: "machine_type", Equal: cloud.EqualBaseName},
+		{Attribute: "zone", Property: "zone", Equal: cloud.EqualBaseName},
+	}},
+	"google_storage_bucket": {Tags: "labels", Attributes: []cloud.AttributeMapping{
+		{Attribute: "location", Property: "location", Equal: cloud.EqualFold},
+		{Attribute: "storage_class", Property: "storage_class"},
+		{Attribute: "versioning[0].enabled", Property: "versioning_enabled"},
+		{Attribute: "uniform_bucket_level_access", Property: "uniform_bucket_level_access"},
+		{Attribute: "public_access_prevention", Property: "public_access_prevention"},
+	}},
+	"google_compute_firewall": {Tags: "labels", Attributes: []cloud.AttributeMapping{
+		{Attribute: "network", Property: "network", Equal: cloud.EqualBaseName},
+		{Attribute: "direction", Property: "direction"},
+		{Attribute: "priority", Property: "priority"},
+		{Attribute: "disabled", Property: "disabled"},
+		{Attribute: "source_ranges", Property: "source_ranges", Equal: sameSet},
+		{Attribute: "destination_ranges", Property: "destination_ranges", Equal: sameSet},
+		{Attribute: "target_tags", Property: "target_tags", Equal: sameSet},
+		{Attribute: "allow", Property: "allow", Equal: sameFirewallRules},
+		{Attribute: "deny", Property: "deny", Equal: sameFirewallRules},
+	}},
+	"google_compute_disk": {Tags: "labels", Attributes: []cloud.AttributeMapping{
+		{Attribute: "type", Property: "type", Equal: cloud.EqualBaseName},
+		{Attribute: "size", Property: "size"},
+		{Attribute: "zone", Property: "zone", Equal: cloud.EqualBaseName},
+		{Attribute: "provisioned_iops", Property: "provisioned_iops"},
+		{Attribute: "disk_encryption_key[0].kms_key_self_link", Property: "kms_key_self_link", Equal: keyName},
+	}},
+	"google_sql_database_instance": {Tags: "settings[0].user_labels", Attributes: []cloud.AttributeMapping{
+		{Attribute: "database_version", Property: "database_version"},
+		{Attribute: "region", Property: "region"},
+		{Attribute: "settings[0].tier", Property: "tier"},
+		{Attribute: "settings[0].availability_type", Property: "availability_type"},
+		{Attribute: "settings[0].disk_size", Property: "disk_size"},
+		{Attribute: "settings[0].disk_type", Property: "disk_type"},
+		{Attribute: "settings[0].deletion_protection_enabled", Property: "deletion_protection_enabled"},
+		{Attribute: "settings[0].ip_configuration[0].ipv4_enabled", Property: "ipv4_enabled"},
+		{Attribute: "settings[0].ip_configuration[0].require_ssl", Property: "require_ssl"},
+		{Attribute: "settings[0].backup_configuration[0].enabled", Property: "backup_enabled"},
+		{Attribute: "settings[0].backup_configuration[0].point_in_time_recovery_enabled", Property: "point_in_time_recovery_enabled"},
+	}},
+	"google_kms_crypto_key": {Tags: "labels", Attributes: []cloud.AttributeMapping{
+		{Attribute: "purpose", Property: "purpose"},
+		{Attribute: "rotation_period", Property: "rotation_period"},
+		{Attribute: "import_only", Property: "import_only"},
+		{Attribute: "destroy_scheduled_duration", Property: "destroy_scheduled_duration"},
+		{Attribute: "version_template[0].algorithm", Property: "algorithm"},
+		{Attribute: "version_template[0].protection_level", Property: "protection_level"},
+	}},
+	"google_container_cluster": {Tags: "resource_labels", Attributes: []cloud.AttributeMapping{
+		{Attribute: "location", Property: "location"},
+		{Attribute: "min_master_version", Property: "master_version", Equal: versionPrefix},
+		{Attribute: "network", Property: "network", Equal: cloud.EqualBaseName},
+		{Attribute: "subnetwork", Property: "subnetwork", Equal: cloud.EqualBaseName},
+		{Attribute: "release_channel[0].channel", Property: "release_channel"},
+		{Attribute: "private_cluster_config[0].enable_private_nodes", Property: "enable_private_nodes"},
+		{Attribute: "private_cluster_config[0].enable_private_endpoint", Property: "enable_private_endpoint"},
+		{Attribute: "enable_legacy_abac", Property: "enable_legacy_abac"},
+		{Attribute: "network_policy[0].enabled", Property: "network_policy_enabled"},
+	}},
+	"google_project_iam_binding": {Tags: "labels", Attributes: []cloud.AttributeMapping{
+		{Attribute: "members", Property: "members", Equal: sameSet},
+	}},
+}
+
+// sameSet compares a planned list with a sorted deployed list regardless of
+// order
+func sameSet(planned, actual interface{}) bool {
+	items, _ := planned.([]interface{})
+	values := make([]string, 0, len(items))
+	for _, item := range items {
+		values = append(values, fmt.Sprint(item))
+	}
+	deployed, _ := actual.([]string)
+	return strings.Join(sorted(values), ",") == strings.Join(deployed, ",")
+}
+
+// sameFirewallRules compares the protocols and ports of planned allow or deny
+// blocks with the deployed entries
+func sameFirewallRules(planned, actual interface{}) bool {
+	blocks, _ := planned.([]interface{})
+	rules := make([]string, 0, len(blocks))
+	for _, block := range blocks {
+		rule, _ := block.(map[string]interface{})
+		var ports []string
+		list, _ := rule["ports"].([]interface{})
+		for _, port := range list {
+			ports = append(ports, fmt.Sprint(port))
+		}
+		rules = append(rules, firewallRuleKey(fmt.Sprint(rule["protocol"]), ports))
+	}
+	sort.Strings(rules)
+	deployed, _ := actual.([]string)
+	return strings.Join(rules, " ") == strings.Join(deployed, " ")
+}
+
+// versionPrefix matches a planned version such as 1.29 with a deployed
+// version that starts with it, like 1.29.1-gke.1589017
+func versionPrefix(planned, actual interface{}) bool {
+	version, deployed := fmt.Sprint(planned), fmt.Sprint(actual)
+	return deployed == version || strings.HasPrefix(deployed, version+".") || strings.HasPrefix(deployed, version+"-")
+}
+
+// keyName compares a planned crypto key with the key a disk is encrypted
+// with, which names the key version in use
+func keyName(planned, actual interface{}) bool {
+	deployed := fmt.Sprint(actual)
+	if i := strings.Index(deployed, "/cryptoKeyVersions/"); i >= 0 {
+		deployed = deployed[:i]
+	}
+	return strings.HasSuffix(fmt.Sprint(planned), deployed)
+}
+
+// liveChecks answer built-in policy conditions for deployed resources;
+// attribute conditions are answered with the properties of differs
+var liveChecks = map[string]cloud.LiveChecks{
+	"google_storage_bucket": {
+		"public_access.blocked": publicAccessPrevented,
+		"versioning.enabled":    cloud.EnabledCheck("versioning_enabled", "Versioning is not enabled on the bucket"),
+	},
+}
+
+// publicAccessPrevented answers public_access.blocked for a deployed bucket:
+// public access prevention is enforced rather than inherited
+func publicAccessPrevented(live map[string]interface{}, expected interface{}) (bool, []string, bool) {
+	prevention, ok := live["public_access_prevention"].(string)
+	if !ok {
+		return false, nil, false
+	}
+	if required, _ := expected.(bool); !required || prevention == "enforced" {
+		return true, nil, true
+	}
+	return false, []string{fmt.Sprintf("Public access prevention is %s, not enforced", prevention)}, true
+}
+
+// ValidateResourceCompliance evaluates the rules against the live
+// configuration of a deployed GCP resource
+func (a *Adapter) ValidateResourceCompliance(ctx context.Context, resourceType, resourceID string, rules []cloud.ValidationRule) ([]cloud.ValidationResult, error) {
+	checks := differs[resourceType].LiveChecks(liveChecks[resourceType])
+	return checks.Check(rules, func() (*cloud.ResourceStatus, error) {
+		return a.GetResourceStatus(ctx, resourceType, resourceID)
+	})
 }
 
 // DetectDrift compares planned state with actual cloud resources
@@ -227,37 +552,59 @@ func (a *Adapter) DetectDrift(ctx context.Context, plannedState map[string]inter
 	if err != nil {
 		return nil, err
 	}
+	return setDrift(plannedState, resourceType, actualStatus), nil
+}
+
+// CheckResource reads a deployed GCP resource once to evaluate the rules
+// against it and detect drift
+func (a *Adapter) CheckResource(ctx context.Context, plannedState map[string]interface{}, resourceType, resourceID string, rules []cloud.ValidationRule) (*cloud.ResourceStatus, []cloud.ValidationResult, error) {
+	actualStatus, err := a.GetResourceStatus(ctx, resourceType, resourceID)
+	if err != nil {
+		return nil, nil, err
+	}
+	results := differs[resourceType].LiveChecks(liveChecks[resourceType]).Results(rules, actualStatus)
+	return setDrift(plannedState, resourceType, actualStatus), results, nil
+}
 
+// setDrift records the drift of a deployed resource of resourceType from the
+// planned state
+func setDrift(plannedState map[string]interface{}, resourceType string, actualStatus *cloud.ResourceStatus) *cloud.ResourceStatus {
 	if !actualStatus.Exists {
 		actualStatus.DriftDetected = true
 		actualStatus.DriftDetails = []string{"Resource does not exist in GCP"}
-		return actualStatus, nil
+		return actualStatus
 	}
 
-	driftDetails := []string{}
-
-	// Check labels (GCP's version of tags)
-	if plannedLabels, ok := plannedState["labels"].(map[string]interface{}); ok {
-		for key, value := range plannedLabels {
-			if actualValue, exists := actualStatus.Tags[key]; !exists {
-				driftDetails = append(driftDetails, fmt.Sprintf("Label '%s' missing", key))
-			} else if fmt.Sprint(value) != actualValue {
-				driftDetails = append(driftDetails, fmt.Sprintf("Label '%s' differs: planned=%v, actual=%v", key, value, actualValue))
-			}
-		}
+	differ, ok := differs[resourceType]
+	if !ok {
+		differ = cloud.Differ{Tags: "labels"}
 	}
-
-	if len(driftDetails) > 0 {
-		actualStatus.DriftDetected = true
-		actualStatus.DriftDetails = driftDetails
-	}
-
-	return actualStatus, nil
+	actualStatus.SetDrift(differ.Diff(plannedState, actualStatus))
+	return actualStatus
 }
 
 // ListResources lists GCP resources of a given type
 func (a *Adapter) ListResources(ctx context.Context, resourceType string) ([]string, error) {
-	return nil, fmt.Errorf("listing not yet implemented for GCP")
+	switch resourceType {
+	case "google_compute_instance":
+		return a.listComputeInstances(ctx)
+	case "google_storage_bucket":
+		return a.listStorageBuckets(ctx)
+	case "google_compute_firewall":
+		return a.listFirewalls(ctx)
+	case "google_compute_disk":
+		return a.listDisks(ctx)
+	case "google_sql_database_instance":
+		return a.listSQLInstances(ctx)
+	case "google_kms_crypto_key":
+		return a.listCryptoKeys(ctx)
+	case "google_container_cluster":
+		return a.listContainerClusters(ctx)
+	case "google_project_iam_binding", "google_project_iam_member":
+		return a.listProjectIAM(ctx, resourceType)
+	default:
+		return nil, fmt.Errorf("listing not supported for resource type: %s", resourceType)
+	}
 }
 
 // Close cleans up GCP adapter resources
@@ -265,8 +612,17 @@ func (a *Adapter) Close() error {
 	if a.computeClient != nil {
 		_ = a.computeClient.Close()
 	}
+	if a.firewallsClient != nil {
+		_ = a.firewallsClient.Close()
+	}
+	if a.disksClient != nil {
+		_ = a.disksClient.Close()
+	}
 	if a.storageClient != nil {
 		_ = a.storageClient.Close()
 	}
+	if a.recorder != nil {
+		return a.recorder.Save()
+	}
 	return nil
 }
@@ -0,0 +1,69 @@
+package gcp
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vijayaxai/terraship/internal/cloud"
+)
+
+func TestAdapter_StorageEndpointOverride(t *testing.T) {
+	// Serves the JSON API like fake-gcs-server
+	var paths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		assert.Empty(t, r.Header.Get("Authorization"), "emulators are called without credentials")
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/storage/v1/b":
+			_, _ = io.WriteString(w, `{"items": [{"name": "acme-assets"}]}`)
+		case "/storage/v1/b/acme-assets":
+			_, _ = io.WriteString(w, `{"name": "acme-assets", "location": "EU", "labels": {"env": "dev"}}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = io.WriteString(w, `{"error": {"code": 404, "message": "not found"}}`)
+		}
+	}))
+	defer server.Close()
+
+	t.Setenv("GOOGLE_APPLICATION_CREDENTIALS", "")
+	t.Setenv("STORAGE_EMULATOR_HOST", "")
+
+	ctx := context.Background()
+	adapter := NewAdapter()
+	require.NoError(t, adapter.Initialize(ctx, cloud.CloudConfig{
+		Provider:   cloud.ProviderGCP,
+		GCPProject: "acme",
+		Endpoints:  map[string]string{"storage": server.URL, "compute": server.URL},
+	}))
+	defer adapter.Close()
+	require.NoError(t, adapter.ValidateCredentials(ctx))
+
+	drift, err := adapter.DetectDrift(ctx, map[string]interface{}{
+		"labels": map[string]interface{}{"env": "prod"},
+	}, "google_storage_bucket", "acme-assets")
+	require.NoError(t, err)
+	assert.True(t, drift.Exists)
+	assert.Equal(t, "EU", drift.Properties["location"])
+	assert.Equal(t, []string{"Label 'env' differs: planned=prod, actual=dev"}, drift.DriftDetails)
+
+	missing, err := adapter.GetResourceStatus(ctx, "google_compute_instance", "projects/acme/zones/europe-west1-b/instances/web")
+	require.NoError(t, err)
+	assert.False(t, missing.Exists)
+	assert.Contains(t, paths, "/compute/v1/projects/acme/zones/europe-west1-b/instances/web")
+}
+
+func TestEndpoints(t *testing.T) {
+	assert.Equal(t, "http://localhost:4443/storage/v1/", storageEndpoint("http://localhost:4443"))
+	assert.Equal(t, "https://storage.example.com/storage/v1/", storageEndpoint("https://storage.example.com/storage/v1/"))
+	assert.Empty(t, storageEndpoint(""))
+	assert.Equal(t, "https://compute.example.com", computeEndpoint("https://compute.example.com/compute/v1/"))
+	assert.Equal(t, "https://sqladmin.example.com/", apiEndpoint("https://sqladmin.example.com/sql/v1beta4/"))
+	assert.Equal(t, "http://localhost:9090/", apiEndpoint("http://localhost:9090"))
+	assert.Empty(t, apiEndpoint(""))
+}
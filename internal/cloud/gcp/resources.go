@@ -0,0 +1,586 @@
+package gcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"cloud.google.com/go/compute/apiv1/computepb"
+	"github.com/vijayaxai/terraship/internal/cloud"
+	cloudkms "google.golang.org/api/cloudkms/v1"
+	"google.golang.org/api/cloudresourcemanager/v1"
+	"google.golang.org/api/googleapi"
+	"google.golang.org/api/iterator"
+	sqladmin "google.golang.org/api/sqladmin/v1"
+)
+
+// notFound reports whether a GCP API call failed because the resource does
+// not exist
+func notFound(err error) bool {
+	var apiErr *googleapi.Error
+	return errors.As(err, &apiErr) && apiErr.Code == http.StatusNotFound
+}
+
+// missing returns the status of a resource that does not exist
+func missing(resourceID, resourceType string) *cloud.ResourceStatus {
+	return &cloud.ResourceStatus{
+		ResourceID:   resourceID,
+		ResourceType: resourceType,
+		Exists:       false,
+	}
+}
+
+// gcpLabels copies the labels of a resource
+func gcpLabels(labels map[string]string) map[string]string {
+	result := make(map[string]string, len(labels))
+	for key, value := range labels {
+		result[key] = value
+	}
+	return result
+}
+
+// sorted returns a sorted copy of values, for comparing sets
+func sorted(values []string) []string {
+	result := append([]string{}, values...)
+	sort.Strings(result)
+	return result
+}
+
+// parseID returns the values of the * segments of a resource ID matching a
+// pattern such as projects/*/zones/*/disks/*
+func parseID(resourceID, pattern string) ([]string, error) {
+	parts := strings.Split(strings.Trim(resourceID, "/"), "/")
+	segments := strings.Split(pattern, "/")
+	if len(parts) != len(segments) {
+		return nil, fmt.Errorf("invalid GCP resource ID format: %s", resourceID)
+	}
+	var values []string
+	for i, segment := range segments {
+		switch {
+		case segment == "*":
+			values = append(values, parts[i])
+		case segment != parts[i]:
+			return nil, fmt.Errorf("invalid GCP resource ID format: %s", resourceID)
+		}
+	}
+	return values, nil
+}
+
+func (a *Adapter) getFirewallStatus(ctx context.Context, resourceID string) (*cloud.ResourceStatus, error) {
+	values, err := parseID(resourceID, "projects/*/global/firewalls/*")
+	if err != nil {
+		return nil, err
+	}
+	if a.firewallsClient == nil {
+		return nil, a.clientErrs["compute"]
+	}
+
+	firewall, err := a.firewallsClient.Get(ctx, &computepb.GetFirewallRequest{
+		Project:  values[0],
+		Firewall: values[1],
+	})
+	if notFound(err) {
+		return missing(resourceID, "google_compute_firewall"), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get firewall: %w", err)
+	}
+
+	return &cloud.ResourceStatus{
+		ResourceID:   resourceID,
+		ResourceType: "google_compute_firewall",
+		Exists:       true,
+		Tags:         map[string]string{},
+		Properties: map[string]interface{}{
+			"network":            firewall.GetNetwork(),
+			"direction":          firewall.GetDirection(),
+			"priority":           firewall.GetPriority(),
+			"disabled":           firewall.GetDisabled(),
+			"source_ranges":      sorted(firewall.GetSourceRanges()),
+			"destination_ranges": sorted(firewall.GetDestinationRanges()),
+			"target_tags":        sorted(firewall.GetTargetTags()),
+			"allow":              firewallRules(firewall.GetAllowed()),
+			"deny":               firewallRules(firewall.GetDenied()),
+		},
+	}, nil
+}
+
+// firewallRule is an allow or deny entry of a firewall
+type firewallRule interface {
+	GetIPProtocol() string
+	GetPorts() []string
+}
+
+// firewallRules returns the sorted keys of a firewall's allow or deny entries
+func firewallRules[T firewallRule](entries []T) []string {
+	rules := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		rules = append(rules, firewallRuleKey(entry.GetIPProtocol(), entry.GetPorts()))
+	}
+	sort.Strings(rules)
+	return rules
+}
+
+// firewallRuleKey identifies an allow or deny entry by protocol and ports,
+// e.g. tcp:22,443
+func firewallRuleKey(protocol string, ports []string) string {
+	return strings.ToLower(protocol) + ":" + strings.Join(sorted(ports), ",")
+}
+
+func (a *Adapter) getDiskStatus(ctx context.Context, resourceID string) (*cloud.ResourceStatus, error) {
+	values, err := parseID(resourceID, "projects/*/zones/*/disks/*")
+	if err != nil {
+		return nil, err
+	}
+	if a.disksClient == nil {
+		return nil, a.clientErrs["compute"]
+	}
+
+	disk, err := a.disksClient.Get(ctx, &computepb.GetDiskRequest{
+		Project: values[0],
+		Zone:    values[1],
+		Disk:    values[2],
+	})
+	if notFound(err) {
+		return missing(resourceID, "google_compute_disk"), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get disk: %w", err)
+	}
+
+	status := &cloud.ResourceStatus{
+		ResourceID:   resourceID,
+		ResourceType: "google_compute_disk",
+		Exists:       true,
+		State:        disk.GetStatus(),
+		Tags:         gcpLabels(disk.GetLabels()),
+		Properties: map[string]interface{}{
+			"type": disk.GetType(),
+			"size": disk.GetSizeGb(),
+			"zone": disk.GetZone(),
+		},
+	}
+	if key := disk.GetDiskEncryptionKey(); key != nil {
+		status.Properties["kms_key_self_link"] = key.GetKmsKeyName()
+	}
+	if disk.ProvisionedIops != nil {
+		status.Properties["provisioned_iops"] = disk.GetProvisionedIops()
+	}
+
+	return status, nil
+}
+
+func (a *Adapter) getSQLInstanceStatus(ctx context.Context, resourceID string) (*cloud.ResourceStatus, error) {
+	// The ID of a google_sql_database_instance is its name
+	if a.sqlService == nil {
+		return nil, a.clientErrs["sql"]
+	}
+
+	instance, err := a.sqlService.Instances.Get(a.projectID, resourceID).Context(ctx).Do()
+	if notFound(err) {
+		return missing(resourceID, "google_sql_database_instance"), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get SQL instance: %w", err)
+	}
+
+	status := &cloud.ResourceStatus{
+		ResourceID:   resourceID,
+		ResourceType: "google_sql_database_instance",
+		Exists:       true,
+		State:        instance.State,
+		Tags:         map[string]string{},
+		Properties: map[string]interface{}{
+			"database_version": instance.DatabaseVersion,
+			"region":           instance.Region,
+		},
+	}
+
+	if settings := instance.Settings; settings != nil {
+		status.Tags = gcpLabels(settings.UserLabels)
+		status.Properties["tier"] = settings.Tier
+		status.Properties["availability_type"] = settings.AvailabilityType
+		status.Properties["disk_size"] = settings.DataDiskSizeGb
+		status.Properties["disk_type"] = settings.DataDiskType
+		status.Properties["deletion_protection_enabled"] = settings.DeletionProtectionEnabled
+		if ip := settings.IpConfiguration; ip != nil {
+			status.Properties["ipv4_enabled"] = ip.Ipv4Enabled
+			status.Properties["require_ssl"] = ip.RequireSsl
+		}
+		if backup := settings.BackupConfiguration; backup != nil {
+			status.Properties["backup_enabled"] = backup.Enabled
+			status.Properties["point_in_time_recovery_enabled"] = backup.PointInTimeRecoveryEnabled
+		}
+	}
+
+	return status, nil
+}
+
+func (a *Adapter) getCryptoKeyStatus(ctx context.Context, resourceID string) (*cloud.ResourceStatus, error) {
+	if _, err := parseID(resourceID, "projects/*/locations/*/keyRings/*/cryptoKeys/*"); err != nil {
+		return nil, err
+	}
+	if a.kmsService == nil {
+		return nil, a.clientErrs["kms"]
+	}
+
+	key, err := a.kmsService.Projects.Locations.KeyRings.CryptoKeys.Get(resourceID).Context(ctx).Do()
+	if notFound(err) {
+		return missing(resourceID, "google_kms_crypto_key"), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get crypto key: %w", err)
+	}
+
+	status := &cloud.ResourceStatus{
+		ResourceID:   resourceID,
+		ResourceType: "google_kms_crypto_key",
+		Exists:       true,
+		Tags:         gcpLabels(key.Labels),
+		Properties: map[string]interface{}{
+			"purpose":                    key.Purpose,
+			"rotation_period":            key.RotationPeriod,
+			"import_only":                key.ImportOnly,
+			"destroy_scheduled_duration": key.DestroyScheduledDuration,
+		},
+	}
+	if key.Primary != nil {
+		status.State = key.Primary.State
+	}
+	if template := key.VersionTemplate; template != nil {
+		status.Properties["algorithm"] = template.Algorithm
+		status.Properties["protection_level"] = template.ProtectionLevel
+	}
+
+	return status, nil
+}
+
+func (a *Adapter) getContainerClusterStatus(ctx context.Context, resourceID string) (*cloud.ResourceStatus, error) {
+	values, err := parseID(resourceID, "projects/*/locations/*/clusters/*")
+	if err != nil {
+		return nil, err
+	}
+	if a.containerService == nil {
+		return nil, a.clientErrs["container"]
+	}
+
+	cluster, err := a.containerService.Projects.Locations.Clusters.Get(resourceID).Context(ctx).Do()
+	if notFound(err) {
+		return missing(resourceID, "google_container_cluster"), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get container cluster: %w", err)
+	}
+
+	status := &cloud.ResourceStatus{
+		ResourceID:   resourceID,
+		ResourceType: "google_container_cluster",
+		Exists:       true,
+		State:        cluster.Status,
+		Tags:         gcpLabels(cluster.ResourceLabels),
+		Properties: map[string]interface{}{
+			"location":       values[1],
+			"master_version": cluster.CurrentMasterVersion,
+			"network":        cluster.Network,
+			"subnetwork":     cluster.Subnetwork,
+		},
+	}
+	if cluster.ReleaseChannel != nil {
+		status.Properties["release_channel"] = cluster.ReleaseChannel.Channel
+	}
+	if cluster.PrivateClusterConfig != nil {
+		status.Properties["enable_private_nodes"] = cluster.PrivateClusterConfig.EnablePrivateNodes
+		status.Properties["enable_private_endpoint"] = cluster.PrivateClusterConfig.EnablePrivateEndpoint
+	}
+	if cluster.LegacyAbac != nil {
+		status.Properties["enable_legacy_abac"] = cluster.LegacyAbac.Enabled
+	}
+	if cluster.NetworkPolicy != nil {
+		status.Properties["network_policy_enabled"] = cluster.NetworkPolicy.Enabled
+	}
+
+	return status, nil
+}
+
+// parseIAMID splits the ID of a google_project_iam_binding, {project}/{role},
+// or google_project_iam_member, {project}/{role}/{member}. Roles are
+// predefined, roles/{name}, or custom, projects/{project}/roles/{name} or
+// organizations/{org}/roles/{name}.
+func parseIAMID(resourceID string) (project, role, member string, err error) {
+	project, rest, ok := strings.Cut(resourceID, "/")
+	if !ok || project == "" {
+		return "", "", "", fmt.Errorf("invalid GCP IAM ID format: %s", resourceID)
+	}
+
+	segments := 2
+	if !strings.HasPrefix(rest, "roles/") {
+		segments = 4
+	}
+	parts := strings.SplitN(rest, "/", segments+1)
+	if len(parts) < segments {
+		return "", "", "", fmt.Errorf("invalid GCP IAM ID format: %s", resourceID)
+	}
+	role = strings.Join(parts[:segments], "/")
+	if len(parts) > segments {
+		member = parts[segments]
+	}
+	return project, role, member, nil
+}
+
+// getProjectIAMPolicy reads the IAM policy of a project
+func (a *Adapter) getProjectIAMPolicy(ctx context.Context, project string) (*cloudresourcemanager.Policy, error) {
+	if a.resourceManagerService == nil {
+		return nil, a.clientErrs["resource_manager"]
+	}
+
+	policy, err := a.resourceManagerService.Projects.GetIamPolicy(project, &cloudresourcemanager.GetIamPolicyRequest{}).Context(ctx).Do()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get IAM policy of project %s: %w", project, err)
+	}
+	return policy, nil
+}
+
+// getProjectIAMStatus reads a google_project_iam_binding, the members of a
+// role, or a google_project_iam_member, one member of a role. Bindings with a
+// condition are not distinguished from the unconditional binding of the role.
+func (a *Adapter) getProjectIAMStatus(ctx context.Context, resourceType, resourceID string) (*cloud.ResourceStatus, error) {
+	project, role, member, err := parseIAMID(resourceID)
+	if err != nil {
+		return nil, err
+	}
+	if resourceType == "google_project_iam_member" && member == "" {
+		return nil, fmt.Errorf("invalid GCP IAM member ID format: %s", resourceID)
+	}
+
+	policy, err := a.getProjectIAMPolicy(ctx, project)
+	if notFound(err) {
+		return missing(resourceID, resourceType), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var members []string
+	for _, binding := range policy.Bindings {
+		if binding.Role == role {
+			members = append(members, binding.Members...)
+		}
+	}
+	exists := len(members) > 0
+	if resourceType == "google_project_iam_member" {
+		exists = false
+		for _, m := range members {
+			exists = exists || m == member
+		}
+	}
+	if !exists {
+		return missing(resourceID, resourceType), nil
+	}
+
+	return &cloud.ResourceStatus{
+		ResourceID:   resourceID,
+		ResourceType: resourceType,
+		Exists:       true,
+		Tags:         map[string]string{},
+		Properties: map[string]interface{}{
+			"project": project,
+			"role":    role,
+			"members": sorted(members),
+		},
+	}, nil
+}
+
+// listComputeInstances lists the instances of the project by ID
+func (a *Adapter) listComputeInstances(ctx context.Context) ([]string, error) {
+	if a.computeClient == nil {
+		return nil, a.clientErrs["compute"]
+	}
+
+	var ids []string
+	it := a.computeClient.AggregatedList(ctx, &computepb.AggregatedListInstancesRequest{Project: a.projectID})
+	for {
+		pair, err := it.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to list instances: %w", err)
+		}
+		for _, instance := range pair.Value.GetInstances() {
+			// pair.Key is zones/{zone}
+			ids = append(ids, fmt.Sprintf("projects/%s/%s/instances/%s", a.projectID, pair.Key, instance.GetName()))
+		}
+	}
+	return ids, nil
+}
+
+// listStorageBuckets lists the buckets of the project by name
+func (a *Adapter) listStorageBuckets(ctx context.Context) ([]string, error) {
+	var names []string
+	it := a.storageClient.Buckets(ctx, a.projectID)
+	for {
+		bucket, err := it.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to list buckets: %w", err)
+		}
+		names = append(names, bucket.Name)
+	}
+	return names, nil
+}
+
+// listFirewalls lists the firewalls of the project by ID
+func (a *Adapter) listFirewalls(ctx context.Context) ([]string, error) {
+	if a.firewallsClient == nil {
+		return nil, a.clientErrs["compute"]
+	}
+
+	var ids []string
+	it := a.firewallsClient.List(ctx, &computepb.ListFirewallsRequest{Project: a.projectID})
+	for {
+		firewall, err := it.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to list firewalls: %w", err)
+		}
+		ids = append(ids, fmt.Sprintf("projects/%s/global/firewalls/%s", a.projectID, firewall.GetName()))
+	}
+	return ids, nil
+}
+
+// listDisks lists the zonal disks of the project by ID
+func (a *Adapter) listDisks(ctx context.Context) ([]string, error) {
+	if a.disksClient == nil {
+		return nil, a.clientErrs["compute"]
+	}
+
+	var ids []string
+	it := a.disksClient.AggregatedList(ctx, &computepb.AggregatedListDisksRequest{Project: a.projectID})
+	for {
+		pair, err := it.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to list disks: %w", err)
+		}
+		if !strings.HasPrefix(pair.Key, "zones/") {
+			continue // regional disks are google_compute_region_disk resources
+		}
+		for _, disk := range pair.Value.GetDisks() {
+			ids = append(ids, fmt.Sprintf("projects/%s/%s/disks/%s", a.projectID, pair.Key, disk.GetName()))
+		}
+	}
+	return ids, nil
+}
+
+// listSQLInstances lists the Cloud SQL instances of the project by name
+func (a *Adapter) listSQLInstances(ctx context.Context) ([]string, error) {
+	if a.sqlService == nil {
+		return nil, a.clientErrs["sql"]
+	}
+
+	var names []string
+	err := a.sqlService.Instances.List(a.projectID).Pages(ctx, func(page *sqladmin.InstancesListResponse) error {
+		for _, instance := range page.Items {
+			names = append(names, instance.Name)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list SQL instances: %w", err)
+	}
+	return names, nil
+}
+
+// listCryptoKeys lists the crypto keys of every key ring of the project by
+// name, location by location
+func (a *Adapter) listCryptoKeys(ctx context.Context) ([]string, error) {
+	if a.kmsService == nil {
+		return nil, a.clientErrs["kms"]
+	}
+
+	var locations, keyRings, names []string
+	err := a.kmsService.Projects.Locations.List("projects/"+a.projectID).Pages(ctx, func(page *cloudkms.ListLocationsResponse) error {
+		for _, location := range page.Locations {
+			locations = append(locations, location.Name)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list KMS locations: %w", err)
+	}
+
+	for _, location := range locations {
+		err := a.kmsService.Projects.Locations.KeyRings.List(location).Pages(ctx, func(page *cloudkms.ListKeyRingsResponse) error {
+			for _, keyRing := range page.KeyRings {
+				keyRings = append(keyRings, keyRing.Name)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list key rings in %s: %w", location, err)
+		}
+	}
+
+	for _, keyRing := range keyRings {
+		err := a.kmsService.Projects.Locations.KeyRings.CryptoKeys.List(keyRing).Pages(ctx, func(page *cloudkms.ListCryptoKeysResponse) error {
+			for _, key := range page.CryptoKeys {
+				names = append(names, key.Name)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list crypto keys of %s: %w", keyRing, err)
+		}
+	}
+	return names, nil
+}
+
+// listContainerClusters lists the GKE clusters of the project in every
+// location by ID
+func (a *Adapter) listContainerClusters(ctx context.Context) ([]string, error) {
+	if a.containerService == nil {
+		return nil, a.clientErrs["container"]
+	}
+
+	resp, err := a.containerService.Projects.Locations.Clusters.List(fmt.Sprintf("projects/%s/locations/-", a.projectID)).Context(ctx).Do()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list container clusters: %w", err)
+	}
+
+	ids := make([]string, 0, len(resp.Clusters))
+	for _, cluster := range resp.Clusters {
+		ids = append(ids, fmt.Sprintf("projects/%s/locations/%s/clusters/%s", a.projectID, cluster.Location, cluster.Name))
+	}
+	return ids, nil
+}
+
+// listProjectIAM lists the role bindings of the project, by
+// google_project_iam_binding ID, or their members, by
+// google_project_iam_member ID
+func (a *Adapter) listProjectIAM(ctx context.Context, resourceType string) ([]string, error) {
+	policy, err := a.getProjectIAMPolicy(ctx, a.projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	var ids []string
+	for _, binding := range policy.Bindings {
+		if resourceType == "google_project_iam_binding" {
+			ids = append(ids, a.projectID+"/"+binding.Role)
+			continue
+		}
+		for _, member := range binding.Members {
+			ids = append(ids, a.projectID+"/"+binding.Role+"/"+member)
+		}
+	}
+	return ids, nil
+}
@@ -0,0 +1,264 @@
+package gcp
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vijayaxai/terraship/internal/cloud"
+)
+
+const keyID = "projects/acme/locations/europe/keyRings/ring/cryptoKeys/key"
+
+// standInResources are the stand-in GCP APIs' responses by path
+var standInResources = map[string]string{
+	"/compute/v1/projects/acme/global/firewalls/web": `{"name": "web", "direction": "INGRESS", "priority": 1000,
+		"network": "https://www.googleapis.com/compute/v1/projects/acme/global/networks/default",
+		"sourceRanges": ["10.0.0.0/8", "0.0.0.0/0"], "allowed": [{"IPProtocol": "tcp", "ports": ["443", "22"]}]}`,
+	"/compute/v1/projects/acme/zones/europe-west1-b/disks/data": `{"name": "data", "sizeGb": "100", "status": "READY",
+		"type": "https://www.googleapis.com/compute/v1/projects/acme/zones/europe-west1-b/diskTypes/pd-ssd",
+		"zone": "https://www.googleapis.com/compute/v1/projects/acme/zones/europe-west1-b", "labels": {"env": "prod"}}`,
+	"/v1/projects/acme/instances/db": `{"name": "db", "state": "RUNNABLE", "databaseVersion": "POSTGRES_15",
+		"region": "europe-west1", "settings": {"tier": "db-custom-2-7680", "availabilityType": "ZONAL",
+		"dataDiskSizeGb": "50", "userLabels": {"env": "prod"}, "ipConfiguration": {"ipv4Enabled": true},
+		"backupConfiguration": {"enabled": true}}}`,
+	"/v1/" + keyID: `{"name": "projects/acme/locations/europe/keyRings/ring/cryptoKeys/key", "purpose": "ENCRYPT_DECRYPT",
+		"rotationPeriod": "7776000s", "labels": {"env": "prod"}, "primary": {"state": "ENABLED"},
+		"versionTemplate": {"algorithm": "GOOGLE_SYMMETRIC_ENCRYPTION", "protectionLevel": "SOFTWARE"}}`,
+	"/v1/projects/acme/locations/europe-west1/clusters/gke": `{"name": "gke", "status": "RUNNING",
+		"currentMasterVersion": "1.29.1-gke.1589017", "network": "default", "subnetwork": "default",
+		"resourceLabels": {"env": "prod"}, "releaseChannel": {"channel": "REGULAR"},
+		"privateClusterConfig": {"enablePrivateNodes": true}}`,
+	"/v1/projects/acme:getIamPolicy": `{"bindings": [
+		{"role": "roles/viewer", "members": ["user:ana@example.com", "group:ops@example.com"]},
+		{"role": "projects/acme/roles/deployer", "members": ["serviceAccount:ci@acme.iam.gserviceaccount.com"]}]}`,
+
+	"/compute/v1/projects/acme/aggregated/instances": `{"items": {"zones/europe-west1-b": {"instances": [{"name": "web"}]},
+		"zones/us-east1-c": {"warning": {"code": "NO_RESULTS_ON_PAGE"}}}}`,
+	"/compute/v1/projects/acme/aggregated/disks": `{"items": {"zones/europe-west1-b": {"disks": [{"name": "data"}]},
+		"regions/europe-west1": {"disks": [{"name": "replicated"}]}}}`,
+	"/compute/v1/projects/acme/global/firewalls": `{"items": [{"name": "web"}, {"name": "default-allow-ssh"}]}`,
+	"/storage/v1/b":                                               `{"items": [{"name": "acme-assets"}]}`,
+	"/v1/projects/acme/instances":                                 `{"items": [{"name": "db"}]}`,
+	"/v1/projects/acme/locations":                                 `{"locations": [{"name": "projects/acme/locations/europe"}]}`,
+	"/v1/projects/acme/locations/europe/keyRings":                 `{"keyRings": [{"name": "projects/acme/locations/europe/keyRings/ring"}]}`,
+	"/v1/projects/acme/locations/europe/keyRings/ring/cryptoKeys": `{"cryptoKeys": [{"name": "` + keyID + `"}]}`,
+	"/v1/projects/acme/locations/-/clusters":                      `{"clusters": [{"name": "gke", "location": "europe-west1"}]}`,
+	"/storage/v1/b/acme-assets": `{"name": "acme-assets", "location": "EU", "labels": {"env": "prod"}, "versioning": {"enabled": true},
+		"iamConfiguration": {"uniformBucketLevelAccess": {"enabled": false}, "publicAccessPrevention": "inherited"}}`,
+}
+
+// newStandInAdapter returns an adapter calling stand-in GCP APIs, which it
+// reaches as an emulator without credentials
+func newStandInAdapter(t *testing.T) *Adapter {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		response, ok := standInResources[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = io.WriteString(w, `{"error": {"code": 404, "message": "not found"}}`)
+			return
+		}
+		_, _ = io.WriteString(w, response)
+	}))
+	t.Cleanup(server.Close)
+
+	t.Setenv("GOOGLE_APPLICATION_CREDENTIALS", "")
+	t.Setenv("STORAGE_EMULATOR_HOST", "")
+	adapter := NewAdapter()
+	require.NoError(t, adapter.Initialize(context.Background(), cloud.CloudConfig{
+		Provider:    cloud.ProviderGCP,
+		GCPProject:  "acme",
+		EndpointURL: server.URL,
+	}))
+	t.Cleanup(func() { assert.NoError(t, adapter.Close()) })
+
+	return adapter
+}
+
+func TestAdapter_ResourceCoverage(t *testing.T) {
+	tests := []struct {
+		resourceType string
+		id           string
+		planned      map[string]interface{}
+		state        string
+		properties   map[string]interface{}
+		drift        []string
+	}{
+		{
+			resourceType: "google_compute_firewall",
+			id:           "projects/acme/global/firewalls/web",
+			planned: map[string]interface{}{
+				"network":       "default",
+				"source_ranges": []interface{}{"0.0.0.0/0", "10.0.0.0/8"},
+				"allow":         []interface{}{map[string]interface{}{"protocol": "tcp", "ports": []interface{}{"443"}}},
+			},
+			properties: map[string]interface{}{"priority": int32(1000), "direction": "INGRESS"},
+			drift:      []string{"Attribute 'allow' differs: planned=[map[ports:[443] protocol:tcp]], actual=[tcp:22,443]"},
+		},
+		{
+			resourceType: "google_compute_disk",
+			id:           "projects/acme/zones/europe-west1-b/disks/data",
+			planned:      map[string]interface{}{"type": "pd-balanced", "size": float64(100), "zone": "europe-west1-b"},
+			state:        "READY",
+			properties:   map[string]interface{}{"size": int64(100)},
+			drift:        []string{"Attribute 'type' differs: planned=pd-balanced, actual=https://www.googleapis.com/compute/v1/projects/acme/zones/europe-west1-b/diskTypes/pd-ssd"},
+		},
+		{
+			resourceType: "google_sql_database_instance",
+			id:           "db",
+			planned: map[string]interface{}{
+				"database_version": "POSTGRES_15",
+				"settings": []interface{}{map[string]interface{}{
+					"tier":              "db-custom-2-7680",
+					"availability_type": "REGIONAL",
+					"user_labels":       map[string]interface{}{"env": "prod", "team": "data"},
+				}},
+			},
+			state:      "RUNNABLE",
+			properties: map[string]interface{}{"disk_size": int64(50), "ipv4_enabled": true},
+			drift:      []string{"Attribute 'settings[0].availability_type' differs: planned=REGIONAL, actual=ZONAL", "Label 'team' missing"},
+		},
+		{
+			resourceType: "google_kms_crypto_key",
+			id:           keyID,
+			planned:      map[string]interface{}{"purpose": "ENCRYPT_DECRYPT", "rotation_period": "2592000s"},
+			state:        "ENABLED",
+			properties:   map[string]interface{}{"protection_level": "SOFTWARE"},
+			drift:        []string{"Attribute 'rotation_period' differs: planned=2592000s, actual=7776000s"},
+		},
+		{
+			resourceType: "google_container_cluster",
+			id:           "projects/acme/locations/europe-west1/clusters/gke",
+			planned: map[string]interface{}{
+				"min_master_version": "1.29",
+				"network":            "projects/acme/global/networks/default",
+				"release_channel":    []interface{}{map[string]interface{}{"channel": "STABLE"}},
+			},
+			state:      "RUNNING",
+			properties: map[string]interface{}{"enable_private_nodes": true},
+			drift:      []string{"Attribute 'release_channel[0].channel' differs: planned=STABLE, actual=REGULAR"},
+		},
+		{
+			resourceType: "google_project_iam_binding",
+			id:           "acme/roles/viewer",
+			planned:      map[string]interface{}{"members": []interface{}{"user:ana@example.com"}},
+			properties:   map[string]interface{}{"role": "roles/viewer"},
+			drift:        []string{"Attribute 'members' differs: planned=[user:ana@example.com], actual=[group:ops@example.com user:ana@example.com]"},
+		},
+		{
+			resourceType: "google_project_iam_member",
+			id:           "acme/projects/acme/roles/deployer/serviceAccount:ci@acme.iam.gserviceaccount.com",
+			properties:   map[string]interface{}{"role": "projects/acme/roles/deployer"},
+		},
+	}
+
+	ctx := context.Background()
+	adapter := newStandInAdapter(t)
+	for _, tt := range tests {
+		t.Run(tt.resourceType, func(t *testing.T) {
+			status, err := adapter.DetectDrift(ctx, tt.planned, tt.resourceType, tt.id)
+			require.NoError(t, err)
+			assert.True(t, status.Exists)
+			assert.Equal(t, tt.state, status.State)
+			for property, value := range tt.properties {
+				assert.Equal(t, value, status.Properties[property], property)
+			}
+			assert.Equal(t, tt.drift, status.DriftDetails)
+		})
+	}
+}
+
+func TestAdapter_ResourceCoverageMissing(t *testing.T) {
+	ctx := context.Background()
+	adapter := newStandInAdapter(t)
+
+	for resourceType, id := range map[string]string{
+		"google_compute_firewall":      "projects/acme/global/firewalls/gone",
+		"google_compute_disk":          "projects/acme/zones/europe-west1-b/disks/gone",
+		"google_sql_database_instance": "gone",
+		"google_kms_crypto_key":        "projects/acme/locations/europe/keyRings/ring/cryptoKeys/gone",
+		"google_container_cluster":     "projects/acme/locations/europe-west1/clusters/gone",
+		"google_project_iam_binding":   "acme/roles/owner",
+		"google_project_iam_member":    "acme/roles/viewer/user:bob@example.com",
+	} {
+		status, err := adapter.DetectDrift(ctx, nil, resourceType, id)
+		require.NoError(t, err, resourceType)
+		assert.False(t, status.Exists, resourceType)
+		assert.Equal(t, []string{"Resource does not exist in GCP"}, status.DriftDetails, resourceType)
+	}
+
+	_, err := adapter.GetResourceStatus(ctx, "google_compute_disk", "data")
+	assert.True(t, err != nil && strings.Contains(err.Error(), "invalid GCP resource ID format"))
+}
+
+func TestAdapter_ValidateResourceCompliance(t *testing.T) {
+	ctx := context.Background()
+	adapter := newStandInAdapter(t)
+
+	rules := []cloud.ValidationRule{
+		{Name: "gcs-uniform-access", Severity: "error", Conditions: map[string]interface{}{"uniform_bucket_level_access": true}},
+		{Name: "gcs-public-access-blocked", Severity: "error", Conditions: map[string]interface{}{"public_access.blocked": true}},
+		{Name: "gcs-versioning", Severity: "warning", Conditions: map[string]interface{}{"versioning.enabled": true}},
+	}
+	results, err := adapter.ValidateResourceCompliance(ctx, "google_storage_bucket", "acme-assets", rules)
+	require.NoError(t, err)
+	require.Len(t, results, 3)
+	assert.Equal(t, []string{"Deployed attribute 'uniform_bucket_level_access' has value 'false', expected 'true'"}, results[0].Details)
+	assert.Equal(t, []string{"Public access prevention is inherited, not enforced"}, results[1].Details)
+	assert.True(t, results[2].Passed)
+	assert.Equal(t, cloud.SourceLive, results[2].Source)
+
+	results, err = adapter.ValidateResourceCompliance(ctx, "google_storage_bucket", "gone", rules)
+	require.NoError(t, err)
+	assert.Empty(t, results)
+}
+
+func TestAdapter_ListResources(t *testing.T) {
+	ctx := context.Background()
+	adapter := newStandInAdapter(t)
+
+	tests := map[string][]string{
+		"google_compute_instance":      {"projects/acme/zones/europe-west1-b/instances/web"},
+		"google_storage_bucket":        {"acme-assets"},
+		"google_compute_firewall":      {"projects/acme/global/firewalls/web", "projects/acme/global/firewalls/default-allow-ssh"},
+		"google_compute_disk":          {"projects/acme/zones/europe-west1-b/disks/data"},
+		"google_sql_database_instance": {"db"},
+		"google_kms_crypto_key":        {keyID},
+		"google_container_cluster":     {"projects/acme/locations/europe-west1/clusters/gke"},
+		"google_project_iam_binding":   {"acme/roles/viewer", "acme/projects/acme/roles/deployer"},
+		"google_project_iam_member": {
+			"acme/roles/viewer/user:ana@example.com",
+			"acme/roles/viewer/group:ops@example.com",
+			"acme/projects/acme/roles/deployer/serviceAccount:ci@acme.iam.gserviceaccount.com",
+		},
+	}
+	for resourceType, expected := range tests {
+		ids, err := adapter.ListResources(ctx, resourceType)
+		require.NoError(t, err, resourceType)
+		assert.Equal(t, expected, ids, resourceType)
+	}
+
+	_, err := adapter.ListResources(ctx, "google_pubsub_topic")
+	assert.True(t, err != nil && strings.Contains(err.Error(), "listing not supported"))
+}
+
+func TestParseIAMID(t *testing.T) {
+	project, role, member, err := parseIAMID("acme/organizations/42/roles/auditor/user:ana@example.com")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"acme", "organizations/42/roles/auditor", "user:ana@example.com"}, []string{project, role, member})
+
+	_, role, member, err = parseIAMID("acme/roles/viewer")
+	require.NoError(t, err)
+	assert.Equal(t, "roles/viewer", role)
+	assert.Empty(t, member)
+
+	_, _, _, err = parseIAMID("acme")
+	assert.Error(t, err)
+}
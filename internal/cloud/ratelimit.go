@@ -0,0 +1,73 @@
+package cloud
+
+import (
+	"net/http"
+	"sync"
+
+	"golang.org/x/time/rate"
+)
+
+// DefaultRateLimits are the default cloud API request rates, in requests per
+// second, used when no rate limit is configured. They stay well below the
+// documented throttling limits of each provider.
+var DefaultRateLimits = map[Provider]float64{
+	ProviderAWS:   20,
+	ProviderAzure: 10,
+	ProviderGCP:   10,
+}
+
+// RateLimiters hands out one request budget per provider, shared by all of
+// its adapters, e.g. those of aliased provider blocks, and by concurrent
+// validation workers
+type RateLimiters struct {
+	rps      float64
+	mu       sync.Mutex
+	limiters map[Provider]*rate.Limiter
+}
+
+// NewRateLimiters limits the API requests of each provider to rps requests
+// per second. An rps of zero uses the provider's default rate; a negative rps
+// disables limiting.
+func NewRateLimiters(rps float64) *RateLimiters {
+	return &RateLimiters{rps: rps, limiters: make(map[Provider]*rate.Limiter)}
+}
+
+// For returns the limiter of a provider, or nil when its requests are not
+// limited
+func (l *RateLimiters) For(provider Provider) *rate.Limiter {
+	rps := l.rps
+	if rps == 0 {
+		rps = DefaultRateLimits[provider]
+	}
+	if rps <= 0 {
+		return nil
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	limiter, ok := l.limiters[provider]
+	if !ok {
+		burst := int(rps)
+		if burst < 1 {
+			burst = 1
+		}
+		limiter = rate.NewLimiter(rate.Limit(rps), burst)
+		l.limiters[provider] = limiter
+	}
+	return limiter
+}
+
+// rateLimitedTransport waits for the limiter before sending each request
+type rateLimitedTransport struct {
+	base    http.RoundTripper
+	limiter *rate.Limiter
+}
+
+// RoundTrip waits for the rate limiter before sending the request
+func (t rateLimitedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := t.limiter.Wait(req.Context()); err != nil {
+		return nil, err
+	}
+	return t.base.RoundTrip(req)
+}
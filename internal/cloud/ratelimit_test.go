@@ -0,0 +1,117 @@
+package cloud
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// countingServer counts the requests it answers
+func countingServer(t *testing.T) (*httptest.Server, *atomic.Int32) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+	}))
+	t.Cleanup(server.Close)
+	return server, &requests
+}
+
+// get sends a request through transport
+func get(ctx context.Context, transport http.RoundTripper, url string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := transport.RoundTrip(req)
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+func TestRateLimiters_Disabled(t *testing.T) {
+	assert.Nil(t, NewRateLimiters(-1).For(ProviderAWS))
+
+	transport, recorder, err := CloudConfig{Provider: ProviderAWS}.Transport(nil)
+	require.NoError(t, err)
+	assert.Nil(t, transport)
+	assert.Nil(t, recorder)
+}
+
+func TestRateLimiters_DefaultsPerProvider(t *testing.T) {
+	limiters := NewRateLimiters(0)
+	for _, provider := range []Provider{ProviderAWS, ProviderAzure, ProviderGCP} {
+		limiter := limiters.For(provider)
+		require.NotNil(t, limiter, provider)
+		assert.InDelta(t, DefaultRateLimits[provider], float64(limiter.Limit()), 0.001, provider)
+	}
+
+	assert.Same(t, limiters.For(ProviderAWS), limiters.For(ProviderAWS))
+	assert.NotSame(t, limiters.For(ProviderAWS), limiters.For(ProviderAzure))
+}
+
+func TestTransport_DelaysRequests(t *testing.T) {
+	server, requests := countingServer(t)
+	config := CloudConfig{Provider: ProviderAWS, RateLimiter: NewRateLimiters(20).For(ProviderAWS)}
+	transport, recorder, err := config.Transport(nil)
+	require.NoError(t, err)
+	assert.Nil(t, recorder)
+
+	start := time.Now()
+	for i := 0; i < 25; i++ {
+		require.NoError(t, get(context.Background(), transport, server.URL))
+	}
+
+	// A burst of 20 passes immediately; the remaining 5 wait for tokens
+	assert.GreaterOrEqual(t, time.Since(start), 200*time.Millisecond)
+	assert.Equal(t, int32(25), requests.Load())
+}
+
+func TestTransport_SharesProviderBudget(t *testing.T) {
+	server, requests := countingServer(t)
+	limiters := NewRateLimiters(10)
+
+	// Two provider blocks of one provider, e.g. an aliased one in another region
+	base := CloudConfig{Provider: ProviderAWS, AWSRegion: "us-east-1"}
+	aliased := base.WithProviderBlock(ProviderAWS, map[string]interface{}{"region": "eu-west-1"})
+
+	var transports []http.RoundTripper
+	for _, config := range []CloudConfig{base, aliased} {
+		config.RateLimiter = limiters.For(config.Provider)
+		transport, _, err := config.Transport(nil)
+		require.NoError(t, err)
+		transports = append(transports, transport)
+	}
+
+	start := time.Now()
+	for i := 0; i < 10; i++ {
+		for _, transport := range transports {
+			require.NoError(t, get(context.Background(), transport, server.URL))
+		}
+	}
+
+	// 20 requests at 10 per second: a burst of 10, then 10 waiting for
+	// tokens, which two budgets of their own would not have
+	assert.GreaterOrEqual(t, time.Since(start), 900*time.Millisecond)
+	assert.Equal(t, int32(20), requests.Load())
+}
+
+func TestTransport_HonoursContext(t *testing.T) {
+	server, requests := countingServer(t)
+	config := CloudConfig{Provider: ProviderAWS, RateLimiter: NewRateLimiters(1).For(ProviderAWS)}
+	transport, _, err := config.Transport(nil)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	require.NoError(t, get(ctx, transport, server.URL))
+
+	cancel()
+	assert.Error(t, get(ctx, transport, server.URL))
+	assert.Equal(t, int32(1), requests.Load())
+}
@@ -0,0 +1,135 @@
+package cloud
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// defaultRegistryHost is the registry terraform assumes for provider source
+// addresses without a hostname
+const defaultRegistryHost = "registry.terraform.io"
+
+// Factory creates an uninitialized adapter
+type Factory func() Adapter
+
+var (
+	registryMu sync.RWMutex
+	factories  = make(map[Provider]Factory)
+	sources    = make(map[string]Provider) // normalized provider source address -> adapter
+)
+
+// Register makes an adapter available under name, typically from the init
+// function of the adapter package. sources are the terraform provider source
+// addresses (e.g. "hashicorp/aws") whose resources the adapter handles.
+// Register panics if name or a source is registered twice.
+func Register(name Provider, factory Factory, providerSources ...string) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	if factory == nil {
+		panic(fmt.Sprintf("cloud: Register factory for %s is nil", name))
+	}
+	if _, dup := factories[name]; dup {
+		panic(fmt.Sprintf("cloud: Register called twice for adapter %s", name))
+	}
+
+	for _, source := range providerSources {
+		source = NormalizeSource(source)
+		if existing, dup := sources[source]; dup {
+			panic(fmt.Sprintf("cloud: provider source %s registered for both %s and %s", source, existing, name))
+		}
+		sources[source] = name
+	}
+	factories[name] = factory
+}
+
+// New creates an uninitialized adapter of a registered provider
+func New(name Provider) (Adapter, error) {
+	registryMu.RLock()
+	factory, ok := factories[name]
+	registryMu.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("unsupported cloud provider: %s (available: %s)", name, strings.Join(providerNames(Registered()), ", "))
+	}
+	return factory(), nil
+}
+
+// IsRegistered reports whether an adapter is registered under name
+func IsRegistered(name Provider) bool {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	_, ok := factories[name]
+	return ok
+}
+
+// Registered returns the names of the registered adapters, sorted
+func Registered() []Provider {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	names := make([]Provider, 0, len(factories))
+	for name := range factories {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool { return names[i] < names[j] })
+	return names
+}
+
+// ProviderForSource returns the adapter that handles resources of a terraform
+// provider, given its source address as it appears in the plan (e.g.
+// "registry.terraform.io/hashicorp/aws"). mappings maps source addresses to
+// adapter names and takes precedence over the sources adapters registered.
+func ProviderForSource(source string, mappings map[string]string) (Provider, bool) {
+	source = NormalizeSource(source)
+	if source == "" {
+		return "", false
+	}
+
+	for from, to := range mappings {
+		if NormalizeSource(from) == source {
+			return Provider(to), true
+		}
+	}
+
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	name, ok := sources[source]
+	return name, ok
+}
+
+// NormalizeSource expands a terraform provider source address to its fully
+// qualified hostname/namespace/type form, the way terraform does: "aws"
+// becomes "registry.terraform.io/hashicorp/aws". Legacy "provider.aws" and
+// "provider[\"registry.terraform.io/hashicorp/aws\"]" forms are accepted.
+func NormalizeSource(source string) string {
+	source = strings.ToLower(strings.TrimSpace(source))
+	source = strings.TrimPrefix(source, "provider.")
+	if strings.HasPrefix(source, `provider["`) && strings.HasSuffix(source, `"]`) {
+		source = source[len(`provider["`) : len(source)-len(`"]`)]
+	}
+
+	switch parts := strings.Split(source, "/"); len(parts) {
+	case 1:
+		if source == "" {
+			return ""
+		}
+		return defaultRegistryHost + "/hashicorp/" + source
+	case 2:
+		return defaultRegistryHost + "/" + source
+	default:
+		return source
+	}
+}
+
+func providerNames(providers []Provider) []string {
+	names := make([]string, len(providers))
+	for i, provider := range providers {
+		names[i] = string(provider)
+	}
+	return names
+}
@@ -0,0 +1,72 @@
+package cloud
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testAdapter is an adapter of no cloud, for registering
+type testAdapter struct {
+	Adapter
+}
+
+func TestRegistry_RegisterAndNew(t *testing.T) {
+	Register("test-registry", func() Adapter { return &testAdapter{} }, "example/test")
+
+	assert.True(t, IsRegistered("test-registry"))
+	assert.Contains(t, Registered(), Provider("test-registry"))
+
+	adapter, err := New("test-registry")
+	require.NoError(t, err)
+	assert.IsType(t, &testAdapter{}, adapter)
+
+	_, err = New("no-such-provider")
+	assert.ErrorContains(t, err, "unsupported cloud provider: no-such-provider")
+	assert.ErrorContains(t, err, "test-registry")
+
+	assert.Panics(t, func() {
+		Register("test-registry", func() Adapter { return &testAdapter{} })
+	})
+	assert.Panics(t, func() {
+		Register("test-registry-other", func() Adapter { return &testAdapter{} }, "registry.terraform.io/example/test")
+	})
+}
+
+func TestNormalizeSource(t *testing.T) {
+	tests := map[string]string{
+		"aws":                                 "registry.terraform.io/hashicorp/aws",
+		"hashicorp/google":                    "registry.terraform.io/hashicorp/google",
+		"registry.terraform.io/hashicorp/aws": "registry.terraform.io/hashicorp/aws",
+		"example.com/Acme/AWS":                "example.com/acme/aws",
+		"provider.azurerm":                    "registry.terraform.io/hashicorp/azurerm",
+		`provider["registry.terraform.io/hashicorp/aws"]`: "registry.terraform.io/hashicorp/aws",
+		"": "",
+	}
+
+	for source, expected := range tests {
+		assert.Equal(t, expected, NormalizeSource(source), source)
+	}
+}
+
+func TestProviderForSource(t *testing.T) {
+	Register("test-source", func() Adapter { return &testAdapter{} }, "example/source")
+
+	provider, ok := ProviderForSource("registry.terraform.io/example/source", nil)
+	require.True(t, ok)
+	assert.Equal(t, Provider("test-source"), provider)
+
+	_, ok = ProviderForSource("registry.terraform.io/acme/source", nil)
+	assert.False(t, ok)
+
+	mappings := map[string]string{"acme/source": "test-source"}
+	provider, ok = ProviderForSource("registry.terraform.io/acme/source", mappings)
+	require.True(t, ok)
+	assert.Equal(t, Provider("test-source"), provider)
+
+	// Mappings take precedence over registered sources
+	provider, ok = ProviderForSource("example/source", map[string]string{"example/source": "other"})
+	require.True(t, ok)
+	assert.Equal(t, Provider("other"), provider)
+}
@@ -0,0 +1,162 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/vijayaxai/terraship/internal/discovery"
+	"github.com/vijayaxai/terraship/internal/terraform"
+)
+
+// Discover lists the live resources of the types Terraform manages through the
+// cloud adapters and reports those that are not under Terraform control. The
+// managed resources are read from the state file when StatePath is set, else
+// from the pre-generated plan or a generated one; resources the plan still has
+// to create have no ID yet and match nothing.
+func (v *Validator) Discover(ctx context.Context) (*discovery.Report, error) {
+	report, err := v.discover(ctx)
+
+	// Close the cloud adapters even when discovery failed, so recorded
+	// cassettes are saved
+	if closeErr := v.closeCloudAdapters(); closeErr != nil && err == nil {
+		return nil, fmt.Errorf("failed to close cloud adapter: %w", closeErr)
+	}
+
+	return report, err
+}
+
+func (v *Validator) discover(ctx context.Context) (*discovery.Report, error) {
+	resources, err := v.managedResources(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	managed := discovery.Managed{}
+	types := make(map[string][]string) // resource types by adapters key
+	for _, resource := range resources {
+		if resource.Mode == "data" {
+			continue
+		}
+		managed.Add(resource.Type, resourceIDs(resource)...)
+
+		key := v.providerKey(resource)
+		if _, ok := v.adapters[key]; !ok {
+			key = defaultAdapter
+		}
+		if _, ok := v.adapters[key]; ok {
+			types[key] = append(types[key], resource.Type)
+		}
+	}
+
+	var scopes []discovery.Scope
+	for key, adapter := range v.adapters {
+		if len(types[key]) == 0 {
+			continue
+		}
+		scopes = append(scopes, discovery.Scope{
+			Name:    describeAdapter(adapter.Name(), key),
+			Adapter: adapter,
+			Types:   types[key],
+		})
+	}
+	sort.Slice(scopes, func(i, j int) bool { return scopes[i].Name < scopes[j].Name })
+
+	return discovery.Discover(ctx, scopes, managed)
+}
+
+// managedResources reads the resources Terraform manages and initializes the
+// cloud adapters that list them. Resources the plan deletes or replaces are
+// still managed, so their prior values are included.
+func (v *Validator) managedResources(ctx context.Context) ([]terraform.Resource, error) {
+	if v.config.StatePath != "" {
+		state, err := terraform.LoadStateFile(v.config.StatePath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load state: %w", err)
+		}
+		if state.Values == nil || state.Values.RootModule == nil {
+			return nil, fmt.Errorf("no resources found")
+		}
+		resources := v.collectResources(state.Values.RootModule)
+
+		if err := v.initializeStateAdapters(ctx, resources); err != nil {
+			return nil, fmt.Errorf("failed to initialize cloud adapter: %w", err)
+		}
+		return resources, nil
+	}
+
+	var plan *terraform.PlanOutput
+	var err error
+	if v.config.PlanJSONPath != "" {
+		plan, err = terraform.LoadPlanFile(v.config.PlanJSONPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load plan: %w", err)
+		}
+	} else {
+		// A plan file of its own, so concurrent runs do not overwrite it
+		file, err := os.CreateTemp("", "terraship-discover-*.tfplan")
+		if err != nil {
+			return nil, fmt.Errorf("failed to create plan file: %w", err)
+		}
+		planFile := file.Name()
+		file.Close()
+		defer os.Remove(planFile)
+
+		if plan, err = v.generatePlan(ctx, planFile); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := v.initializeCloudAdapters(ctx, plan); err != nil {
+		return nil, fmt.Errorf("failed to initialize cloud adapter: %w", err)
+	}
+
+	var resources []terraform.Resource
+	if plan.PlannedValues != nil && plan.PlannedValues.RootModule != nil {
+		resources = v.collectResources(plan.PlannedValues.RootModule)
+	}
+	for _, change := range plan.ResourceChanges {
+		if change.Change == nil || change.Change.Before == nil {
+			continue
+		}
+		resources = append(resources, terraform.Resource{
+			Address:      change.Address,
+			Mode:         change.Mode,
+			Type:         change.Type,
+			Name:         change.Name,
+			ProviderName: change.ProviderName,
+			Values:       change.Change.Before,
+		})
+	}
+	return resources, nil
+}
+
+// initializeStateAdapters creates the cloud adapters of the resources of a
+// state, which records no provider blocks: the explicit cloud provider's, or
+// else one per provider of the resources
+func (v *Validator) initializeStateAdapters(ctx context.Context, resources []terraform.Resource) error {
+	if v.config.CloudProvider != "" {
+		return v.initializeCloudAdapters(ctx, &terraform.PlanOutput{})
+	}
+
+	if err := v.initializeResourceAdapters(ctx, resources, nil); err != nil {
+		return err
+	}
+	if len(v.adapters) == 0 {
+		return fmt.Errorf("no resource uses a provider with a cloud adapter; specify the cloud provider")
+	}
+	return nil
+}
+
+// resourceIDs returns the values a cloud adapter may list a resource by: its
+// id, name and ARN
+func resourceIDs(resource terraform.Resource) []string {
+	var ids []string
+	for _, attribute := range []string{"id", "name", "arn"} {
+		if id, ok := resource.Values[attribute].(string); ok && id != "" {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
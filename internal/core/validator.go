@@ -6,13 +6,13 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sync"
 
+	"github.com/vijayaxai/terraship/internal/baseline"
 	"github.com/vijayaxai/terraship/internal/cloud"
-	awsadapter "github.com/vijayaxai/terraship/internal/cloud/aws"
-	azureadapter "github.com/vijayaxai/terraship/internal/cloud/azure"
-	gcpadapter "github.com/vijayaxai/terraship/internal/cloud/gcp"
 	"github.com/vijayaxai/terraship/internal/rules"
 	"github.com/vijayaxai/terraship/internal/terraform"
+	"github.com/vijayaxai/terraship/internal/waivers"
 )
 
 // ValidationMode defines how validation is performed
@@ -29,21 +29,64 @@ const (
 type ValidatorConfig struct {
 	Mode          ValidationMode
 	WorkingDir    string
-	PolicyPath    string
-	CloudProvider string // manual override; empty for auto-detect
-	OutputFormat  string // "human", "json", "sarif"
+	PolicyPaths   []string // policy files, directories or glob patterns
+	CloudProvider string   // manual override; empty for auto-detect
+	OutputFormat  string   // "human", "json", "sarif"
 	OutputFile    string
 	NoDestroy     bool // for ephemeral mode
 	Verbose       bool
+	PlanJSONPath  string  // pre-generated terraform show -json output; skips terraform, and cloud access unless CloudProvider is set
+	StatePath     string  // terraform show -json state or raw terraform.tfstate; same as PlanJSONPath
+	WaiversPath   string  // optional waivers YAML file; inline ignore comments are read from WorkingDir
+	BaselinePath  string  // optional baseline of accepted findings; only new findings fail
+	Parallelism   int     // concurrent resource evaluations; 0 uses DefaultParallelism
+	RateLimit     float64 // cloud API requests per second; 0 uses the provider default, negative disables
+
+	// ProviderMappings maps terraform provider source addresses in the plan
+	// (e.g. registry.terraform.io/acme/aws) to registered adapter names
+	ProviderMappings map[string]string
+
+	// Cloud is the base configuration of every adapter, e.g. the region,
+	// fake fixture or cassette directory given on the command line; provider
+	// blocks override it
+	Cloud cloud.CloudConfig
 }
 
+// DefaultParallelism is the number of resources evaluated concurrently when
+// ValidatorConfig.Parallelism is not set
+const DefaultParallelism = 10
+
 // Validator orchestrates the validation process
 type Validator struct {
-	config       ValidatorConfig
-	tfClient     *terraform.Client
-	cloudAdapter cloud.Adapter
-	rulesEngine  *rules.Engine
-	results      []ValidationReport
+	config      ValidatorConfig
+	tfClient    terraformClient
+	adapters    map[string]cloud.Adapter // cloud adapters by provider configuration; see providerKey
+	limiters    *cloud.RateLimiters      // API request budgets shared by the adapters of each provider
+	rulesEngine *rules.Engine
+	waivers     *waivers.Set
+	baseline    *baseline.Baseline
+	results     []ValidationReport
+
+	// Plan metadata and planned actions by resource address, exposed to
+	// expr conditions
+	planContext     map[string]interface{}
+	resourceActions map[string][]string
+
+	// Provider config key by resource configuration address
+	resourceProviders map[string]string
+}
+
+// terraformClient runs the terraform commands of the validation workflow; it
+// is implemented by *terraform.Client
+type terraformClient interface {
+	Init(ctx context.Context, upgrade bool) error
+	Validate(ctx context.Context) error
+	Plan(ctx context.Context, planFile string) error
+	ShowJSON(ctx context.Context, planFile string) (*terraform.PlanOutput, error)
+	ShowState(ctx context.Context) (*terraform.StateOutput, error)
+	Apply(ctx context.Context, planFile string) error
+	Destroy(ctx context.Context, autoApprove bool) error
+	GetProvider(ctx context.Context) (string, error)
 }
 
 // ValidationReport contains the results of validation
@@ -65,42 +108,159 @@ type Summary struct {
 	WarningResources int                `json:"warning_resources"`
 	ErrorResources   int                `json:"error_resources"`
 	DriftDetected    int                `json:"drift_detected"`
+	WaivedFindings   int                `json:"waived_findings"`
+	BaselineFindings int                `json:"baseline_findings"`
 	Reports          []ValidationReport `json:"reports"`
 }
 
 // NewValidator creates a new validator instance
 func NewValidator(config ValidatorConfig) (*Validator, error) {
-	// Validate config
-	if config.WorkingDir == "" {
-		return nil, fmt.Errorf("working directory is required")
-	}
-
-	if config.PolicyPath == "" {
+	if len(config.PolicyPaths) == 0 {
 		return nil, fmt.Errorf("policy path is required")
 	}
 
-	// Create Terraform client
-	tfClient, err := terraform.NewClient(config.WorkingDir)
+	v, err := newValidator(config)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create terraform client: %w", err)
+		return nil, err
 	}
 
 	// Load rules engine
-	rulesEngine, err := rules.NewEngine(config.PolicyPath)
+	v.rulesEngine, err = rules.NewEngine(config.PolicyPaths...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to load policy: %w", err)
 	}
 
+	// Load waivers from the waivers file and inline ignore comments
+	v.waivers, err = waivers.Load(config.WaiversPath, config.WorkingDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load waivers: %w", err)
+	}
+
+	// Load baseline of accepted findings
+	if config.BaselinePath != "" {
+		v.baseline, err = baseline.Load(config.BaselinePath)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return v, nil
+}
+
+// NewDiscoverer creates a validator that only runs Discover, so it needs no
+// policy
+func NewDiscoverer(config ValidatorConfig) (*Validator, error) {
+	return newValidator(config)
+}
+
+// newValidator checks the configuration and creates a validator without
+// policies
+func newValidator(config ValidatorConfig) (*Validator, error) {
+	// Validate config
+	if config.WorkingDir == "" {
+		return nil, fmt.Errorf("working directory is required")
+	}
+
+	if config.PlanJSONPath != "" && config.StatePath != "" {
+		return nil, fmt.Errorf("a plan JSON file and a state file cannot be validated together")
+	}
+
+	// Validate the cloud provider and provider mappings name registered adapters
+	if config.CloudProvider != "" {
+		if _, err := cloud.New(cloud.Provider(config.CloudProvider)); err != nil {
+			return nil, err
+		}
+	}
+	for source, provider := range config.ProviderMappings {
+		if _, err := cloud.New(cloud.Provider(provider)); err != nil {
+			return nil, fmt.Errorf("invalid provider mapping for %s: %w", source, err)
+		}
+	}
+
+	offline := config.PlanJSONPath != "" || config.StatePath != ""
+	if offline && config.Mode == ModeEphemeralSandbox {
+		return nil, fmt.Errorf("ephemeral-sandbox mode cannot be used with a pre-generated plan or state file")
+	}
+
+	// Create Terraform client (not needed when validating a pre-generated plan or state)
+	var tfClient terraformClient
+	if !offline {
+		client, err := terraform.NewClient(config.WorkingDir)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create terraform client: %w", err)
+		}
+		tfClient = client
+	}
+
 	return &Validator{
-		config:      config,
-		tfClient:    tfClient,
-		rulesEngine: rulesEngine,
-		results:     make([]ValidationReport, 0),
+		config:   config,
+		tfClient: tfClient,
+		limiters: cloud.NewRateLimiters(config.RateLimit),
+		results:  make([]ValidationReport, 0),
 	}, nil
 }
 
 // Validate performs the validation workflow
 func (v *Validator) Validate(ctx context.Context) (*Summary, error) {
+	summary, err := v.validate(ctx)
+
+	// Close the cloud adapters even when validation failed, so recorded
+	// cassettes are saved
+	if closeErr := v.closeCloudAdapters(); closeErr != nil && err == nil {
+		return nil, fmt.Errorf("failed to close cloud adapter: %w", closeErr)
+	}
+
+	return summary, err
+}
+
+func (v *Validator) validate(ctx context.Context) (*Summary, error) {
+	if v.config.PlanJSONPath != "" {
+		return v.validatePlanFile(ctx)
+	}
+	if v.config.StatePath != "" {
+		return v.validateStateFile(ctx)
+	}
+
+	// Steps 1-4: Initialize, validate and plan the configuration
+	planFile := filepath.Join(os.TempDir(), "terraship-plan.tfplan")
+	defer os.Remove(planFile)
+
+	plan, err := v.generatePlan(ctx, planFile)
+	if err != nil {
+		return nil, err
+	}
+
+	// Step 5: Initialize a cloud adapter per provider configuration
+	if err := v.initializeCloudAdapters(ctx, plan); err != nil {
+		return nil, fmt.Errorf("failed to initialize cloud adapter: %w", err)
+	}
+
+	// Step 6: Validate resources
+	v.setPlanContext(plan)
+	if err := v.validateResources(ctx, plan.PlannedValues); err != nil {
+		return nil, fmt.Errorf("resource validation failed: %w", err)
+	}
+	if err := v.validateDocument(ctx, plan); err != nil {
+		return nil, fmt.Errorf("plan policy validation failed: %w", err)
+	}
+
+	// Step 7: For ephemeral mode, apply, verify the created resources and
+	// then destroy
+	if v.config.Mode == ModeEphemeralSandbox {
+		if err := v.runEphemeralMode(ctx, plan, planFile); err != nil {
+			return nil, fmt.Errorf("ephemeral mode failed: %w", err)
+		}
+	}
+
+	// Step 8: Generate summary
+	summary := v.generateSummary()
+
+	return summary, nil
+}
+
+// generatePlan initializes and validates the Terraform configuration, plans it
+// to planFile and parses the plan
+func (v *Validator) generatePlan(ctx context.Context, planFile string) (*terraform.PlanOutput, error) {
 	// Step 1: Initialize Terraform
 	if err := v.tfClient.Init(ctx, false); err != nil {
 		return nil, fmt.Errorf("terraform init failed: %w", err)
@@ -111,96 +271,301 @@ func (v *Validator) Validate(ctx context.Context) (*Summary, error) {
 		return nil, fmt.Errorf("terraform validate failed: %w", err)
 	}
 
-	// Step 3: Detect or set cloud provider
-	provider := v.config.CloudProvider
-	if provider == "" {
-		detectedProvider, err := v.tfClient.GetProvider(ctx)
-		if err != nil {
-			return nil, fmt.Errorf("failed to detect cloud provider: %w", err)
-		}
-		provider = detectedProvider
+	// Step 3: Generate Terraform plan
+	if err := v.tfClient.Plan(ctx, planFile); err != nil {
+		return nil, fmt.Errorf("terraform plan failed: %w", err)
 	}
 
-	// Step 4: Initialize cloud adapter
-	if err := v.initializeCloudAdapter(ctx, provider); err != nil {
-		return nil, fmt.Errorf("failed to initialize cloud adapter: %w", err)
+	// Step 4: Parse plan output
+	plan, err := v.tfClient.ShowJSON(ctx, planFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse plan: %w", err)
 	}
+	return plan, nil
+}
 
-	// Step 5: Generate Terraform plan
-	planFile := filepath.Join(os.TempDir(), "terraship-plan.tfplan")
-	defer os.Remove(planFile)
+// validatePlanFile evaluates policy rules against a pre-generated plan JSON file.
+// No terraform binary is used. Drift detection is skipped unless a cloud
+// provider is given explicitly, e.g. the fake adapter for offline runs.
+func (v *Validator) validatePlanFile(ctx context.Context) (*Summary, error) {
+	plan, err := terraform.LoadPlanFile(v.config.PlanJSONPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load plan: %w", err)
+	}
 
-	if err := v.tfClient.Plan(ctx, planFile); err != nil {
-		return nil, fmt.Errorf("terraform plan failed: %w", err)
+	if v.config.CloudProvider != "" {
+		if err := v.initializeCloudAdapters(ctx, plan); err != nil {
+			return nil, fmt.Errorf("failed to initialize cloud adapter: %w", err)
+		}
 	}
 
-	// Step 6: Parse plan output
-	plan, err := v.tfClient.ShowJSON(ctx, planFile)
+	v.setPlanContext(plan)
+	if err := v.validateResources(ctx, plan.PlannedValues); err != nil {
+		return nil, fmt.Errorf("resource validation failed: %w", err)
+	}
+	if err := v.validateDocument(ctx, plan); err != nil {
+		return nil, fmt.Errorf("plan policy validation failed: %w", err)
+	}
+
+	return v.generateSummary(), nil
+}
+
+// validateStateFile evaluates policy rules against the resources recorded in a
+// state file, auditing what is deployed without generating a plan. Like
+// validatePlanFile it detects drift only with an explicit cloud provider.
+func (v *Validator) validateStateFile(ctx context.Context) (*Summary, error) {
+	state, err := terraform.LoadStateFile(v.config.StatePath)
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse plan: %w", err)
+		return nil, fmt.Errorf("failed to load state: %w", err)
+	}
+
+	if v.config.CloudProvider != "" {
+		provider := cloud.Provider(v.config.CloudProvider)
+		if err := v.initializeCloudAdapter(ctx, defaultAdapter, v.config.Cloud.WithProviderBlock(provider, nil)); err != nil {
+			return nil, fmt.Errorf("failed to initialize cloud adapter: %w", err)
+		}
 	}
 
-	// Step 7: Validate resources
-	if err := v.validateResources(ctx, plan); err != nil {
+	v.planContext = map[string]interface{}{
+		"format_version":    state.FormatVersion,
+		"terraform_version": state.TerraformVersion,
+		"variables":         map[string]interface{}{},
+	}
+	if err := v.validateResources(ctx, state.Values); err != nil {
 		return nil, fmt.Errorf("resource validation failed: %w", err)
 	}
+	if err := v.validateDocument(ctx, state); err != nil {
+		return nil, fmt.Errorf("state policy validation failed: %w", err)
+	}
 
-	// Step 8: For ephemeral mode, apply and then destroy
-	if v.config.Mode == ModeEphemeralSandbox {
-		if err := v.runEphemeralMode(ctx, planFile); err != nil {
-			return nil, fmt.Errorf("ephemeral mode failed: %w", err)
+	return v.generateSummary(), nil
+}
+
+// setPlanContext records the plan metadata and per-resource change actions
+// that expr conditions can reference
+func (v *Validator) setPlanContext(plan *terraform.PlanOutput) {
+	variables := make(map[string]interface{}, len(plan.Variables))
+	for name, variable := range plan.Variables {
+		// terraform show -json wraps each variable as {"value": ...}
+		if wrapped, ok := variable.(map[string]interface{}); ok {
+			if value, ok := wrapped["value"]; ok {
+				variables[name] = value
+				continue
+			}
 		}
+		variables[name] = variable
 	}
 
-	// Step 9: Generate summary
-	summary := v.generateSummary()
+	v.planContext = map[string]interface{}{
+		"format_version":    plan.FormatVersion,
+		"terraform_version": plan.TerraformVersion,
+		"variables":         variables,
+	}
 
-	return summary, nil
+	v.resourceActions = make(map[string][]string, len(plan.ResourceChanges))
+	for _, change := range plan.ResourceChanges {
+		if change.Change != nil {
+			v.resourceActions[change.Address] = change.Change.Actions
+		}
+	}
+}
+
+// evalContext builds the expr condition context for a resource
+func (v *Validator) evalContext(resource terraform.Resource) *rules.EvalContext {
+	return &rules.EvalContext{
+		Resource: map[string]interface{}{
+			"address":       resource.Address,
+			"type":          resource.Type,
+			"name":          resource.Name,
+			"mode":          resource.Mode,
+			"provider_name": resource.ProviderName,
+		},
+		Plan:    v.planContext,
+		Actions: v.resourceActions[resource.Address],
+	}
+}
+
+// defaultAdapter is the adapters key of the adapter used for resources without
+// an adapter of their own: the --provider adapter, or the one detected from
+// the configuration when no plan resource names a registered provider
+const defaultAdapter = ""
+
+// initializeCloudAdapters creates an adapter for every provider configuration
+// used by the plan's resources, so mixed and aliased providers each get drift
+// detection against the right account, region or project. An explicit cloud
+// provider uses a single adapter for every resource instead.
+func (v *Validator) initializeCloudAdapters(ctx context.Context, plan *terraform.PlanOutput) error {
+	if v.config.CloudProvider != "" {
+		provider := cloud.Provider(v.config.CloudProvider)
+		return v.initializeCloudAdapter(ctx, defaultAdapter, v.config.Cloud.WithProviderBlock(provider, nil))
+	}
+
+	configs := plan.ProviderConfigs()
+	v.resourceProviders = plan.ResourceProviderKeys()
+
+	var resources []terraform.Resource
+	if plan.PlannedValues != nil && plan.PlannedValues.RootModule != nil {
+		resources = v.collectResources(plan.PlannedValues.RootModule)
+	}
+
+	if err := v.initializeResourceAdapters(ctx, resources, configs); err != nil {
+		return err
+	}
+	if len(v.adapters) > 0 {
+		return nil
+	}
+
+	// No resource names a registered provider; fall back to the configuration
+	if v.tfClient == nil {
+		return fmt.Errorf("no resource uses a provider with a cloud adapter; specify the cloud provider")
+	}
+	provider, err := v.tfClient.GetProvider(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to detect cloud provider: %w", err)
+	}
+	return v.initializeCloudAdapter(ctx, defaultAdapter, v.config.Cloud.WithProviderBlock(cloud.Provider(provider), nil))
 }
 
-func (v *Validator) initializeCloudAdapter(ctx context.Context, provider string) error {
-	var adapter cloud.Adapter
+// initializeResourceAdapters creates an adapter for every provider
+// configuration of resources that has none yet. configs are the provider
+// blocks of the plan by provider config key; resources without one get an
+// adapter of the base configuration.
+func (v *Validator) initializeResourceAdapters(ctx context.Context, resources []terraform.Resource, configs map[string]terraform.ProviderConfig) error {
+	for _, resource := range resources {
+		key := v.providerKey(resource)
+		if _, ok := v.adapters[key]; ok {
+			continue
+		}
 
-	switch provider {
-	case "aws":
-		adapter = newAWSAdapter() // Will implement next
-	case "azure":
-		adapter = newAzureAdapter()
-	case "gcp":
-		adapter = newGCPAdapter()
-	default:
-		return fmt.Errorf("unsupported cloud provider: %s", provider)
+		provider, ok := cloud.ProviderForSource(resource.ProviderName, v.config.ProviderMappings)
+		if !ok {
+			continue
+		}
+		if err := v.initializeCloudAdapter(ctx, key, v.config.Cloud.WithProviderBlock(provider, configs[key].Settings)); err != nil {
+			return err
+		}
 	}
+	return nil
+}
 
-	config := cloud.CloudConfig{
-		Provider: cloud.Provider(provider),
+func (v *Validator) initializeCloudAdapter(ctx context.Context, key string, config cloud.CloudConfig) error {
+	provider := config.Provider
+	if key != defaultAdapter {
+		// Adapters of other provider configurations record to their own cassette
+		config.CassetteName = key
+	}
+	config.RateLimiter = v.limiters.For(provider)
+
+	adapter, err := cloud.New(provider)
+	if err != nil {
+		return err
 	}
 
 	if err := adapter.Initialize(ctx, config); err != nil {
-		return fmt.Errorf("failed to initialize %s adapter: %w", provider, err)
+		return fmt.Errorf("failed to initialize %s adapter: %w", describeAdapter(provider, key), err)
 	}
 
 	if err := adapter.ValidateCredentials(ctx); err != nil {
-		return fmt.Errorf("cloud credentials validation failed: %w", err)
+		_ = adapter.Close()
+		return fmt.Errorf("cloud credentials validation failed for %s: %w", describeAdapter(provider, key), err)
 	}
 
-	v.cloudAdapter = adapter
+	if v.adapters == nil {
+		v.adapters = make(map[string]cloud.Adapter)
+	}
+	v.adapters[key] = adapter
 	return nil
 }
 
-func (v *Validator) validateResources(ctx context.Context, plan *terraform.PlanOutput) error {
-	if plan.PlannedValues == nil || plan.PlannedValues.RootModule == nil {
-		return fmt.Errorf("no resources found in plan")
+// closeCloudAdapters closes every adapter, returning the first error
+func (v *Validator) closeCloudAdapters() error {
+	var firstErr error
+	for key, adapter := range v.adapters {
+		if err := adapter.Close(); err != nil && firstErr == nil {
+			firstErr = fmt.Errorf("%s: %w", describeAdapter(adapter.Name(), key), err)
+		}
+	}
+	v.adapters = nil
+	return firstErr
+}
+
+// providerKey returns the provider configuration a resource belongs to: its
+// provider config key (e.g. "aws.west") when the plan has configuration, or
+// else its provider source address
+func (v *Validator) providerKey(resource terraform.Resource) string {
+	if key, ok := v.resourceProviders[terraform.ConfigAddress(resource.Address)]; ok {
+		return key
+	}
+	return cloud.NormalizeSource(resource.ProviderName)
+}
+
+// adapterFor returns the adapter that checks a resource for drift, or nil
+// when its provider has no adapter
+func (v *Validator) adapterFor(resource terraform.Resource) cloud.Adapter {
+	if adapter, ok := v.adapters[v.providerKey(resource)]; ok {
+		return adapter
+	}
+	return v.adapters[defaultAdapter]
+}
+
+// describeAdapter names an adapter in errors, e.g. "aws (aws.west)"
+func describeAdapter(provider cloud.Provider, key string) string {
+	if key == defaultAdapter || key == string(provider) {
+		return string(provider)
+	}
+	return fmt.Sprintf("%s (%s)", provider, key)
+}
+
+func (v *Validator) validateResources(ctx context.Context, values *terraform.StateValues) error {
+	if values == nil || values.RootModule == nil {
+		return fmt.Errorf("no resources found")
 	}
 
 	// Collect all resources from root and child modules
-	resources := v.collectResources(plan.PlannedValues.RootModule)
+	resources := v.collectResources(values.RootModule)
 
-	for _, resource := range resources {
-		report := v.validateResource(ctx, resource)
-		v.results = append(v.results, report)
+	// Evaluate resources on a bounded worker pool. Each worker writes to the
+	// slot of its resource so reports keep the plan order.
+	reports := make([]ValidationReport, len(resources))
+	jobs := make(chan int)
+
+	workers := v.config.Parallelism
+	if workers <= 0 {
+		workers = DefaultParallelism
+	}
+	if workers > len(resources) {
+		workers = len(resources)
+	}
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				reports[i] = v.validateResource(ctx, resources[i])
+			}
+		}()
 	}
 
+	var err error
+feed:
+	for i := range resources {
+		select {
+		case jobs <- i:
+		case <-ctx.Done():
+			err = ctx.Err()
+			break feed
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	if err != nil {
+		return err
+	}
+
+	v.results = append(v.results, reports...)
 	return nil
 }
 
@@ -230,41 +595,125 @@ func (v *Validator) validateResource(ctx context.Context, resource terraform.Res
 	applicableRules := v.rulesEngine.GetRulesForResource(resource.Type)
 
 	// Evaluate each rule
+	env := v.evalContext(resource)
 	for _, rule := range applicableRules {
-		result := v.rulesEngine.EvaluateRule(rule, resource.Values)
-		result.ResourceID = resource.Address
-		report.RuleResults = append(report.RuleResults, result)
-
-		if !result.Passed {
-			if result.Severity == "error" {
-				report.Status = "fail"
-			} else if result.Severity == "warning" && report.Status != "fail" {
-				report.Status = "warning"
-			}
-		}
+		result := v.rulesEngine.EvaluateRuleWithContext(rule, resource.Values, env)
+		v.recordResult(&report, result)
 	}
 
-	// Check for drift if in validate-existing mode
-	if v.config.Mode == ModeValidateExisting && v.cloudAdapter != nil {
+	// Evaluate per-resource Rego policies
+	regoResults, err := v.rulesEngine.EvaluateRegoResource(ctx, resource)
+	if err != nil {
+		report.Errors = append(report.Errors, fmt.Sprintf("Rego evaluation failed: %s", err))
+	}
+	for _, result := range regoResults {
+		v.recordResult(&report, result)
+	}
+
+	// Check the deployed resource for drift and against the rules if in
+	// validate-existing mode
+	adapter := v.adapterFor(resource)
+	if v.config.Mode == ModeValidateExisting && adapter != nil {
 		resourceID := v.extractResourceID(resource)
 		if resourceID != "" {
-			driftStatus, err := v.cloudAdapter.DetectDrift(ctx, resource.Values, resource.Type, resourceID)
-			if err != nil {
-				report.Errors = append(report.Errors, fmt.Sprintf("Drift detection failed: %s", err))
-			} else {
-				report.DriftStatus = driftStatus
-				if driftStatus.DriftDetected {
-					if report.Status == "pass" {
-						report.Status = "warning"
-					}
-				}
-			}
+			v.checkDeployedResource(ctx, &report, adapter, resource.Type, resourceID, resource.Values, applicableRules)
 		}
 	}
 
 	return report
 }
 
+// checkDeployedResource reads a deployed resource once to evaluate the rules
+// against its live configuration and compare it with the planned values for
+// drift
+func (v *Validator) checkDeployedResource(ctx context.Context, report *ValidationReport, adapter cloud.Adapter, resourceType, resourceID string, planned map[string]interface{}, applicableRules []cloud.ValidationRule) {
+	driftStatus, liveResults, err := adapter.CheckResource(ctx, planned, resourceType, resourceID, applicableRules)
+	if err != nil {
+		report.Errors = append(report.Errors, fmt.Sprintf("Drift detection failed: %s", err))
+		return
+	}
+	for _, result := range liveResults {
+		v.recordResult(report, result)
+	}
+
+	// Policies can ignore drift of noisy attributes
+	driftStatus.IgnoreDrift(func(attribute string) bool {
+		return v.rulesEngine.DriftIgnored(resourceType, attribute)
+	})
+	report.DriftStatus = driftStatus
+	if driftStatus.DriftDetected {
+		if report.Status == "pass" {
+			report.Status = "warning"
+		}
+	}
+}
+
+// recordResult applies waivers and the baseline to a rule result and adds it
+// to the report. Results without a source were evaluated against the planned
+// values.
+func (v *Validator) recordResult(report *ValidationReport, result cloud.ValidationResult) {
+	if result.Source == "" {
+		result.Source = cloud.SourcePlanned
+	}
+	v.waivers.Apply(report.ResourceAddress, &result)
+	v.baseline.Apply(report.ResourceAddress, &result)
+	addRuleResult(report, result)
+}
+
+// addRuleResult records a rule result on the report and updates its status.
+// Waived and baseline results are recorded without affecting the status.
+func addRuleResult(report *ValidationReport, result cloud.ValidationResult) {
+	result.ResourceID = report.ResourceAddress
+	report.RuleResults = append(report.RuleResults, result)
+
+	if !result.Passed && !result.Waived && !result.Baselined {
+		if result.Severity == "error" {
+			report.Status = "fail"
+		} else if result.Severity == "warning" && report.Status != "fail" {
+			report.Status = "warning"
+		}
+	}
+}
+
+// validateDocument evaluates plan-level Rego policies against the whole plan or
+// state document. Findings are attached to the resource they name; the rest are
+// collected on a report for the document itself.
+func (v *Validator) validateDocument(ctx context.Context, document interface{}) error {
+	findings, err := v.rulesEngine.EvaluateRegoPlan(ctx, document)
+	if err != nil {
+		return err
+	}
+
+	for _, finding := range findings {
+		index := -1
+		for i := range v.results {
+			if v.results[i].ResourceAddress == finding.Address {
+				index = i
+				break
+			}
+		}
+
+		if index < 0 {
+			address := finding.Address
+			if address == "" {
+				address = "plan"
+			}
+			v.results = append(v.results, ValidationReport{
+				ResourceAddress: address,
+				ResourceType:    "terraform_plan",
+				Status:          "pass",
+				RuleResults:     make([]cloud.ValidationResult, 0),
+				Errors:          make([]string, 0),
+			})
+			index = len(v.results) - 1
+		}
+
+		v.recordResult(&v.results[index], finding.Result)
+	}
+
+	return nil
+}
+
 func (v *Validator) extractResourceID(resource terraform.Resource) string {
 	// Try to extract resource ID from values
 	if id, ok := resource.Values["id"].(string); ok && id != "" {
@@ -279,10 +728,18 @@ func (v *Validator) extractResourceID(resource terraform.Resource) string {
 	return ""
 }
 
-func (v *Validator) runEphemeralMode(ctx context.Context, planFile string) error {
+// runEphemeralMode applies the plan, verifies the created resources against
+// the cloud and destroys them again
+func (v *Validator) runEphemeralMode(ctx context.Context, plan *terraform.PlanOutput, planFile string) error {
 	// Apply the plan
 	applyErr := v.tfClient.Apply(ctx, planFile)
 
+	// Verify what was created before it is destroyed
+	var verifyErr error
+	if applyErr == nil {
+		verifyErr = v.verifyCreatedResources(ctx, plan)
+	}
+
 	// Always attempt destroy unless --no-destroy flag is set, even if apply failed
 	// This ensures cleanup happens to prevent resource leaks
 	if !v.config.NoDestroy {
@@ -298,10 +755,76 @@ func (v *Validator) runEphemeralMode(ctx context.Context, planFile string) error
 	if applyErr != nil {
 		return fmt.Errorf("terraform apply failed: %w", applyErr)
 	}
+	if verifyErr != nil {
+		return fmt.Errorf("post-apply verification failed: %w", verifyErr)
+	}
+
+	return nil
+}
+
+// verifyCreatedResources re-reads the state after apply and checks every
+// resource the plan created: the deployed resource is evaluated against the
+// rules and compared with its planned values for drift, and the results are
+// added to the resource's report. Resources of providers without an adapter
+// are not checked.
+func (v *Validator) verifyCreatedResources(ctx context.Context, plan *terraform.PlanOutput) error {
+	state, err := v.tfClient.ShowState(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to read state: %w", err)
+	}
+
+	// A state without values has no resources, so every created resource
+	// is reported missing
+	deployed := make(map[string]terraform.Resource)
+	if state.Values != nil && state.Values.RootModule != nil {
+		for _, resource := range v.collectResources(state.Values.RootModule) {
+			deployed[resource.Address] = resource
+		}
+	}
+
+	planned := make(map[string]terraform.Resource)
+	if plan.PlannedValues != nil && plan.PlannedValues.RootModule != nil {
+		for _, resource := range v.collectResources(plan.PlannedValues.RootModule) {
+			planned[resource.Address] = resource
+		}
+	}
+
+	for i := range v.results {
+		report := &v.results[i]
+		resource, ok := planned[report.ResourceAddress]
+		if !ok || resource.Mode == "data" || !created(v.resourceActions[resource.Address]) {
+			continue
+		}
+		adapter := v.adapterFor(resource)
+		if adapter == nil {
+			continue
+		}
+
+		// Created resources are identified by the IDs the apply assigned
+		resourceID := v.extractResourceID(deployed[resource.Address])
+		if resourceID == "" {
+			report.Errors = append(report.Errors, "Post-apply verification failed: resource not found in state after apply")
+			continue
+		}
+
+		applicableRules := v.rulesEngine.GetRulesForResource(resource.Type)
+		v.checkDeployedResource(ctx, report, adapter, resource.Type, resourceID, resource.Values, applicableRules)
+	}
 
 	return nil
 }
 
+// created reports whether planned actions create a resource, including
+// replacements
+func created(actions []string) bool {
+	for _, action := range actions {
+		if action == "create" {
+			return true
+		}
+	}
+	return false
+}
+
 func (v *Validator) generateSummary() *Summary {
 	summary := &Summary{
 		TotalResources: len(v.results),
@@ -323,20 +846,36 @@ func (v *Validator) generateSummary() *Summary {
 		if report.DriftStatus != nil && report.DriftStatus.DriftDetected {
 			summary.DriftDetected++
 		}
+
+		for _, result := range report.RuleResults {
+			if result.Waived {
+				summary.WaivedFindings++
+			}
+			if result.Baselined {
+				summary.BaselineFindings++
+			}
+		}
 	}
 
 	return summary
 }
 
-// Cloud adapter factory functions
-func newAWSAdapter() cloud.Adapter {
-	return awsadapter.NewAdapter()
-}
-
-func newAzureAdapter() cloud.Adapter {
-	return azureadapter.NewAdapter()
-}
-
-func newGCPAdapter() cloud.Adapter {
-	return gcpadapter.NewAdapter()
+// Baseline creates a baseline from the failed findings of the run. Waived
+// findings are left out since they do not fail the run.
+func (s *Summary) Baseline() *baseline.Baseline {
+	var findings []baseline.Finding
+	for _, report := range s.Reports {
+		for _, result := range report.RuleResults {
+			if result.Passed || result.Waived {
+				continue
+			}
+			findings = append(findings, baseline.Finding{
+				Resource: report.ResourceAddress,
+				Rule:     result.RuleName,
+				Severity: result.Severity,
+				Message:  result.Message,
+			})
+		}
+	}
+	return baseline.New(findings)
 }
@@ -0,0 +1,349 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vijayaxai/terraship/internal/cloud/fake"
+	"github.com/vijayaxai/terraship/internal/terraform"
+	"gopkg.in/yaml.v3"
+)
+
+const testPolicy = `version: "1.0"
+name: "Test"
+rules:
+  - name: required-tags
+    severity: error
+    enabled: true
+    conditions:
+      tags.required: ["Owner"]
+`
+
+// writeFile writes content, marshalled to JSON or YAML unless it is a
+// string, to a file of dir
+func writeFile(t *testing.T, dir, name string, content interface{}) string {
+	t.Helper()
+
+	var data []byte
+	var err error
+	switch content := content.(type) {
+	case string:
+		data = []byte(content)
+	default:
+		if filepath.Ext(name) == ".json" {
+			data, err = json.Marshal(content)
+		} else {
+			data, err = yaml.Marshal(content)
+		}
+		require.NoError(t, err)
+	}
+
+	path := filepath.Join(dir, name)
+	require.NoError(t, os.WriteFile(path, data, 0644))
+	return path
+}
+
+// bucketPlan plans n buckets and seeds a fixture in which every third bucket
+// is missing and every fifth has drifted
+func bucketPlan(n int) (*terraform.PlanOutput, fake.Fixture) {
+	module := &terraform.Module{}
+	var fixture fake.Fixture
+	for i := 0; i < n; i++ {
+		name := fmt.Sprintf("bucket-%03d", i)
+		tags := map[string]interface{}{"Environment": "production"}
+		if i%2 == 0 {
+			tags["Owner"] = "platform"
+		}
+		module.Resources = append(module.Resources, terraform.Resource{
+			Address:      "aws_s3_bucket." + name,
+			Mode:         "managed",
+			Type:         "aws_s3_bucket",
+			Name:         name,
+			ProviderName: "registry.terraform.io/hashicorp/aws",
+			Values:       map[string]interface{}{"id": name, "bucket": name, "tags": tags},
+		})
+
+		if i%3 == 0 {
+			continue
+		}
+		resource := fake.Resource{Type: "aws_s3_bucket", ID: name, Tags: map[string]string{"Environment": "production"}}
+		if i%5 == 0 {
+			resource.Tags["Environment"] = "staging"
+		}
+		if i%2 == 0 {
+			resource.Tags["Owner"] = "platform"
+		}
+		fixture.Resources = append(fixture.Resources, resource)
+	}
+
+	plan := &terraform.PlanOutput{
+		FormatVersion:    "1.2",
+		TerraformVersion: "1.6.0",
+		PlannedValues:    &terraform.StateValues{RootModule: module},
+	}
+	return plan, fixture
+}
+
+func TestValidate_ReportsKeepPlanOrder(t *testing.T) {
+	dir := t.TempDir()
+	plan, fixture := bucketPlan(200)
+	config := ValidatorConfig{
+		Mode:          ModeValidateExisting,
+		WorkingDir:    dir,
+		PolicyPaths:   []string{writeFile(t, dir, "policy.yml", testPolicy)},
+		CloudProvider: string(fake.Name),
+		PlanJSONPath:  writeFile(t, dir, "plan.json", plan),
+		RateLimit:     -1,
+	}
+	config.Cloud.FakeFixture = writeFile(t, dir, "fixture.yaml", fixture)
+
+	validate := func(parallelism int) *Summary {
+		config.Parallelism = parallelism
+		validator, err := NewValidator(config)
+		require.NoError(t, err)
+		summary, err := validator.Validate(context.Background())
+		require.NoError(t, err)
+		return summary
+	}
+
+	sequential := validate(1)
+	require.Len(t, sequential.Reports, 200)
+	for i, report := range sequential.Reports {
+		assert.Equal(t, plan.PlannedValues.RootModule.Resources[i].Address, report.ResourceAddress)
+	}
+	assert.Greater(t, sequential.FailedResources, 0)
+	assert.Greater(t, sequential.DriftDetected, 0)
+
+	for run := 0; run < 5; run++ {
+		assert.Equal(t, sequential, validate(16), "run %d", run)
+	}
+}
+
+// stubTerraform stands in for the terraform binary: it plans plan, reports
+// state after apply and records the commands run
+type stubTerraform struct {
+	plan      *terraform.PlanOutput
+	state     *terraform.StateOutput
+	stateErr  error
+	onDestroy func()
+	commands  []string
+}
+
+func (s *stubTerraform) Init(ctx context.Context, upgrade bool) error {
+	s.commands = append(s.commands, "init")
+	return nil
+}
+
+func (s *stubTerraform) Validate(ctx context.Context) error {
+	s.commands = append(s.commands, "validate")
+	return nil
+}
+
+func (s *stubTerraform) Plan(ctx context.Context, planFile string) error {
+	s.commands = append(s.commands, "plan")
+	return nil
+}
+
+func (s *stubTerraform) ShowJSON(ctx context.Context, planFile string) (*terraform.PlanOutput, error) {
+	s.commands = append(s.commands, "show plan")
+	return s.plan, nil
+}
+
+func (s *stubTerraform) ShowState(ctx context.Context) (*terraform.StateOutput, error) {
+	s.commands = append(s.commands, "show state")
+	return s.state, s.stateErr
+}
+
+func (s *stubTerraform) Apply(ctx context.Context, planFile string) error {
+	s.commands = append(s.commands, "apply")
+	return nil
+}
+
+func (s *stubTerraform) Destroy(ctx context.Context, autoApprove bool) error {
+	s.commands = append(s.commands, "destroy")
+	if s.onDestroy != nil {
+		s.onDestroy()
+	}
+	return nil
+}
+
+func (s *stubTerraform) GetProvider(ctx context.Context) (string, error) {
+	return "", errors.New("no provider")
+}
+
+// bucket is a planned or deployed bucket resource
+func bucket(name string, values map[string]interface{}) terraform.Resource {
+	return terraform.Resource{
+		Address:      "aws_s3_bucket." + name,
+		Mode:         "managed",
+		Type:         "aws_s3_bucket",
+		Name:         name,
+		ProviderName: "registry.terraform.io/hashicorp/aws",
+		Values:       values,
+	}
+}
+
+// ephemeralStub plans three buckets: "created" is created and deployed,
+// "missing" is created but absent from the state after apply, and "existing"
+// is deployed already and left unchanged
+func ephemeralStub() *stubTerraform {
+	tags := map[string]interface{}{"Owner": "platform", "Environment": "production"}
+	plan := &terraform.PlanOutput{
+		FormatVersion: "1.2",
+		PlannedValues: &terraform.StateValues{RootModule: &terraform.Module{Resources: []terraform.Resource{
+			// Created resources have no ID until they are applied
+			bucket("created", map[string]interface{}{"bucket": "created-bucket", "tags": tags}),
+			bucket("missing", map[string]interface{}{"bucket": "missing-bucket", "tags": tags}),
+			bucket("existing", map[string]interface{}{"id": "existing-bucket", "bucket": "existing-bucket", "tags": tags}),
+		}}},
+	}
+	for _, action := range []struct{ name, action string }{
+		{"created", "create"},
+		{"missing", "create"},
+		{"existing", "no-op"},
+	} {
+		plan.ResourceChanges = append(plan.ResourceChanges, terraform.ResourceChange{
+			Address: "aws_s3_bucket." + action.name,
+			Mode:    "managed",
+			Type:    "aws_s3_bucket",
+			Name:    action.name,
+			Change:  &terraform.Change{Actions: []string{action.action}},
+		})
+	}
+
+	state := &terraform.StateOutput{
+		FormatVersion: "1.0",
+		Values: &terraform.StateValues{RootModule: &terraform.Module{Resources: []terraform.Resource{
+			bucket("created", map[string]interface{}{"id": "created-bucket", "bucket": "created-bucket", "tags": tags}),
+			bucket("existing", map[string]interface{}{"id": "existing-bucket", "bucket": "existing-bucket", "tags": tags}),
+		}}},
+	}
+
+	return &stubTerraform{plan: plan, state: state}
+}
+
+// ephemeralValidator runs the ephemeral workflow with tf in place of the
+// terraform binary, against a fake cloud serving the deployed buckets. The
+// created bucket's Environment tag differs from the planned one.
+func ephemeralValidator(t *testing.T, tf *stubTerraform) *Validator {
+	dir := t.TempDir()
+	fixture := fake.Fixture{Resources: []fake.Resource{
+		{Type: "aws_s3_bucket", ID: "created-bucket", Tags: map[string]string{"Owner": "platform", "Environment": "staging"}},
+		{Type: "aws_s3_bucket", ID: "existing-bucket", Tags: map[string]string{"Owner": "platform", "Environment": "staging"}},
+	}}
+
+	// Created for a plan file, so that no terraform binary is looked up
+	config := ValidatorConfig{
+		Mode:          ModeValidateExisting,
+		WorkingDir:    dir,
+		PolicyPaths:   []string{writeFile(t, dir, "policy.yml", testPolicy)},
+		CloudProvider: string(fake.Name),
+		PlanJSONPath:  filepath.Join(dir, "plan.json"),
+		RateLimit:     -1,
+	}
+	config.Cloud.FakeFixture = writeFile(t, dir, "fixture.yaml", fixture)
+
+	validator, err := NewValidator(config)
+	require.NoError(t, err)
+	validator.config.Mode = ModeEphemeralSandbox
+	validator.config.PlanJSONPath = ""
+	validator.tfClient = tf
+	return validator
+}
+
+// reportFor returns the report of a resource address
+func reportFor(t *testing.T, summary *Summary, address string) ValidationReport {
+	t.Helper()
+	for _, report := range summary.Reports {
+		if report.ResourceAddress == address {
+			return report
+		}
+	}
+	require.Failf(t, "no report", "no report for %s", address)
+	return ValidationReport{}
+}
+
+func TestValidate_EphemeralVerifiesBeforeDestroy(t *testing.T) {
+	tf := ephemeralStub()
+	validator := ephemeralValidator(t, tf)
+
+	// Destroy removes the bucket from the cloud, so it is only found if it
+	// was verified before
+	tf.onDestroy = func() {
+		validator.adapters[defaultAdapter].(*fake.Adapter).Remove("aws_s3_bucket", "created-bucket")
+	}
+
+	summary, err := validator.Validate(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"init", "validate", "plan", "show plan", "apply", "show state", "destroy"}, tf.commands)
+
+	report := reportFor(t, summary, "aws_s3_bucket.created")
+	require.NotNil(t, report.DriftStatus)
+	assert.Equal(t, "created-bucket", report.DriftStatus.ResourceID, "identified by the ID in the state")
+	assert.True(t, report.DriftStatus.Exists)
+	assert.True(t, report.DriftStatus.DriftDetected)
+	assert.Equal(t, "warning", report.Status)
+	assert.Empty(t, report.Errors)
+}
+
+func TestValidate_EphemeralReportsResourcesMissingFromState(t *testing.T) {
+	summary, err := ephemeralValidator(t, ephemeralStub()).Validate(context.Background())
+	require.NoError(t, err)
+
+	report := reportFor(t, summary, "aws_s3_bucket.missing")
+	assert.Nil(t, report.DriftStatus)
+	assert.Equal(t, []string{"Post-apply verification failed: resource not found in state after apply"}, report.Errors)
+}
+
+func TestValidate_EphemeralSkipsResourcesNotCreated(t *testing.T) {
+	summary, err := ephemeralValidator(t, ephemeralStub()).Validate(context.Background())
+	require.NoError(t, err)
+
+	// The existing bucket has drifted too, but ephemeral runs only verify
+	// what they created
+	report := reportFor(t, summary, "aws_s3_bucket.existing")
+	assert.Nil(t, report.DriftStatus)
+	assert.Empty(t, report.Errors)
+	assert.Equal(t, "pass", report.Status)
+}
+
+func TestValidate_EphemeralDestroysWhenVerificationFails(t *testing.T) {
+	tf := ephemeralStub()
+	tf.stateErr = errors.New("state lock held")
+
+	_, err := ephemeralValidator(t, tf).Validate(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "post-apply verification failed")
+	assert.Contains(t, err.Error(), "state lock held")
+	assert.Equal(t, []string{"init", "validate", "plan", "show plan", "apply", "show state", "destroy"}, tf.commands)
+}
+
+func TestValidate_EphemeralStateWithoutResources(t *testing.T) {
+	for name, values := range map[string]*terraform.StateValues{
+		"no values":      nil,
+		"no root module": {},
+	} {
+		t.Run(name, func(t *testing.T) {
+			tf := ephemeralStub()
+			tf.state.Values = values
+
+			summary, err := ephemeralValidator(t, tf).Validate(context.Background())
+			require.NoError(t, err)
+			assert.Equal(t, []string{"init", "validate", "plan", "show plan", "apply", "show state", "destroy"}, tf.commands)
+
+			for _, address := range []string{"aws_s3_bucket.created", "aws_s3_bucket.missing"} {
+				report := reportFor(t, summary, address)
+				assert.Nil(t, report.DriftStatus, address)
+				assert.Equal(t, []string{"Post-apply verification failed: resource not found in state after apply"}, report.Errors, address)
+			}
+			assert.Empty(t, reportFor(t, summary, "aws_s3_bucket.existing").Errors)
+		})
+	}
+}
@@ -0,0 +1,146 @@
+// Package discovery finds cloud resources that exist in an account but are not
+// managed by Terraform.
+package discovery
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/vijayaxai/terraship/internal/cloud"
+)
+
+// Scope is a cloud adapter and the resource types to list through it: the
+// types of the plan or state resources it checks
+type Scope struct {
+	Name    string // describes the adapter, e.g. "aws (aws.west)"
+	Adapter cloud.Adapter
+	Types   []string
+}
+
+// Managed is the set of resource IDs Terraform manages, by resource type
+type Managed map[string]map[string]bool
+
+// Add records the IDs of a managed resource; a resource can be known by more
+// than one, e.g. its id and name
+func (m Managed) Add(resourceType string, ids ...string) {
+	for _, id := range ids {
+		if id == "" {
+			continue
+		}
+		if m[resourceType] == nil {
+			m[resourceType] = make(map[string]bool)
+		}
+		m[resourceType][id] = true
+	}
+}
+
+// contains reports whether Terraform manages a resource listed by an adapter.
+// Azure resource IDs are case-insensitive, so they are compared folded.
+func (m Managed) contains(provider cloud.Provider, resourceType, id string) bool {
+	if m[resourceType][id] {
+		return true
+	}
+	if provider != cloud.ProviderAzure {
+		return false
+	}
+	for managed := range m[resourceType] {
+		if strings.EqualFold(managed, id) {
+			return true
+		}
+	}
+	return false
+}
+
+// Resource is a live resource that is not under Terraform control
+type Resource struct {
+	Type  string `json:"type"`
+	ID    string `json:"id"`
+	Scope string `json:"scope"`
+}
+
+// TypeResult is the outcome of listing one resource type in a scope
+type TypeResult struct {
+	Type      string `json:"type"`
+	Scope     string `json:"scope"`
+	Live      int    `json:"live"`
+	Managed   int    `json:"managed"`
+	Unmanaged int    `json:"unmanaged"`
+	Error     string `json:"error,omitempty"` // why the type could not be listed
+}
+
+// Report is the result of a discovery run
+type Report struct {
+	Timestamp      string       `json:"timestamp"`
+	LiveResources  int          `json:"live_resources"`
+	UnmanagedCount int          `json:"unmanaged_resources"`
+	SkippedTypes   int          `json:"skipped_types"`
+	Types          []TypeResult `json:"types"`
+	Unmanaged      []Resource   `json:"unmanaged"`
+}
+
+// Discover lists the live resources of every scope and reports those whose
+// IDs are not managed. Types an adapter cannot list are reported as skipped
+// rather than failing the run.
+func Discover(ctx context.Context, scopes []Scope, managed Managed) (*Report, error) {
+	report := &Report{
+		Timestamp: time.Now().Format("2006-01-02 15:04:05"),
+		Types:     make([]TypeResult, 0),
+		Unmanaged: make([]Resource, 0),
+	}
+
+	for _, scope := range scopes {
+		provider := scope.Adapter.Name()
+		for _, resourceType := range sortedTypes(scope.Types) {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+
+			result := TypeResult{Type: resourceType, Scope: scope.Name}
+			ids, err := scope.Adapter.ListResources(ctx, resourceType)
+			if err != nil {
+				result.Error = err.Error()
+				report.SkippedTypes++
+				report.Types = append(report.Types, result)
+				continue
+			}
+
+			sort.Strings(ids)
+			for _, id := range ids {
+				if managed.contains(provider, resourceType, id) {
+					result.Managed++
+					continue
+				}
+				result.Unmanaged++
+				report.Unmanaged = append(report.Unmanaged, Resource{Type: resourceType, ID: id, Scope: scope.Name})
+			}
+			result.Live = len(ids)
+			report.LiveResources += result.Live
+			report.UnmanagedCount += result.Unmanaged
+			report.Types = append(report.Types, result)
+		}
+	}
+
+	return report, nil
+}
+
+// sortedTypes returns the distinct resource types in order
+func sortedTypes(types []string) []string {
+	seen := make(map[string]bool, len(types))
+	var result []string
+	for _, resourceType := range types {
+		if !seen[resourceType] {
+			seen[resourceType] = true
+			result = append(result, resourceType)
+		}
+	}
+	sort.Strings(result)
+	return result
+}
+
+// ToJSON renders the report as indented JSON
+func (r *Report) ToJSON() ([]byte, error) {
+	return json.MarshalIndent(r, "", "  ")
+}
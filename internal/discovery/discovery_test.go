@@ -0,0 +1,100 @@
+package discovery
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vijayaxai/terraship/internal/cloud"
+	"github.com/vijayaxai/terraship/internal/cloud/fake"
+)
+
+// azureAdapter serves fake resources as the Azure adapter, which cannot list
+// storage accounts in these tests
+type azureAdapter struct {
+	*fake.Adapter
+}
+
+func (a azureAdapter) Name() cloud.Provider {
+	return cloud.ProviderAzure
+}
+
+func (a azureAdapter) ListResources(ctx context.Context, resourceType string) ([]string, error) {
+	if resourceType == "azurerm_storage_account" {
+		return nil, fmt.Errorf("listing not supported for resource type: %s", resourceType)
+	}
+	return a.Adapter.ListResources(ctx, resourceType)
+}
+
+func TestDiscover(t *testing.T) {
+	aws := fake.NewAdapter(
+		fake.Resource{Type: "aws_s3_bucket", ID: "app-logs"},
+		fake.Resource{Type: "aws_s3_bucket", ID: "legacy-exports"},
+		fake.Resource{Type: "aws_instance", ID: "i-0abc"},
+		fake.Resource{Type: "aws_instance", ID: "i-0def"},
+		fake.Resource{Type: "aws_iam_role", ID: "admin"}, // not a type of the plan
+	)
+	azure := azureAdapter{fake.NewAdapter(
+		fake.Resource{Type: "azurerm_resource_group", ID: "/subscriptions/sub/resourceGroups/RG"},
+		fake.Resource{Type: "azurerm_resource_group", ID: "/subscriptions/sub/resourceGroups/sandbox"},
+	)}
+
+	managed := Managed{}
+	managed.Add("aws_s3_bucket", "app-logs", "arn:aws:s3:::app-logs")
+	managed.Add("aws_instance", "i-0abc", "")
+	managed.Add("azurerm_resource_group", "/subscriptions/sub/resourceGroups/rg")
+
+	report, err := Discover(context.Background(), []Scope{
+		{Name: "fake", Adapter: aws, Types: []string{"aws_s3_bucket", "aws_instance", "aws_s3_bucket"}},
+		{Name: "azure", Adapter: azure, Types: []string{"azurerm_resource_group", "azurerm_storage_account"}},
+	}, managed)
+	require.NoError(t, err)
+
+	assert.Equal(t, []Resource{
+		{Type: "aws_instance", ID: "i-0def", Scope: "fake"},
+		{Type: "aws_s3_bucket", ID: "legacy-exports", Scope: "fake"},
+		{Type: "azurerm_resource_group", ID: "/subscriptions/sub/resourceGroups/sandbox", Scope: "azure"},
+	}, report.Unmanaged)
+	assert.Equal(t, 6, report.LiveResources)
+	assert.Equal(t, 3, report.UnmanagedCount)
+	assert.Equal(t, 1, report.SkippedTypes)
+
+	require.Len(t, report.Types, 4)
+	assert.Equal(t, TypeResult{Type: "aws_instance", Scope: "fake", Live: 2, Managed: 1, Unmanaged: 1}, report.Types[0])
+	assert.Equal(t, "azurerm_storage_account", report.Types[3].Type)
+	assert.Contains(t, report.Types[3].Error, "listing not supported")
+}
+
+func TestDiscover_CaseSensitiveIDs(t *testing.T) {
+	adapter := fake.NewAdapter(fake.Resource{Type: "aws_s3_bucket", ID: "App-Logs"})
+	managed := Managed{}
+	managed.Add("aws_s3_bucket", "app-logs")
+
+	report, err := Discover(context.Background(), []Scope{{Name: "fake", Adapter: adapter, Types: []string{"aws_s3_bucket"}}}, managed)
+	require.NoError(t, err)
+	assert.Equal(t, 1, report.UnmanagedCount)
+}
+
+func TestReport_Output(t *testing.T) {
+	report, err := Discover(context.Background(), []Scope{{
+		Name:    "fake",
+		Adapter: fake.NewAdapter(fake.Resource{Type: "aws_s3_bucket", ID: "<shadow>"}),
+		Types:   []string{"aws_s3_bucket"},
+	}}, Managed{})
+	require.NoError(t, err)
+
+	data, err := report.ToJSON()
+	require.NoError(t, err)
+	var decoded Report
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, report.Unmanaged, decoded.Unmanaged)
+
+	html, err := report.ToHTML()
+	require.NoError(t, err)
+	assert.Contains(t, html, "&lt;shadow&gt;")
+	assert.False(t, strings.Contains(html, "<shadow>"), "IDs are escaped")
+}
@@ -0,0 +1,93 @@
+package discovery
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+)
+
+// ToHTML renders the report as a standalone HTML page
+func (r *Report) ToHTML() (string, error) {
+	tmpl, err := template.New("discovery").Parse(htmlTemplate)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse template: %w", err)
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, r); err != nil {
+		return "", fmt.Errorf("failed to render template: %w", err)
+	}
+	return buf.String(), nil
+}
+
+const htmlTemplate = `<!DOCTYPE html>
+<html lang="en">
+<head>
+    <meta charset="UTF-8">
+    <meta name="viewport" content="width=device-width, initial-scale=1.0">
+    <title>Unmanaged Resources - Terraship Report</title>
+    <style>
+        :root { --primary: #667eea; --primary-dark: #764ba2; --success: #10b981; --danger: #ef4444; --warning: #f59e0b;
+            --bg: #ffffff; --bg-alt: #f8f9fa; --text: #333333; --text-light: #666666; --border: #e5e7eb; }
+        * { margin: 0; padding: 0; box-sizing: border-box; }
+        body { font-family: -apple-system, BlinkMacSystemFont, 'Segoe UI', Roboto, Oxygen, Ubuntu, sans-serif; background: var(--bg-alt); color: var(--text); }
+        header { background: linear-gradient(135deg, var(--primary) 0%, var(--primary-dark) 100%); color: white; padding: 32px 24px; }
+        header p { opacity: 0.85; margin-top: 6px; }
+        main { max-width: 1100px; margin: 0 auto; padding: 24px; }
+        .cards { display: grid; grid-template-columns: repeat(auto-fit, minmax(200px, 1fr)); gap: 16px; margin-bottom: 24px; }
+        .card { background: var(--bg); border: 1px solid var(--border); border-radius: 8px; padding: 16px; }
+        .card .value { font-size: 32px; font-weight: 700; }
+        .card .label { color: var(--text-light); font-size: 14px; }
+        .unmanaged .value { color: var(--danger); }
+        .skipped .value { color: var(--warning); }
+        section { background: var(--bg); border: 1px solid var(--border); border-radius: 8px; padding: 16px; margin-bottom: 24px; }
+        h2 { font-size: 18px; margin-bottom: 12px; }
+        table { width: 100%; border-collapse: collapse; font-size: 14px; }
+        th, td { text-align: left; padding: 8px; border-bottom: 1px solid var(--border); vertical-align: top; }
+        th { color: var(--text-light); font-weight: 600; }
+        td.id { font-family: SFMono-Regular, Consolas, monospace; word-break: break-all; }
+        td.error { color: var(--warning); }
+        .none { color: var(--success); }
+    </style>
+</head>
+<body>
+    <header>
+        <h1>Unmanaged Resources</h1>
+        <p>Live resources of the Terraform-managed types that are not under Terraform control &middot; {{.Timestamp}}</p>
+    </header>
+    <main>
+        <div class="cards">
+            <div class="card"><div class="value">{{.LiveResources}}</div><div class="label">Live resources</div></div>
+            <div class="card unmanaged"><div class="value">{{.UnmanagedCount}}</div><div class="label">Unmanaged</div></div>
+            <div class="card skipped"><div class="value">{{.SkippedTypes}}</div><div class="label">Types not listed</div></div>
+        </div>
+
+        <section>
+            <h2>Unmanaged resources</h2>
+            {{if .Unmanaged}}
+            <table>
+                <tr><th>Type</th><th>ID</th><th>Scope</th></tr>
+                {{range .Unmanaged}}<tr><td>{{.Type}}</td><td class="id">{{.ID}}</td><td>{{.Scope}}</td></tr>
+                {{end}}
+            </table>
+            {{else}}
+            <p class="none">Every listed resource is managed by Terraform.</p>
+            {{end}}
+        </section>
+
+        <section>
+            <h2>Resource types</h2>
+            <table>
+                <tr><th>Type</th><th>Scope</th><th>Live</th><th>Managed</th><th>Unmanaged</th></tr>
+                {{range .Types}}<tr>
+                    <td>{{.Type}}</td><td>{{.Scope}}</td>
+                    {{if .Error}}<td class="error" colspan="3">Not listed: {{.Error}}</td>
+                    {{else}}<td>{{.Live}}</td><td>{{.Managed}}</td><td>{{.Unmanaged}}</td>{{end}}
+                </tr>
+                {{end}}
+            </table>
+        </section>
+    </main>
+</body>
+</html>
+`
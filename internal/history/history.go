@@ -0,0 +1,202 @@
+// Package history stores a summary of every validation run so compliance
+// trends can be reported across runs.
+package history
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// DefaultDir is the default location of the history store
+const DefaultDir = ".terraship/history"
+
+// Record is the summary of a single validation run
+type Record struct {
+	Timestamp         time.Time `json:"timestamp"`
+	Target            string    `json:"target"` // working directory, or state file for state scans
+	Policies          []string  `json:"policies"`
+	TotalResources    int       `json:"total_resources"`
+	PassedResources   int       `json:"passed_resources"`
+	FailedResources   int       `json:"failed_resources"`
+	WarningResources  int       `json:"warning_resources"`
+	ErrorResources    int       `json:"error_resources"`
+	Findings          int       `json:"findings"` // failed rule results
+	WaivedFindings    int       `json:"waived_findings"`
+	BaselineFindings  int       `json:"baseline_findings"`
+	CompliancePercent float64   `json:"compliance_percent"`
+}
+
+// Series is the run history of one target and policy combination
+type Series struct {
+	Key      string   `json:"key"`
+	Target   string   `json:"target"`
+	Policies []string `json:"policies"`
+	Runs     int      `json:"runs"`
+	Last     Record   `json:"last"`
+}
+
+// Store is a directory of run records, one subdirectory per series
+type Store struct {
+	dir string
+}
+
+// Open returns the store rooted at dir; it is created on the first append
+func Open(dir string) *Store {
+	if dir == "" {
+		dir = DefaultDir
+	}
+	return &Store{dir: dir}
+}
+
+// Key identifies the series of a target and policy combination
+func Key(target string, policies []string) string {
+	h := sha256.New()
+	h.Write([]byte(normalizePath(target)))
+	for _, policy := range normalizePolicies(policies) {
+		h.Write([]byte{0})
+		h.Write([]byte(policy))
+	}
+	return hex.EncodeToString(h.Sum(nil))[:16]
+}
+
+// Key returns the series key of the record
+func (r Record) Key() string {
+	return Key(r.Target, r.Policies)
+}
+
+// Append writes a run record to its series
+func (s *Store) Append(record Record) error {
+	if record.Timestamp.IsZero() {
+		record.Timestamp = time.Now().UTC()
+	}
+	record.Target = normalizePath(record.Target)
+	record.Policies = normalizePolicies(record.Policies)
+
+	dir := filepath.Join(s.dir, record.Key())
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create history directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(record, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode history record: %w", err)
+	}
+
+	name := record.Timestamp.UTC().Format("20060102T150405.000000000Z") + ".json"
+	if err := os.WriteFile(filepath.Join(dir, name), append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write history record: %w", err)
+	}
+	return nil
+}
+
+// List returns the most recent runs of a series, oldest first. A limit of
+// zero or less returns every run.
+func (s *Store) List(key string, limit int) ([]Record, error) {
+	files, err := filepath.Glob(filepath.Join(s.dir, key, "*.json"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list history records: %w", err)
+	}
+	sort.Strings(files)
+
+	if limit > 0 && len(files) > limit {
+		files = files[len(files)-limit:]
+	}
+
+	records := make([]Record, 0, len(files))
+	for _, file := range files {
+		record, err := readRecord(file)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, record)
+	}
+
+	sort.SliceStable(records, func(i, j int) bool {
+		return records[i].Timestamp.Before(records[j].Timestamp)
+	})
+	return records, nil
+}
+
+// Series lists every series in the store, most recently run first
+func (s *Store) Series() ([]Series, error) {
+	entries, err := os.ReadDir(s.dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read history directory: %w", err)
+	}
+
+	var series []Series
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+
+		records, err := s.List(entry.Name(), 0)
+		if err != nil {
+			return nil, err
+		}
+		if len(records) == 0 {
+			continue
+		}
+
+		last := records[len(records)-1]
+		series = append(series, Series{
+			Key:      entry.Name(),
+			Target:   last.Target,
+			Policies: last.Policies,
+			Runs:     len(records),
+			Last:     last,
+		})
+	}
+
+	sort.SliceStable(series, func(i, j int) bool {
+		return series[i].Last.Timestamp.After(series[j].Last.Timestamp)
+	})
+	return series, nil
+}
+
+func readRecord(path string) (Record, error) {
+	var record Record
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return record, fmt.Errorf("failed to read history record: %w", err)
+	}
+	if err := json.Unmarshal(data, &record); err != nil {
+		return record, fmt.Errorf("failed to parse history record %s: %w", path, err)
+	}
+	return record, nil
+}
+
+// normalizePath makes a path absolute and slash-separated so the same target
+// maps to the same series regardless of how it was spelled
+func normalizePath(path string) string {
+	if abs, err := filepath.Abs(path); err == nil {
+		path = abs
+	}
+	return filepath.ToSlash(filepath.Clean(path))
+}
+
+// normalizePolicies normalizes and sorts policy paths; the order policies are
+// given in does not change the series
+func normalizePolicies(policies []string) []string {
+	normalized := make([]string, 0, len(policies))
+	for _, policy := range policies {
+		policy = strings.TrimSpace(policy)
+		if policy == "" {
+			continue
+		}
+		normalized = append(normalized, normalizePath(policy))
+	}
+	sort.Strings(normalized)
+	return normalized
+}
@@ -0,0 +1,75 @@
+package history
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKey_NormalizesTargetAndPolicyOrder(t *testing.T) {
+	cwd, err := os.Getwd()
+	require.NoError(t, err)
+
+	key := Key(".", []string{"policies/a.yml", "policies/b.yml"})
+	assert.Equal(t, key, Key(cwd, []string{"./policies/b.yml", "policies/a.yml"}))
+	assert.NotEqual(t, key, Key(".", []string{"policies/a.yml"}))
+	assert.NotEqual(t, key, Key("other", []string{"policies/a.yml", "policies/b.yml"}))
+}
+
+func TestStore_AppendList(t *testing.T) {
+	store := Open(filepath.Join(t.TempDir(), "history"))
+	policies := []string{"policy.yml"}
+	start := time.Date(2026, 10, 1, 9, 0, 0, 0, time.UTC)
+
+	for i := 0; i < 3; i++ {
+		require.NoError(t, store.Append(Record{
+			Timestamp:       start.Add(time.Duration(i) * time.Hour),
+			Target:          "infra",
+			Policies:        policies,
+			TotalResources:  4,
+			PassedResources: i + 1,
+		}))
+	}
+	require.NoError(t, store.Append(Record{Timestamp: start, Target: "other", Policies: policies}))
+
+	records, err := store.List(Key("infra", policies), 0)
+	require.NoError(t, err)
+	require.Len(t, records, 3)
+	assert.Equal(t, 1, records[0].PassedResources)
+	assert.Equal(t, 3, records[2].PassedResources)
+	assert.True(t, filepath.IsAbs(records[0].Target))
+
+	recent, err := store.List(Key("infra", policies), 2)
+	require.NoError(t, err)
+	require.Len(t, recent, 2)
+	assert.Equal(t, 2, recent[0].PassedResources)
+
+	missing, err := store.List(Key("missing", policies), 0)
+	require.NoError(t, err)
+	assert.Empty(t, missing)
+}
+
+func TestStore_Series(t *testing.T) {
+	store := Open(filepath.Join(t.TempDir(), "history"))
+
+	series, err := store.Series()
+	require.NoError(t, err)
+	assert.Empty(t, series)
+
+	start := time.Date(2026, 10, 1, 9, 0, 0, 0, time.UTC)
+	require.NoError(t, store.Append(Record{Timestamp: start, Target: "a", Policies: []string{"p.yml"}}))
+	require.NoError(t, store.Append(Record{Timestamp: start.Add(time.Hour), Target: "a", Policies: []string{"p.yml"}}))
+	require.NoError(t, store.Append(Record{Timestamp: start.Add(2 * time.Hour), Target: "b", Policies: []string{"p.yml"}}))
+
+	series, err = store.Series()
+	require.NoError(t, err)
+	require.Len(t, series, 2)
+	assert.Equal(t, Key("b", []string{"p.yml"}), series[0].Key)
+	assert.Equal(t, 1, series[0].Runs)
+	assert.Equal(t, 2, series[1].Runs)
+	assert.Equal(t, start.Add(time.Hour), series[1].Last.Timestamp)
+}
@@ -7,6 +7,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/vijayaxai/terraship/internal/cloud"
 	"github.com/vijayaxai/terraship/internal/core"
 )
 
@@ -38,7 +39,9 @@ func (f *HumanFormatter) Format(summary *core.Summary) (string, error) {
 	sb.WriteString(fmt.Sprintf("  ✗ Failed:           %d\n", summary.FailedResources))
 	sb.WriteString(fmt.Sprintf("  ⚠ Warnings:         %d\n", summary.WarningResources))
 	sb.WriteString(fmt.Sprintf("  ⨯ Errors:           %d\n", summary.ErrorResources))
-	sb.WriteString(fmt.Sprintf("  ↔ Drift Detected:   %d\n\n", summary.DriftDetected))
+	sb.WriteString(fmt.Sprintf("  ↔ Drift Detected:   %d\n", summary.DriftDetected))
+	sb.WriteString(fmt.Sprintf("  ⊘ Waived Findings:  %d\n", summary.WaivedFindings))
+	sb.WriteString(fmt.Sprintf("  ≡ Baseline Findings: %d\n\n", summary.BaselineFindings))
 
 	// Overall status
 	if summary.FailedResources == 0 && summary.ErrorResources == 0 {
@@ -71,10 +74,24 @@ func (f *HumanFormatter) Format(summary *core.Summary) (string, error) {
 				sb.WriteString("  Policy Checks:\n")
 				for _, result := range report.RuleResults {
 					resultIcon := "✓"
-					if !result.Passed {
+					if result.Waived {
+						resultIcon = "⊘"
+					} else if result.Baselined {
+						resultIcon = "≡"
+					} else if !result.Passed {
 						resultIcon = "✗"
 					}
-					sb.WriteString(fmt.Sprintf("    %s %s [%s]\n", resultIcon, result.RuleName, result.Severity))
+					source := ""
+					if result.Source == cloud.SourceLive {
+						source = " (live)"
+					}
+					sb.WriteString(fmt.Sprintf("    %s %s [%s]%s\n", resultIcon, result.RuleName, result.Severity, source))
+					if result.Waived {
+						sb.WriteString(fmt.Sprintf("      Waived: %s\n", waiverDescription(result)))
+					}
+					if result.Baselined {
+						sb.WriteString("      Baseline: existing finding, does not fail the run\n")
+					}
 					if !result.Passed {
 						sb.WriteString(fmt.Sprintf("      Message: %s\n", result.Message))
 						for _, detail := range result.Details {
@@ -176,10 +193,21 @@ type SARIFDriver struct {
 
 // SARIFResult represents a single result
 type SARIFResult struct {
-	RuleID    string          `json:"ruleId"`
-	Level     string          `json:"level"` // "error", "warning", "note"
-	Message   SARIFMessage    `json:"message"`
-	Locations []SARIFLocation `json:"locations,omitempty"`
+	RuleID       string             `json:"ruleId"`
+	Level        string             `json:"level"` // "error", "warning", "note"
+	Message      SARIFMessage       `json:"message"`
+	Locations    []SARIFLocation    `json:"locations,omitempty"`
+	Suppressions []SARIFSuppression `json:"suppressions,omitempty"`
+	// BaselineState is "unchanged" for findings already recorded in the baseline
+	BaselineState string `json:"baselineState,omitempty"`
+	// Properties tell whether the finding is on the planned or the deployed resource
+	Properties map[string]string `json:"properties,omitempty"`
+}
+
+// SARIFSuppression marks a result as suppressed, e.g. by a waiver
+type SARIFSuppression struct {
+	Kind          string `json:"kind"` // "inSource" or "external"
+	Justification string `json:"justification,omitempty"`
 }
 
 // SARIFMessage represents a result message
@@ -253,6 +281,15 @@ func (f *SARIFFormatter) Format(summary *core.Summary) (string, error) {
 						},
 					},
 				}
+				if result.Waived {
+					sarifResult.Suppressions = []SARIFSuppression{{Kind: "external", Justification: waiverDescription(result)}}
+				}
+				if result.Baselined {
+					sarifResult.BaselineState = "unchanged"
+				}
+				if result.Source != "" {
+					sarifResult.Properties = map[string]string{"source": result.Source}
+				}
 
 				sarif.Runs[0].Results = append(sarif.Runs[0].Results, sarifResult)
 			}
@@ -266,3 +303,19 @@ func (f *SARIFFormatter) Format(summary *core.Summary) (string, error) {
 
 	return string(data), nil
 }
+
+// waiverDescription describes why a result is waived and until when
+func waiverDescription(result cloud.ValidationResult) string {
+	return describeWaiver(result.WaiverReason, result.WaiverExpires)
+}
+
+// describeWaiver formats a waiver reason and expiry date for display
+func describeWaiver(reason, expires string) string {
+	if reason == "" {
+		reason = "no reason given"
+	}
+	if expires != "" {
+		return fmt.Sprintf("%s (expires %s)", reason, expires)
+	}
+	return reason
+}
@@ -4,7 +4,6 @@ import (
 	"bytes"
 	"fmt"
 	"html/template"
-	"time"
 )
 
 // HtmlReportData holds all data needed to generate an HTML report
@@ -19,6 +18,7 @@ type HtmlReportData struct {
 	Resources          []ResourceReport
 	ValidationHistory  []HistoryPoint
 	PreviousRunStats   PreviousStats
+	Comparison         *ComparisonReport
 }
 
 // ResourceReport represents a single resource with its validation checks
@@ -40,14 +40,17 @@ type CheckReport struct {
 	Message     string
 	Details     []string
 	Remediation string
+	Waiver      string // reason and expiry when the check is waived
+	Live        bool   // evaluated against the deployed resource
 }
 
-// HistoryPoint represents a single point in the validation history
+// HistoryPoint represents a single run in the validation history
 type HistoryPoint struct {
-	Day      string
-	Passed   int
-	Failed   int
-	Warnings int
+	Label             string
+	Passed            int
+	Failed            int
+	Warnings          int
+	CompliancePercent float64
 }
 
 // PreviousStats holds stats from a previous run
@@ -113,7 +116,7 @@ func PrepareReportData(vr *ValidationResult, includeHistory bool, previousRun *V
 		//Count passed checks
 		passedCount := 0
 		for _, check := range res.Checks {
-			if !check.Failed && !check.Warning {
+			if !check.Failed && !check.Warning && !check.Waived && !check.Baselined {
 				passedCount++
 			}
 		}
@@ -124,57 +127,58 @@ func PrepareReportData(vr *ValidationResult, includeHistory bool, previousRun *V
 			checkStatus := "passed"
 			if check.Failed {
 				checkStatus = "failed"
+			} else if check.Waived {
+				checkStatus = "waived"
+			} else if check.Baselined {
+				checkStatus = "baselined"
 			} else if check.Warning {
 				checkStatus = "warning"
 			}
 			
-			resReport.Checks = append(resReport.Checks, CheckReport{
+			checkReport := CheckReport{
 				Name:        check.Name,
 				Status:      checkStatus,
 				Severity:    check.Severity,
 				Message:     check.Message,
 				Details:     check.Details,
 				Remediation: check.Remediation,
-			})
+				Live:        check.Live,
+			}
+			if check.Waived {
+				checkReport.Waiver = describeWaiver(check.WaiverReason, check.WaiverExpires)
+			}
+			resReport.Checks = append(resReport.Checks, checkReport)
 		}
 		
 		data.Resources = append(data.Resources, resReport)
 	}
 	
-	// History data (7 days)
+	// History data from the run history store
 	if includeHistory {
-		data.ValidationHistory = generateHistoryData()
+		data.ValidationHistory = vr.History
 	}
 	
 	// Previous run stats
 	if previousRun != nil {
 		data.PreviousRunStats = PreviousStats{
-			Date:              time.Now().AddDate(0, 0, -1).Format("January 2, 2006"),
+			Date:              previousRun.Timestamp,
 			TotalResources:    previousRun.TotalResources,
 			PassedResources:   previousRun.PassedResources,
 			FailedResources:   previousRun.FailedResources,
 			WarningResources:  previousRun.WarningResources,
 		}
+		if data.PreviousRunStats.Date == "" {
+			data.PreviousRunStats.Date = "Previous Run"
+		}
 		if previousRun.TotalResources > 0 {
 			data.PreviousRunStats.CompliancePercent = (float64(previousRun.PassedResources) / float64(previousRun.TotalResources)) * 100
 		}
+		data.Comparison = Compare(vr, previousRun)
 	}
 	
 	return data
 }
 
-func generateHistoryData() []HistoryPoint {
-	return []HistoryPoint{
-		{Day: "Mon", Passed: 8, Failed: 19, Warnings: 2},
-		{Day: "Tue", Passed: 8, Failed: 19, Warnings: 2},
-		{Day: "Wed", Passed: 9, Failed: 18, Warnings: 2},
-		{Day: "Thu", Passed: 9, Failed: 18, Warnings: 2},
-		{Day: "Fri", Passed: 10, Failed: 16, Warnings: 1},
-		{Day: "Sat", Passed: 10, Failed: 16, Warnings: 1},
-		{Day: "Sun", Passed: 10, Failed: 16, Warnings: 1},
-	}
-}
-
 // getHTMLTemplate returns the comprehensive HTML template with search, filters, and charts
 func getHTMLTemplate() string {
 	// Comprehensive template with all features
@@ -236,6 +240,8 @@ func getHTMLTemplate() string {
         .check.passed { border-left-color: var(--success); background: rgba(16, 185, 129, 0.05); }
         .check.failed { border-left-color: var(--danger); background: rgba(239, 68, 68, 0.05); }
         .check.warning { border-left-color: var(--warning); background: rgba(245, 158, 11, 0.05); }
+        .check.waived { border-left-color: var(--text-light); background: rgba(100, 116, 139, 0.05); }
+        .check.baselined { border-left-color: var(--text-light); background: rgba(100, 116, 139, 0.05); }
         .check-name { font-weight: 600; margin-bottom: 4px; }
         .check-details { color: var(--text-light); margin: 8px 0; }
         .remediation { margin-top: 8px; padding: 8px; background: var(--bg); border-left: 3px solid var(--primary); font-size: 12px; }
@@ -244,6 +250,7 @@ func getHTMLTemplate() string {
         .comparison-section h3 { margin-bottom: 16px; }
         .comparison-section > div { display: flex; justify-content: space-between; padding: 8px 0; border-bottom: 1px solid var(--border); }
         .comparison-section > div:last-child { border-bottom: none; }
+        .changes { margin-top: 20px; }
         .footer { padding: 20px; text-align: center; font-size: 12px; color: var(--text-light); border-top: 1px solid var(--border); background: var(--bg-alt); }
         .hidden { display: none !important; }
         @media (max-width: 768px) { .comparison { grid-template-columns: 1fr; } .controls { grid-template-columns: 1fr; } }
@@ -275,12 +282,13 @@ func getHTMLTemplate() string {
         </div>
         <div class="charts-section">
             <div class="chart-container"><div class="chart-title">📊 Resources Breakdown</div><canvas id="statusChart"></canvas></div>
-            <div class="chart-container"><div class="chart-title">📈 Timeline (Last 7 Days)</div><canvas id="timelineChart"></canvas></div>
+            {{if .ValidationHistory}}<div class="chart-container"><div class="chart-title">📈 Timeline (Last {{len .ValidationHistory}} Runs)</div><canvas id="timelineChart"></canvas></div>{{end}}
         </div>
         <div class="content">
             <div class="resources-header"><h3>Resources Details</h3><div class="result-count">Showing <span id="resultCount">{{.TotalResources}}</span> resources</div></div>
-            {{range .Resources}}<div class="resource" data-status="{{.Status}}" data-type="{{.Type}}"><div class="resource-header"><div class="resource-info"><div class="resource-name">{{with .Status}}{{if eq . "passed"}}✓{{else if eq . "failed"}}✗{{else}}⚠{{end}}{{end}} {{.Name}}</div><div class="resource-type">{{.Type}} • {{.Provider}} • {{.PassedCount}}/{{.CheckCount}} checks passed</div></div><div class="resource-status"><span class="status-badge {{.Status}}">{{with .Status}}{{if eq . "passed"}}Passed{{else if eq . "failed"}}Failed{{else}}Warning{{end}}{{end}}</span><div class="expand-icon">▼</div></div></div><div class="resource-body">{{range .Checks}}<div class="check {{.Status}}"><div class="check-name">{{with .Status}}{{if eq . "passed"}}✓{{else if eq . "failed"}}✗{{else}}⚠{{end}}{{end}} {{.Name}} <span style="font-size: 11px; opacity: 0.7;">[{{.Severity}}]</span></div>{{if .Message}}<div class="check-details">{{.Message}}</div>{{end}}{{if .Details}}<div class="check-details">{{range .Details}}• {{.}}<br>{{end}}</div>{{end}}{{if .Remediation}}<div class="remediation"><strong>💡 Remediation:</strong> {{.Remediation}}</div>{{end}}</div>{{end}}</div></div>{{end}}
+            {{range .Resources}}<div class="resource" data-status="{{.Status}}" data-type="{{.Type}}"><div class="resource-header"><div class="resource-info"><div class="resource-name">{{with .Status}}{{if eq . "passed"}}✓{{else if eq . "failed"}}✗{{else}}⚠{{end}}{{end}} {{.Name}}</div><div class="resource-type">{{.Type}} • {{.Provider}} • {{.PassedCount}}/{{.CheckCount}} checks passed</div></div><div class="resource-status"><span class="status-badge {{.Status}}">{{with .Status}}{{if eq . "passed"}}Passed{{else if eq . "failed"}}Failed{{else}}Warning{{end}}{{end}}</span><div class="expand-icon">▼</div></div></div><div class="resource-body">{{range .Checks}}<div class="check {{.Status}}"><div class="check-name">{{with .Status}}{{if eq . "passed"}}✓{{else if eq . "failed"}}✗{{else if eq . "waived"}}⊘{{else if eq . "baselined"}}≡{{else}}⚠{{end}}{{end}} {{.Name}} <span style="font-size: 11px; opacity: 0.7;">[{{.Severity}}]{{if .Live}} [live]{{end}}</span></div>{{if .Message}}<div class="check-details">{{.Message}}</div>{{end}}{{if .Waiver}}<div class="check-details"><strong>⊘ Waived:</strong> {{.Waiver}}</div>{{end}}{{if eq .Status "baselined"}}<div class="check-details"><strong>≡ Baseline:</strong> existing finding, does not fail the run</div>{{end}}{{if .Details}}<div class="check-details">{{range .Details}}• {{.}}<br>{{end}}</div>{{end}}{{if .Remediation}}<div class="remediation"><strong>💡 Remediation:</strong> {{.Remediation}}</div>{{end}}</div>{{end}}</div></div>{{end}}
             {{if ne .PreviousRunStats.Date ""}}<div class="comparison"><div class="comparison-section"><h3>📊 Current Run</h3><div><strong>Resources:</strong><span>{{.TotalResources}}</span></div><div><strong>Passed:</strong><span style="color: var(--success);">{{.PassedResources}} ✓</span></div><div><strong>Failed:</strong><span style="color: var(--danger);">{{.FailedResources}} ✗</span></div><div><strong>Warnings:</strong><span style="color: var(--warning);">{{.WarningResources}} ⚠</span></div><div><strong>Compliance:</strong><span>{{printf "%.1f" .CompliancePercent}}%</span></div></div><div class="comparison-section"><h3>📊 {{.PreviousRunStats.Date}}</h3><div><strong>Resources:</strong><span>{{.PreviousRunStats.TotalResources}}</span></div><div><strong>Passed:</strong><span style="color: var(--success);">{{.PreviousRunStats.PassedResources}} ✓</span></div><div><strong>Failed:</strong><span style="color: var(--danger);">{{.PreviousRunStats.FailedResources}} ✗</span></div><div><strong>Warnings:</strong><span style="color: var(--warning);">{{.PreviousRunStats.WarningResources}} ⚠</span></div><div><strong>Compliance:</strong><span>{{printf "%.1f" .PreviousRunStats.CompliancePercent}}%</span></div></div></div>{{end}}
+            {{with .Comparison}}<div class="comparison-section changes"><h3>🔀 Changes since {{.PreviousTimestamp}}</h3><div><strong>New:</strong><span style="color: var(--danger);">{{.NewFindings}}</span></div><div><strong>Fixed:</strong><span style="color: var(--success);">{{.FixedFindings}}</span></div><div><strong>Regressed:</strong><span style="color: var(--danger);">{{.RegressedFindings}}</span></div><div><strong>Unchanged:</strong><span>{{.UnchangedFindings}}</span></div>{{range .ChangedResources}}<div><strong>{{.ResourceName}}</strong><span>{{.Status}}{{range .Rules}} • {{.Rule}} ({{.Status}}){{end}}</span></div>{{end}}</div>{{end}}
         </div>
         <div class="footer"><p>Generated by Terraship v1.1.0 | {{.Timestamp}}</p></div>
     </div>
@@ -311,14 +319,14 @@ func getHTMLTemplate() string {
             type: 'doughnut', data: { labels: ['Passed', 'Failed', 'Warnings'], datasets: [{ data: [{{.PassedResources}}, {{.FailedResources}}, {{.WarningResources}}], backgroundColor: [cc.passed, cc.failed, cc.warning], borderColor: ['#fff', '#fff', '#fff'], borderWidth: 2 }] },
             options: { responsive: true, maintainAspectRatio: true, plugins: { legend: { position: 'bottom' } } }
         });
-        new Chart(document.getElementById('timelineChart'), {
-            type: 'line', data: { labels: ['Mon', 'Tue', 'Wed', 'Thu', 'Fri', 'Sat', 'Sun'], datasets: [
-                { label: 'Passed', data: [8, 8, 9, 9, 10, 10, 10], borderColor: cc.passed, backgroundColor:  'rgba(16, 185, 129, 0.1)', tension: 0.4 },
-                { label: 'Failed', data: [19, 19, 18, 18, 16, 16, 16], borderColor: cc.failed, backgroundColor: 'rgba(239, 68, 68, 0.1)', tension: 0.4 },
-                { label: 'Warnings', data: [2, 2, 2, 2, 1, 1, 1], borderColor: cc.warning, backgroundColor: 'rgba(245, 158, 11, 0.1)', tension: 0.4 }
+        {{if .ValidationHistory}}new Chart(document.getElementById('timelineChart'), {
+            type: 'line', data: { labels: [{{range $i, $p := .ValidationHistory}}{{if $i}}, {{end}}{{$p.Label}}{{end}}], datasets: [
+                { label: 'Passed', data: [{{range $i, $p := .ValidationHistory}}{{if $i}}, {{end}}{{$p.Passed}}{{end}}], borderColor: cc.passed, backgroundColor:  'rgba(16, 185, 129, 0.1)', tension: 0.4 },
+                { label: 'Failed', data: [{{range $i, $p := .ValidationHistory}}{{if $i}}, {{end}}{{$p.Failed}}{{end}}], borderColor: cc.failed, backgroundColor: 'rgba(239, 68, 68, 0.1)', tension: 0.4 },
+                { label: 'Warnings', data: [{{range $i, $p := .ValidationHistory}}{{if $i}}, {{end}}{{$p.Warnings}}{{end}}], borderColor: cc.warning, backgroundColor: 'rgba(245, 158, 11, 0.1)', tension: 0.4 }
             ] },
             options: { responsive: true, maintainAspectRatio: true, plugins: { legend: { position: 'bottom' } } }
-        });
+        });{{end}}
     </script>
 </body>
 </html>`
@@ -2,6 +2,9 @@ package output
 
 import (
 	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
 	"time"
 )
 
@@ -11,8 +14,12 @@ type ValidationResult struct {
 	PassedResources  int
 	FailedResources  int
 	WarningResources int
+	WaivedFindings   int
+	BaselineFindings int
 	Timestamp        string
 	Resources        []Resource
+	Comparison       *ComparisonReport // set when compared with a previous run
+	History          []HistoryPoint    // recent runs, oldest first, for the trend chart
 }
 
 // Resource represents a validated resource
@@ -34,6 +41,18 @@ type Check struct {
 	Warning     bool
 	Details     []string
 	Remediation string
+
+	// Waived checks failed but are suppressed by a waiver
+	Waived        bool
+	WaiverReason  string
+	WaiverExpires string
+
+	// Baselined checks failed but are already recorded in the baseline
+	Baselined bool
+
+	// Live checks were evaluated against the deployed resource rather than
+	// the planned values
+	Live bool
 }
 
 // ToJSON converts results to JSON
@@ -44,14 +63,59 @@ func (vr *ValidationResult) ToJSON() ([]byte, error) {
 		"passed_resources":   vr.PassedResources,
 		"failed_resources":   vr.FailedResources,
 		"warning_resources":  vr.WarningResources,
+		"waived_findings":    vr.WaivedFindings,
+		"baseline_findings":  vr.BaselineFindings,
 		"compliance_percent": calculateCompliance(vr.TotalResources, vr.PassedResources),
 		"resources":          vr.Resources,
 		"validation_passed":  vr.FailedResources == 0,
 	}
+	if vr.Comparison != nil {
+		data["comparison"] = vr.Comparison
+	}
 
 	return json.MarshalIndent(data, "", "  ")
 }
 
+// jsonReport is the layout written by ToJSON
+type jsonReport struct {
+	Timestamp        string     `json:"timestamp"`
+	TotalResources   int        `json:"total_resources"`
+	PassedResources  int        `json:"passed_resources"`
+	FailedResources  int        `json:"failed_resources"`
+	WarningResources int        `json:"warning_resources"`
+	WaivedFindings   int        `json:"waived_findings"`
+	BaselineFindings int        `json:"baseline_findings"`
+	Resources        []Resource `json:"resources"`
+}
+
+// ParseJSON parses a report written by ToJSON
+func ParseJSON(data []byte) (*ValidationResult, error) {
+	var report jsonReport
+	if err := json.Unmarshal(data, &report); err != nil {
+		return nil, fmt.Errorf("failed to parse JSON report: %w", err)
+	}
+
+	return &ValidationResult{
+		TotalResources:   report.TotalResources,
+		PassedResources:  report.PassedResources,
+		FailedResources:  report.FailedResources,
+		WarningResources: report.WarningResources,
+		WaivedFindings:   report.WaivedFindings,
+		BaselineFindings: report.BaselineFindings,
+		Timestamp:        report.Timestamp,
+		Resources:        report.Resources,
+	}, nil
+}
+
+// LoadJSONFile reads a report written by ToJSON
+func LoadJSONFile(path string) (*ValidationResult, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read JSON report: %w", err)
+	}
+	return ParseJSON(data)
+}
+
 // ToSARIF converts results to SARIF format
 func (vr *ValidationResult) ToSARIF() ([]byte, error) {
 	// SARIF 2.1.0 format for GitHub Code Scanning and other tools
@@ -81,9 +145,9 @@ func buildSARIFResults(vr *ValidationResult) []map[string]interface{} {
 
 	for _, resource := range vr.Resources {
 		for _, check := range resource.Checks {
-			if check.Failed || check.Warning {
+			if check.Failed || check.Warning || check.Waived || check.Baselined {
 				level := "warning"
-				if check.Failed {
+				if check.Failed || ((check.Waived || check.Baselined) && check.Severity == "error") {
 					level = "error"
 				}
 
@@ -108,6 +172,14 @@ func buildSARIFResults(vr *ValidationResult) []map[string]interface{} {
 						"severity":      check.Severity,
 					},
 				}
+				if check.Waived {
+					result["suppressions"] = []map[string]interface{}{
+						{"kind": "external", "justification": describeWaiver(check.WaiverReason, check.WaiverExpires)},
+					}
+				}
+				if check.Baselined {
+					result["baselineState"] = "unchanged"
+				}
 
 				results = append(results, result)
 			}
@@ -125,40 +197,208 @@ func calculateCompliance(total, passed int) float64 {
 	return (float64(passed) / float64(total)) * 100
 }
 
+// Delta statuses of a rule or resource between two validation runs
+const (
+	DeltaNew       = "new"       // failing now; the rule or resource was not in the previous run
+	DeltaFixed     = "fixed"     // failed previously; passing or gone now
+	DeltaRegressed = "regressed" // passed previously; failing now
+	DeltaUnchanged = "unchanged" // failing in both runs
+	DeltaImproved  = "improved"  // resource with some findings fixed and none new or regressed
+	DeltaRemoved   = "removed"   // resource not present in the current run
+)
+
 // ComparisonReport represents a comparison between two validation runs
 type ComparisonReport struct {
-	Current          *ValidationResult
-	Previous         *ValidationResult
-	ChangedResources []ResourceChange
-	TrendPercent     float64 // positive = improving, negative = regressing
+	Current           *ValidationResult `json:"-"`
+	Previous          *ValidationResult `json:"-"`
+	PreviousTimestamp string            `json:"previous_timestamp"`
+	ChangedResources  []ResourceChange  `json:"changed_resources"`
+	TrendPercent      float64           `json:"trend_percent"` // positive = improving, negative = regressing
+	NewFindings       int               `json:"new_findings"`
+	FixedFindings     int               `json:"fixed_findings"`
+	RegressedFindings int               `json:"regressed_findings"`
+	UnchangedFindings int               `json:"unchanged_findings"`
 }
 
 // ResourceChange tracks changes in a resource validation
 type ResourceChange struct {
-	ResourceName   string
-	Status         string // "improved", "regressed", "unchanged"
-	PreviousFailed int
-	CurrentFailed  int
-	DetailChanges  []string
+	ResourceName   string       `json:"resource"`
+	Status         string       `json:"status"` // "new", "removed", "fixed", "improved", "regressed", "unchanged"
+	PreviousFailed int          `json:"previous_failed"`
+	CurrentFailed  int          `json:"current_failed"`
+	DetailChanges  []string     `json:"details,omitempty"`
+	Rules          []RuleChange `json:"rules,omitempty"`
 }
 
-// Compare compares two validation runs
+// RuleChange is the delta of a single rule on a resource
+type RuleChange struct {
+	Rule   string `json:"rule"`
+	Status string `json:"status"` // "new", "fixed", "regressed"
+}
+
+// Compare compares two validation runs. Findings are matched by resource name
+// and rule name; only failed checks count as findings.
 func Compare(current, previous *ValidationResult) *ComparisonReport {
 	report := &ComparisonReport{
 		Current:  current,
 		Previous: previous,
 	}
 
+	if previous == nil {
+		return report
+	}
+
+	report.PreviousTimestamp = previous.Timestamp
+
 	// Calculate trend
-	if previous != nil {
-		prevCompliance := calculateCompliance(previous.TotalResources, previous.PassedResources)
-		currCompliance := calculateCompliance(current.TotalResources, current.PassedResources)
-		report.TrendPercent = currCompliance - prevCompliance
+	prevCompliance := calculateCompliance(previous.TotalResources, previous.PassedResources)
+	currCompliance := calculateCompliance(current.TotalResources, current.PassedResources)
+	report.TrendPercent = currCompliance - prevCompliance
+
+	previousResources := make(map[string]*Resource, len(previous.Resources))
+	for i := range previous.Resources {
+		previousResources[previous.Resources[i].Name] = &previous.Resources[i]
+	}
+
+	seen := make(map[string]bool, len(current.Resources))
+	for i := range current.Resources {
+		resource := &current.Resources[i]
+		seen[resource.Name] = true
+
+		change, unchanged := compareResource(resource, previousResources[resource.Name])
+		report.count(change, unchanged)
+		if change.Status != DeltaUnchanged || len(change.Rules) > 0 {
+			report.ChangedResources = append(report.ChangedResources, change)
+		}
+	}
+
+	for i := range previous.Resources {
+		resource := &previous.Resources[i]
+		if seen[resource.Name] {
+			continue
+		}
+
+		change, _ := compareResource(&Resource{Name: resource.Name}, resource)
+		change.Status = DeltaRemoved
+		report.count(change, 0)
+		report.ChangedResources = append(report.ChangedResources, change)
 	}
 
+	sort.SliceStable(report.ChangedResources, func(i, j int) bool {
+		return report.ChangedResources[i].ResourceName < report.ChangedResources[j].ResourceName
+	})
+
 	return report
 }
 
+// count adds the rule deltas of a resource to the report totals
+func (r *ComparisonReport) count(change ResourceChange, unchanged int) {
+	for _, rule := range change.Rules {
+		switch rule.Status {
+		case DeltaNew:
+			r.NewFindings++
+		case DeltaFixed:
+			r.FixedFindings++
+		case DeltaRegressed:
+			r.RegressedFindings++
+		}
+	}
+	r.UnchangedFindings += unchanged
+}
+
+// compareResource computes the rule deltas of a resource and the number of
+// findings failing in both runs. previous is nil when the resource is new.
+func compareResource(current, previous *Resource) (ResourceChange, int) {
+	change := ResourceChange{ResourceName: current.Name}
+
+	currentRules, currentOrder, currentFailed := ruleStates(current)
+	previousRules, previousOrder, previousFailed := ruleStates(previous)
+	change.CurrentFailed = currentFailed
+	change.PreviousFailed = previousFailed
+
+	unchanged := 0
+	for _, rule := range currentOrder {
+		if !currentRules[rule] {
+			continue
+		}
+
+		wasFailing, existed := previousRules[rule]
+		switch {
+		case !existed:
+			change.addRule(rule, DeltaNew)
+		case wasFailing:
+			unchanged++
+		default:
+			change.addRule(rule, DeltaRegressed)
+		}
+	}
+
+	for _, rule := range previousOrder {
+		if previousRules[rule] && !currentRules[rule] {
+			change.addRule(rule, DeltaFixed)
+		}
+	}
+
+	change.Status = change.resourceStatus(previous == nil)
+	return change, unchanged
+}
+
+// ruleStates maps each rule checked on a resource to whether any of its checks
+// failed, and returns the rules in order of appearance and the failed check count
+func ruleStates(resource *Resource) (map[string]bool, []string, int) {
+	states := make(map[string]bool)
+	var order []string
+	failed := 0
+
+	if resource == nil {
+		return states, order, failed
+	}
+
+	for _, check := range resource.Checks {
+		if _, seen := states[check.Name]; !seen {
+			order = append(order, check.Name)
+		}
+		states[check.Name] = states[check.Name] || check.Failed
+		if check.Failed {
+			failed++
+		}
+	}
+
+	return states, order, failed
+}
+
+// addRule records a rule delta on the resource change
+func (c *ResourceChange) addRule(rule, status string) {
+	c.Rules = append(c.Rules, RuleChange{Rule: rule, Status: status})
+	c.DetailChanges = append(c.DetailChanges, fmt.Sprintf("%s: %s", rule, status))
+}
+
+// resourceStatus summarizes the rule deltas into a resource status
+func (c *ResourceChange) resourceStatus(added bool) string {
+	var fixed, worse bool
+	for _, rule := range c.Rules {
+		switch rule.Status {
+		case DeltaFixed:
+			fixed = true
+		case DeltaNew, DeltaRegressed:
+			worse = true
+		}
+	}
+
+	switch {
+	case added:
+		return DeltaNew
+	case worse:
+		return DeltaRegressed
+	case fixed && c.CurrentFailed == 0:
+		return DeltaFixed
+	case fixed:
+		return DeltaImproved
+	default:
+		return DeltaUnchanged
+	}
+}
+
 // ExportStats returns exportable statistics
 type ExportStats struct {
 	Timestamp           time.Time      `json:"timestamp"`
@@ -125,30 +125,144 @@ func TestValidationResult_Compliance(t *testing.T) {
 	}
 }
 
-// TestHtmlReporter_GenerateHTML tests HTML report generation
-func TestHtmlReporter_GenerateHTML(t *testing.T) {
-	reporter := NewHtmlReporter()
-
-	reportData := &HtmlReportData{
-		Title:             "Test Report",
-		Timestamp:         "2026-02-19 11:15 AM",
-		TotalResources:    10,
-		PassedResources:   8,
-		FailedResources:   2,
-		CompliancePercent: 80.0,
-		Resources: []ResourceReport{
+// TestParseJSON_RoundTrip tests that a JSON report can be loaded back
+func TestParseJSON_RoundTrip(t *testing.T) {
+	result := &ValidationResult{
+		TotalResources:  1,
+		FailedResources: 1,
+		Timestamp:       "2026-02-19 11:15 AM",
+		Resources: []Resource{
+			{
+				Name:     "aws_s3_bucket.logs",
+				Type:     "aws_s3_bucket",
+				Provider: "aws",
+				IsFailed: true,
+				Checks:   []Check{{Name: "require-encryption", Severity: "error", Failed: true}},
+			},
+		},
+	}
+
+	jsonBytes, err := result.ToJSON()
+	if err != nil {
+		t.Fatalf("ToJSON() failed: %v", err)
+	}
+
+	loaded, err := ParseJSON(jsonBytes)
+	if err != nil {
+		t.Fatalf("ParseJSON() failed: %v", err)
+	}
+	if loaded.Timestamp != result.Timestamp || loaded.FailedResources != 1 {
+		t.Errorf("Unexpected summary after round trip: %+v", loaded)
+	}
+	if len(loaded.Resources) != 1 || len(loaded.Resources[0].Checks) != 1 || !loaded.Resources[0].Checks[0].Failed {
+		t.Errorf("Expected failed check to survive round trip, got %+v", loaded.Resources)
+	}
+
+	if _, err := ParseJSON([]byte("not json")); err == nil {
+		t.Error("Expected error for invalid JSON")
+	}
+}
+
+// TestCompare tests finding deltas between two runs
+func TestCompare(t *testing.T) {
+	previous := &ValidationResult{
+		TotalResources:  3,
+		PassedResources: 1,
+		Timestamp:       "2026-02-18 10:00 AM",
+		Resources: []Resource{
+			{Name: "aws_s3_bucket.a", Checks: []Check{
+				{Name: "encryption", Failed: true},
+				{Name: "versioning", Failed: true},
+			}},
+			{Name: "aws_s3_bucket.b", Checks: []Check{{Name: "encryption"}}},
+			{Name: "aws_s3_bucket.old", Checks: []Check{{Name: "encryption", Failed: true}}},
+		},
+	}
+	current := &ValidationResult{
+		TotalResources:  3,
+		PassedResources: 0,
+		Resources: []Resource{
+			{Name: "aws_s3_bucket.a", Checks: []Check{
+				{Name: "encryption", Failed: true},
+				{Name: "versioning"},
+			}},
+			{Name: "aws_s3_bucket.b", Checks: []Check{{Name: "encryption", Failed: true}}},
+			{Name: "aws_s3_bucket.c", Checks: []Check{{Name: "encryption", Failed: true}}},
+		},
+	}
+
+	report := Compare(current, previous)
+
+	if report.NewFindings != 1 || report.FixedFindings != 2 || report.RegressedFindings != 1 || report.UnchangedFindings != 1 {
+		t.Errorf("Unexpected counts: new=%d fixed=%d regressed=%d unchanged=%d",
+			report.NewFindings, report.FixedFindings, report.RegressedFindings, report.UnchangedFindings)
+	}
+	if report.PreviousTimestamp != previous.Timestamp {
+		t.Errorf("Expected previous timestamp %q, got %q", previous.Timestamp, report.PreviousTimestamp)
+	}
+
+	statuses := make(map[string]string)
+	for _, change := range report.ChangedResources {
+		statuses[change.ResourceName] = change.Status
+	}
+	expected := map[string]string{
+		"aws_s3_bucket.a":   DeltaImproved,
+		"aws_s3_bucket.b":   DeltaRegressed,
+		"aws_s3_bucket.c":   DeltaNew,
+		"aws_s3_bucket.old": DeltaRemoved,
+	}
+	for name, status := range expected {
+		if statuses[name] != status {
+			t.Errorf("Expected %s to be %s, got %q", name, status, statuses[name])
+		}
+	}
+	if report.TrendPercent >= 0 {
+		t.Errorf("Expected negative trend, got %.1f", report.TrendPercent)
+	}
+}
+
+// TestPrepareReportData_History tests that the trend chart uses recorded runs
+func TestPrepareReportData_History(t *testing.T) {
+	result := &ValidationResult{
+		TotalResources:  2,
+		PassedResources: 2,
+		History: []HistoryPoint{
+			{Label: "Oct 1 09:00", Passed: 1, Failed: 1},
+			{Label: "Oct 2 09:00", Passed: 2},
+		},
+	}
+
+	data := PrepareReportData(result, true, nil)
+	if len(data.ValidationHistory) != 2 || data.ValidationHistory[1].Passed != 2 {
+		t.Errorf("Expected recorded history, got %+v", data.ValidationHistory)
+	}
+
+	data = PrepareReportData(result, false, nil)
+	if len(data.ValidationHistory) != 0 {
+		t.Errorf("Expected no history without includeHistory, got %+v", data.ValidationHistory)
+	}
+}
+
+// TestGenerateHTML tests HTML report generation
+func TestGenerateHTML(t *testing.T) {
+	result := &ValidationResult{
+		Timestamp:       "2026-02-19 11:15 AM",
+		TotalResources:  10,
+		PassedResources: 8,
+		FailedResources: 2,
+		Resources: []Resource{
 			{
-				Name:        "test-resource",
-				Type:        "aws_s3_bucket",
-				Provider:    "aws",
-				Status:      "passed",
-				CheckCount:  5,
-				PassedCount: 5,
+				Name:     "test-resource",
+				Type:     "aws_s3_bucket",
+				Provider: "aws",
+				Checks: []Check{
+					{Name: "encryption", Severity: "error"},
+				},
 			},
 		},
 	}
 
-	html, err := reporter.GenerateHTML(reportData)
+	html, err := GenerateHTML(result, false, nil)
 	if err != nil {
 		t.Fatalf("GenerateHTML() failed: %v", err)
 	}
@@ -172,33 +286,6 @@ func TestHtmlReporter_GenerateHTML(t *testing.T) {
 	}
 }
 
-// TestPDFReporter_Initialization tests PDF reporter initialization
-func TestPDFReporter_Initialization(t *testing.T) {
-	reporter := NewPDFReporter()
-	if reporter == nil {
-		t.Error("NewPDFReporter() returned nil")
-	}
-
-	// Verify it has the HTML reporter
-	if reporter.htmlReporter == nil {
-		t.Error("PDFReporter missing htmlReporter")
-	}
-}
-
-// TestGetPDFInstallInstructions verifies installation help text
-func TestGetPDFInstallInstructions(t *testing.T) {
-	instructions := GetPDFInstallInstructions()
-
-	if len(instructions) == 0 {
-		t.Error("GetPDFInstallInstructions returned empty string")
-	}
-
-	// Should mention installation methods
-	if !contains(instructions, "brew") && !contains(instructions, "apt") {
-		t.Error("Instructions should include package manager details")
-	}
-}
-
 // Helper function
 func contains(s, substr string) bool {
 	for i := 0; i < len(s)-len(substr)+1; i++ {
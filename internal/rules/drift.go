@@ -0,0 +1,64 @@
+package rules
+
+import (
+	"path"
+	"strings"
+)
+
+// DriftSettings configures drift detection
+type DriftSettings struct {
+	// Ignore lists attributes whose drift is not reported, such as values
+	// the cloud changes on its own
+	Ignore []DriftIgnore `yaml:"ignore,omitempty"`
+}
+
+// DriftIgnore ignores drift of attributes of some resource types
+type DriftIgnore struct {
+	ResourceTypes []string `yaml:"resource_types"` // every resource type when empty; * is a wildcard
+	Attributes    []string `yaml:"attributes"`     // attribute paths or patterns, e.g. public_ip, tags.LastScanned or tags.*
+	Reason        string   `yaml:"reason,omitempty"`
+}
+
+// DriftIgnored reports whether the policy ignores drift of an attribute of a
+// resource type. An attribute pattern also ignores the attributes nested in
+// it, so tags ignores every tag.
+func (e *Engine) DriftIgnored(resourceType, attribute string) bool {
+	for _, ignore := range e.policy.Drift.Ignore {
+		if !ignore.appliesTo(resourceType) {
+			continue
+		}
+		for _, pattern := range ignore.Attributes {
+			if matchAttribute(pattern, attribute) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (i DriftIgnore) appliesTo(resourceType string) bool {
+	if len(i.ResourceTypes) == 0 {
+		return true
+	}
+	for _, pattern := range i.ResourceTypes {
+		if matchResourceType(pattern, resourceType) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchAttribute matches an attribute path, or one of its parents, against a
+// pattern: the path itself or a glob
+func matchAttribute(pattern, attribute string) bool {
+	for {
+		if matched, _ := path.Match(pattern, attribute); matched || pattern == attribute {
+			return true
+		}
+		i := strings.LastIndexAny(attribute, ".[")
+		if i < 0 {
+			return false
+		}
+		attribute = attribute[:i]
+	}
+}
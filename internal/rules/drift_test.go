@@ -0,0 +1,44 @@
+package rules
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEngine_DriftIgnored(t *testing.T) {
+	dir := writePolicyFiles(t, map[string]string{
+		"base.yml": basePolicy + `
+drift:
+  ignore:
+    - attributes: ["tags.LastScanned"]
+      reason: Set by the nightly scanner
+`,
+		"team.yml": `version: "1.0"
+name: "Team"
+extends: base.yml
+rules: []
+drift:
+  ignore:
+    - resource_types: ["aws_instance"]
+      attributes: ["public_ip", "tags.aws:*"]
+    - resource_types: ["google_*"]
+      attributes: ["labels"]
+    - attributes: ["versioning[0].enabled"]
+`,
+	})
+
+	engine, err := NewEngine(filepath.Join(dir, "team.yml"))
+	require.NoError(t, err)
+
+	assert.True(t, engine.DriftIgnored("aws_s3_bucket", "tags.LastScanned"), "inherited from the extended policy")
+	assert.True(t, engine.DriftIgnored("aws_instance", "public_ip"))
+	assert.False(t, engine.DriftIgnored("aws_eip", "public_ip"))
+	assert.True(t, engine.DriftIgnored("aws_instance", "tags.aws:autoscaling:groupName"))
+	assert.False(t, engine.DriftIgnored("aws_instance", "tags.Owner"))
+	assert.True(t, engine.DriftIgnored("google_storage_bucket", "labels.env"), "patterns cover nested attributes")
+	assert.True(t, engine.DriftIgnored("aws_s3_bucket", "versioning[0].enabled"))
+	assert.False(t, engine.DriftIgnored("aws_s3_bucket", "instance_type"))
+}
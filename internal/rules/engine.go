@@ -3,40 +3,65 @@ package rules
 
 import (
 	"fmt"
-	"os"
 	"regexp"
+	"sort"
 	"strings"
+	"sync"
 
+	"github.com/google/cel-go/cel"
 	"github.com/vijayaxai/terraship/internal/cloud"
-	"gopkg.in/yaml.v3"
 )
 
-// Policy represents a collection of validation rules
+// Policy represents a collection of validation rules. Extends names policy
+// files (relative to this one) whose rules are inherited; Overrides adjusts
+// the severity or enabled state of inherited rules. Drift settings are
+// inherited too.
 type Policy struct {
 	Version     string                 `yaml:"version"`
 	Name        string                 `yaml:"name"`
 	Description string                 `yaml:"description"`
+	Extends     PolicyRefs             `yaml:"extends,omitempty"`
 	Rules       []cloud.ValidationRule `yaml:"rules"`
+	Overrides   []RuleOverride         `yaml:"overrides,omitempty"`
+	Drift       DriftSettings          `yaml:"drift,omitempty"`
 }
 
 // Engine evaluates rules against resources
 type Engine struct {
-	policy *Policy
+	policy       *Policy
+	regoPolicies []regoPolicy
+	exprEnv      *cel.Env
+	exprs        map[string]cel.Program // compiled expr conditions by source
+	exprMu       sync.Mutex             // guards exprEnv and exprs; rules are evaluated concurrently
 }
 
-// NewEngine creates a new rules engine
-func NewEngine(policyPath string) (*Engine, error) {
-	data, err := os.ReadFile(policyPath)
+// NewEngine creates a new rules engine from one or more policy files,
+// directories or glob patterns. Rules from all files are merged; duplicate
+// rule names are an error. Rego modules (*.rego) in the directories of the
+// policy files and the files they extend are loaded alongside the YAML rules,
+// and expr conditions are compiled so invalid expressions fail here.
+func NewEngine(policyPaths ...string) (*Engine, error) {
+	files, err := ResolvePolicyPaths(policyPaths)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read policy file: %w", err)
+		return nil, err
 	}
 
-	var policy Policy
-	if err := yaml.Unmarshal(data, &policy); err != nil {
-		return nil, fmt.Errorf("failed to parse policy: %w", err)
+	policy, sources, err := loadPolicies(files)
+	if err != nil {
+		return nil, err
+	}
+
+	regoPolicies, err := loadRegoPolicies(policyDirs(sources))
+	if err != nil {
+		return nil, err
+	}
+
+	engine := &Engine{policy: policy, regoPolicies: regoPolicies}
+	if err := engine.compileRuleExprs(); err != nil {
+		return nil, err
 	}
 
-	return &Engine{policy: &policy}, nil
+	return engine, nil
 }
 
 // GetRulesForResource returns rules applicable to a resource type
@@ -68,6 +93,13 @@ func (e *Engine) GetRulesForResource(resourceType string) []cloud.ValidationRule
 
 // EvaluateRule checks if a resource meets a rule's conditions
 func (e *Engine) EvaluateRule(rule cloud.ValidationRule, resource map[string]interface{}) cloud.ValidationResult {
+	return e.EvaluateRuleWithContext(rule, resource, nil)
+}
+
+// EvaluateRuleWithContext checks if a resource meets a rule's conditions,
+// exposing the resource address, plan metadata and planned actions to expr
+// conditions. env may be nil.
+func (e *Engine) EvaluateRuleWithContext(rule cloud.ValidationRule, resource map[string]interface{}, env *EvalContext) cloud.ValidationResult {
 	result := cloud.ValidationResult{
 		RuleName:    rule.Name,
 		Severity:    rule.Severity,
@@ -77,19 +109,46 @@ func (e *Engine) EvaluateRule(rule cloud.ValidationRule, resource map[string]int
 	}
 
 	// Evaluate conditions
-	for condition, expected := range rule.Conditions {
-		if !e.evaluateCondition(condition, expected, resource, &result) {
-			result.Passed = false
-			break
-		}
+	if !e.evaluateConditions(rule.Conditions, resource, env, &result) {
+		result.Passed = false
 	}
 
 	return result
 }
 
+// evaluateConditions ANDs a set of conditions, stopping at the first failure.
+// Conditions are evaluated in sorted key order so failure details are deterministic.
+func (e *Engine) evaluateConditions(conditions map[string]interface{}, resource map[string]interface{}, env *EvalContext, result *cloud.ValidationResult) bool {
+	keys := make([]string, 0, len(conditions))
+	for condition := range conditions {
+		keys = append(keys, condition)
+	}
+	sort.Strings(keys)
+
+	for _, condition := range keys {
+		if !e.evaluateCondition(condition, conditions[condition], resource, env, result) {
+			return false
+		}
+	}
+
+	return true
+}
+
 // evaluateCondition checks a single condition
-func (e *Engine) evaluateCondition(condition string, expected interface{}, resource map[string]interface{}, result *cloud.ValidationResult) bool {
+func (e *Engine) evaluateCondition(condition string, expected interface{}, resource map[string]interface{}, env *EvalContext, result *cloud.ValidationResult) bool {
 	switch condition {
+	case "all":
+		return e.checkAll(expected, resource, env, result)
+
+	case "any":
+		return e.checkAny(expected, resource, env, result)
+
+	case "not":
+		return e.checkNot(expected, resource, env, result)
+
+	case "expr":
+		return e.checkExpr(expected, resource, env, result)
+
 	case "tags.required":
 		return e.checkRequiredTags(expected, resource, result)
 
@@ -123,6 +182,84 @@ func (e *Engine) evaluateCondition(condition string, expected interface{}, resou
 	}
 }
 
+// checkAll passes when every nested condition block passes
+func (e *Engine) checkAll(expected interface{}, resource map[string]interface{}, env *EvalContext, result *cloud.ValidationResult) bool {
+	blocks, ok := conditionBlocks(expected)
+	if !ok {
+		result.Details = append(result.Details, "Invalid all configuration: expected a list of conditions")
+		return false
+	}
+
+	for _, block := range blocks {
+		if !e.evaluateConditions(block, resource, env, result) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// checkAny passes when at least one nested condition block passes. Details
+// from the failed alternatives are only reported when none of them pass.
+func (e *Engine) checkAny(expected interface{}, resource map[string]interface{}, env *EvalContext, result *cloud.ValidationResult) bool {
+	blocks, ok := conditionBlocks(expected)
+	if !ok || len(blocks) == 0 {
+		result.Details = append(result.Details, "Invalid any configuration: expected a list of conditions")
+		return false
+	}
+
+	var details []string
+	for _, block := range blocks {
+		scratch := cloud.ValidationResult{}
+		if e.evaluateConditions(block, resource, env, &scratch) {
+			return true
+		}
+		details = append(details, scratch.Details...)
+	}
+
+	result.Details = append(result.Details, fmt.Sprintf("None of %d alternative conditions matched", len(blocks)))
+	result.Details = append(result.Details, details...)
+	return false
+}
+
+// checkNot passes when the nested condition block fails
+func (e *Engine) checkNot(expected interface{}, resource map[string]interface{}, env *EvalContext, result *cloud.ValidationResult) bool {
+	block, ok := expected.(map[string]interface{})
+	if !ok {
+		result.Details = append(result.Details, "Invalid not configuration: expected a map of conditions")
+		return false
+	}
+
+	scratch := cloud.ValidationResult{}
+	if e.evaluateConditions(block, resource, env, &scratch) {
+		result.Details = append(result.Details, fmt.Sprintf("Resource matches negated condition %v", block))
+		return false
+	}
+
+	return true
+}
+
+// conditionBlocks converts an all/any value into a list of condition maps.
+// A single map is treated as a list with one block.
+func conditionBlocks(expected interface{}) ([]map[string]interface{}, bool) {
+	switch value := expected.(type) {
+	case map[string]interface{}:
+		return []map[string]interface{}{value}, true
+	case []interface{}:
+		blocks := make([]map[string]interface{}, 0, len(value))
+		for _, item := range value {
+			block, ok := item.(map[string]interface{})
+			if !ok {
+				return nil, false
+			}
+			blocks = append(blocks, block)
+		}
+		return blocks, true
+	default:
+		return nil, false
+	}
+}
+
 func (e *Engine) checkRequiredTags(expected interface{}, resource map[string]interface{}, result *cloud.ValidationResult) bool {
 	requiredTags, ok := expected.([]interface{})
 	if !ok {
@@ -347,36 +484,51 @@ func (e *Engine) checkPrivateSubnet(expected interface{}, resource map[string]in
 }
 
 func (e *Engine) checkProperty(propertyPath string, expected interface{}, resource map[string]interface{}, result *cloud.ValidationResult) bool {
-	// Navigate nested properties using dot notation
-	parts := strings.Split(propertyPath, ".")
-	current := resource
-
-	for i, part := range parts {
-		value, exists := current[part]
-		if !exists {
-			result.Details = append(result.Details, fmt.Sprintf("Property '%s' not found", propertyPath))
-			return false
+	// Navigate nested properties using dot notation, list indexes and quantifiers
+	segments, err := parsePropertyPath(propertyPath)
+	if err != nil {
+		result.Details = append(result.Details, err.Error())
+		return false
+	}
+
+	check := equalityCheck(expected)
+	// Typed operators, e.g. {gte: 7} or {in: [t3.micro, t3.small]}
+	if ops, ok := asOperators(expected); ok {
+		check = operatorCheck(ops)
+	}
+
+	passed, details := evaluatePath(resource, segments, "", check)
+	result.Details = append(result.Details, details...)
+	return passed
+}
+
+// equalityCheck compares a property value with the expected value as strings
+func equalityCheck(expected interface{}) valueCheck {
+	return func(value interface{}, present bool) (bool, string) {
+		if !present {
+			return false, "not found"
 		}
+		if fmt.Sprint(value) != fmt.Sprint(expected) {
+			return false, fmt.Sprintf("has value '%v', expected '%v'", value, expected)
+		}
+		return true, ""
+	}
+}
 
-		if i == len(parts)-1 {
-			// Last part - compare value
-			if fmt.Sprint(value) != fmt.Sprint(expected) {
-				result.Details = append(result.Details, fmt.Sprintf("Property '%s' has value '%v', expected '%v'", propertyPath, value, expected))
-				return false
+// operatorCheck applies every operator of a condition to a property value
+func operatorCheck(ops map[string]interface{}) valueCheck {
+	return func(value interface{}, present bool) (bool, string) {
+		var failures []string
+		for _, name := range sortedOperatorNames(ops) {
+			if ok, detail := operators[name](value, present, ops[name]); !ok {
+				failures = append(failures, detail)
 			}
-			return true
 		}
-
-		// Navigate deeper
-		if nested, ok := value.(map[string]interface{}); ok {
-			current = nested
-		} else {
-			result.Details = append(result.Details, fmt.Sprintf("Cannot navigate property path '%s'", propertyPath))
-			return false
+		if len(failures) > 0 {
+			return false, strings.Join(failures, "; ")
 		}
+		return true, ""
 	}
-
-	return true
 }
 
 // matchResourceType checks if a resource type matches a pattern
@@ -121,3 +121,207 @@ func TestRulesEngine_Encryption(t *testing.T) {
 		})
 	}
 }
+
+func TestRulesEngine_Operators(t *testing.T) {
+	engine := &Engine{policy: &Policy{}}
+
+	resource := map[string]interface{}{
+		"backup_retention_period": float64(7),
+		"instance_type":           "t3.micro",
+		"name":                    "prod-db-01",
+		"security_groups":         []interface{}{"sg-1", "sg-2"},
+		"tags": map[string]interface{}{
+			"Owner": "platform",
+		},
+	}
+
+	tests := []struct {
+		name       string
+		conditions map[string]interface{}
+		shouldPass bool
+	}{
+		{"gte passes", map[string]interface{}{"backup_retention_period": map[string]interface{}{"gte": 7}}, true},
+		{"gt fails", map[string]interface{}{"backup_retention_period": map[string]interface{}{"gt": 7}}, false},
+		{"lt and gte range", map[string]interface{}{"backup_retention_period": map[string]interface{}{"gte": 1, "lt": 35}}, true},
+		{"lte fails", map[string]interface{}{"backup_retention_period": map[string]interface{}{"lte": "3"}}, false},
+		{"non-numeric value", map[string]interface{}{"instance_type": map[string]interface{}{"gt": 1}}, false},
+		{"in passes", map[string]interface{}{"instance_type": map[string]interface{}{"in": []interface{}{"t3.micro", "t3.small"}}}, true},
+		{"in fails", map[string]interface{}{"instance_type": map[string]interface{}{"in": []interface{}{"m5.large"}}}, false},
+		{"not_in passes", map[string]interface{}{"instance_type": map[string]interface{}{"not_in": []interface{}{"m5.large"}}}, true},
+		{"not_in fails", map[string]interface{}{"instance_type": map[string]interface{}{"not_in": []interface{}{"t3.micro"}}}, false},
+		{"matches passes", map[string]interface{}{"name": map[string]interface{}{"matches": "^prod-[a-z]+-[0-9]+$"}}, true},
+		{"matches fails", map[string]interface{}{"name": map[string]interface{}{"matches": "^dev-"}}, false},
+		{"contains list", map[string]interface{}{"security_groups": map[string]interface{}{"contains": "sg-2"}}, true},
+		{"contains string fails", map[string]interface{}{"name": map[string]interface{}{"contains": "staging"}}, false},
+		{"contains map key", map[string]interface{}{"tags": map[string]interface{}{"contains": "Owner"}}, true},
+		{"exists passes", map[string]interface{}{"tags.Owner": map[string]interface{}{"exists": true}}, true},
+		{"exists fails", map[string]interface{}{"tags.CostCenter": map[string]interface{}{"exists": true}}, false},
+		{"absent passes", map[string]interface{}{"publicly_accessible": map[string]interface{}{"absent": true}}, true},
+		{"absent fails", map[string]interface{}{"instance_type": map[string]interface{}{"absent": true}}, false},
+		{"exists false passes", map[string]interface{}{"publicly_accessible": map[string]interface{}{"exists": false}}, true},
+		{"exists false fails", map[string]interface{}{"instance_type": map[string]interface{}{"exists": false}}, false},
+		{"absent false passes", map[string]interface{}{"tags.Owner": map[string]interface{}{"absent": false}}, true},
+		{"absent false fails", map[string]interface{}{"tags.CostCenter": map[string]interface{}{"absent": false}}, false},
+		{"length_gte passes", map[string]interface{}{"security_groups": map[string]interface{}{"length_gte": 2}}, true},
+		{"length_gte fails", map[string]interface{}{"name": map[string]interface{}{"length_gte": 20}}, false},
+		{"missing property", map[string]interface{}{"storage_encrypted": map[string]interface{}{"in": []interface{}{true}}}, false},
+		{"plain equality", map[string]interface{}{"instance_type": "t3.micro"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule := cloud.ValidationRule{Name: "operators", Severity: "error", Enabled: true, Conditions: tt.conditions}
+			result := engine.EvaluateRule(rule, resource)
+			assert.Equal(t, tt.shouldPass, result.Passed, result.Details)
+			if !tt.shouldPass {
+				assert.NotEmpty(t, result.Details)
+			}
+		})
+	}
+}
+
+func TestRulesEngine_Composition(t *testing.T) {
+	engine := &Engine{policy: &Policy{}}
+
+	rule := cloud.ValidationRule{
+		Name:     "encrypted-and-private",
+		Severity: "error",
+		Enabled:  true,
+		Conditions: map[string]interface{}{
+			"any": []interface{}{
+				map[string]interface{}{"sse_algorithm": "aws:kms"},
+				map[string]interface{}{"customer_key_id": map[string]interface{}{"exists": true}},
+			},
+			"not": map[string]interface{}{
+				"acl": map[string]interface{}{"in": []interface{}{"public-read", "public-read-write"}},
+			},
+		},
+	}
+
+	tests := []struct {
+		name       string
+		resource   map[string]interface{}
+		shouldPass bool
+	}{
+		{"kms and private", map[string]interface{}{"sse_algorithm": "aws:kms", "acl": "private"}, true},
+		{"customer key and private", map[string]interface{}{"customer_key_id": "key-1", "acl": "private"}, true},
+		{"unencrypted", map[string]interface{}{"sse_algorithm": "AES256", "acl": "private"}, false},
+		{"kms but public", map[string]interface{}{"sse_algorithm": "aws:kms", "acl": "public-read"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := engine.EvaluateRule(rule, tt.resource)
+			assert.Equal(t, tt.shouldPass, result.Passed, result.Details)
+		})
+	}
+
+	allRule := cloud.ValidationRule{
+		Name: "all-block",
+		Conditions: map[string]interface{}{
+			"all": []interface{}{
+				map[string]interface{}{"instance_type": "t3.micro"},
+				map[string]interface{}{"monitoring": true},
+			},
+		},
+	}
+	assert.True(t, engine.EvaluateRule(allRule, map[string]interface{}{"instance_type": "t3.micro", "monitoring": true}).Passed)
+	assert.False(t, engine.EvaluateRule(allRule, map[string]interface{}{"instance_type": "t3.micro", "monitoring": false}).Passed)
+}
+
+func TestRulesEngine_DeterministicDetails(t *testing.T) {
+	engine := &Engine{policy: &Policy{}}
+
+	rule := cloud.ValidationRule{
+		Name: "deterministic",
+		Conditions: map[string]interface{}{
+			"zone":          "a",
+			"instance_type": "t3.micro",
+			"monitoring":    true,
+		},
+	}
+
+	for i := 0; i < 20; i++ {
+		result := engine.EvaluateRule(rule, map[string]interface{}{})
+		assert.Equal(t, []string{"Property 'instance_type' not found"}, result.Details)
+	}
+}
+
+func TestRulesEngine_PropertyPaths(t *testing.T) {
+	engine := &Engine{policy: &Policy{}}
+
+	resource := map[string]interface{}{
+		"server_side_encryption_configuration": []interface{}{
+			map[string]interface{}{
+				"rule": []interface{}{
+					map[string]interface{}{
+						"apply_server_side_encryption_by_default": []interface{}{
+							map[string]interface{}{"sse_algorithm": "aws:kms"},
+						},
+					},
+				},
+			},
+		},
+		"ingress": []interface{}{
+			map[string]interface{}{"from_port": float64(443), "cidr_blocks": []interface{}{"10.0.0.0/8"}},
+			map[string]interface{}{"from_port": float64(22), "cidr_blocks": []interface{}{"0.0.0.0/0"}},
+		},
+		"egress": []interface{}{},
+	}
+
+	tests := []struct {
+		name       string
+		conditions map[string]interface{}
+		shouldPass bool
+	}{
+		{"nested index", map[string]interface{}{
+			"server_side_encryption_configuration[0].rule[0].apply_server_side_encryption_by_default[0].sse_algorithm": "aws:kms",
+		}, true},
+		{"index out of range", map[string]interface{}{
+			"server_side_encryption_configuration[1].rule": map[string]interface{}{"exists": true},
+		}, false},
+		{"wildcard all fails", map[string]interface{}{
+			"ingress[*].cidr_blocks": map[string]interface{}{"contains": "10.0.0.0/8"},
+		}, false},
+		{"wildcard all passes", map[string]interface{}{
+			"ingress[all].from_port": map[string]interface{}{"gt": 0},
+		}, true},
+		{"any element matches", map[string]interface{}{
+			"ingress[any].from_port": 22,
+		}, true},
+		{"no element matches", map[string]interface{}{
+			"ingress[any].from_port": 3389,
+		}, false},
+		{"negated any", map[string]interface{}{
+			"not": map[string]interface{}{
+				"ingress[any].cidr_blocks": map[string]interface{}{"contains": "0.0.0.0/0"},
+			},
+		}, false},
+		{"all over empty list", map[string]interface{}{
+			"egress[*].cidr_blocks": map[string]interface{}{"contains": "0.0.0.0/0"},
+		}, true},
+		{"any over empty list", map[string]interface{}{
+			"egress[any].cidr_blocks": map[string]interface{}{"exists": true},
+		}, false},
+		{"wildcard on non-list", map[string]interface{}{
+			"server_side_encryption_configuration[0][*]": map[string]interface{}{"exists": true},
+		}, false},
+		{"invalid index", map[string]interface{}{
+			"ingress[first].from_port": 22,
+		}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule := cloud.ValidationRule{Name: "paths", Severity: "error", Enabled: true, Conditions: tt.conditions}
+			result := engine.EvaluateRule(rule, resource)
+			assert.Equal(t, tt.shouldPass, result.Passed, result.Details)
+		})
+	}
+
+	rule := cloud.ValidationRule{Conditions: map[string]interface{}{
+		"ingress[*].cidr_blocks": map[string]interface{}{"contains": "10.0.0.0/8"},
+	}}
+	result := engine.EvaluateRule(rule, resource)
+	assert.Equal(t, []string{"Property 'ingress[1].cidr_blocks' has value '[0.0.0.0/0]', which does not contain '10.0.0.0/8'"}, result.Details)
+}
@@ -0,0 +1,180 @@
+package rules
+
+import (
+	"fmt"
+
+	"github.com/google/cel-go/cel"
+	"github.com/vijayaxai/terraship/internal/cloud"
+)
+
+// EvalContext carries plan information available to expr conditions in
+// addition to the resource values
+type EvalContext struct {
+	Resource map[string]interface{} // address, type, name, mode, provider_name
+	Plan     map[string]interface{} // format_version, terraform_version, variables
+	Actions  []string               // planned change actions, e.g. ["create"]
+}
+
+// newExprEnv declares the variables an expr condition can reference:
+//
+//	values   - the resource's planned attribute values
+//	resource - address, type, name, mode and provider_name
+//	plan     - format_version, terraform_version and variables
+//	actions  - the planned change actions for the resource
+func newExprEnv() (*cel.Env, error) {
+	return cel.NewEnv(
+		cel.Variable("values", cel.MapType(cel.StringType, cel.DynType)),
+		cel.Variable("resource", cel.MapType(cel.StringType, cel.DynType)),
+		cel.Variable("plan", cel.MapType(cel.StringType, cel.DynType)),
+		cel.Variable("actions", cel.ListType(cel.StringType)),
+	)
+}
+
+// compileExpr parses and type-checks an expression, which must evaluate to a bool
+func compileExpr(env *cel.Env, expression string) (cel.Program, error) {
+	ast, issues := env.Compile(expression)
+	if issues != nil && issues.Err() != nil {
+		return nil, issues.Err()
+	}
+
+	if outputType := ast.OutputType(); outputType != cel.BoolType && outputType != cel.DynType {
+		return nil, fmt.Errorf("expression must evaluate to bool, got %s", outputType)
+	}
+
+	return env.Program(ast)
+}
+
+// compileRuleExprs compiles every expr condition in the policy, including
+// those nested in all/any/not blocks, so a broken expression fails at load time
+func (e *Engine) compileRuleExprs() error {
+	env, err := newExprEnv()
+	if err != nil {
+		return fmt.Errorf("failed to create expression environment: %w", err)
+	}
+
+	e.exprEnv = env
+	e.exprs = make(map[string]cel.Program)
+
+	var walk func(conditions map[string]interface{}) error
+	walk = func(conditions map[string]interface{}) error {
+		for condition, expected := range conditions {
+			switch condition {
+			case "expr":
+				expression, ok := expected.(string)
+				if !ok {
+					return fmt.Errorf("expr must be a string, got %v", expected)
+				}
+				program, err := compileExpr(env, expression)
+				if err != nil {
+					return fmt.Errorf("invalid expr %q: %w", expression, err)
+				}
+				e.exprs[expression] = program
+
+			case "all", "any", "not":
+				blocks, ok := conditionBlocks(expected)
+				if !ok {
+					continue
+				}
+				for _, block := range blocks {
+					if err := walk(block); err != nil {
+						return err
+					}
+				}
+			}
+		}
+		return nil
+	}
+
+	for _, rule := range e.policy.Rules {
+		if err := walk(rule.Conditions); err != nil {
+			return fmt.Errorf("rule %s: %w", rule.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// exprProgram returns the compiled program of an expression. Engines built
+// without NewEngine compile expressions on first use.
+func (e *Engine) exprProgram(expression string) (cel.Program, error) {
+	e.exprMu.Lock()
+	defer e.exprMu.Unlock()
+
+	if program, ok := e.exprs[expression]; ok {
+		return program, nil
+	}
+
+	if e.exprEnv == nil {
+		exprEnv, err := newExprEnv()
+		if err != nil {
+			return nil, fmt.Errorf("failed to create expression environment: %w", err)
+		}
+		e.exprEnv = exprEnv
+	}
+
+	program, err := compileExpr(e.exprEnv, expression)
+	if err != nil {
+		return nil, fmt.Errorf("invalid expression '%s': %w", expression, err)
+	}
+	if e.exprs == nil {
+		e.exprs = make(map[string]cel.Program)
+	}
+	e.exprs[expression] = program
+	return program, nil
+}
+
+// checkExpr evaluates a CEL expression against the resource and its plan context
+func (e *Engine) checkExpr(expected interface{}, resource map[string]interface{}, env *EvalContext, result *cloud.ValidationResult) bool {
+	expression, ok := expected.(string)
+	if !ok {
+		result.Details = append(result.Details, "Invalid expr configuration: expected a string")
+		return false
+	}
+
+	program, err := e.exprProgram(expression)
+	if err != nil {
+		result.Details = append(result.Details, fmt.Sprintf("Expression error: %s", err))
+		return false
+	}
+
+	if env == nil {
+		env = &EvalContext{}
+	}
+	activation := map[string]interface{}{
+		"values":   nonNilMap(resource),
+		"resource": nonNilMap(env.Resource),
+		"plan":     nonNilMap(env.Plan),
+		"actions":  nonNilSlice(env.Actions),
+	}
+
+	out, _, err := program.Eval(activation)
+	if err != nil {
+		result.Details = append(result.Details, fmt.Sprintf("Expression '%s' could not be evaluated: %s", expression, err))
+		return false
+	}
+
+	passed, ok := out.Value().(bool)
+	if !ok {
+		result.Details = append(result.Details, fmt.Sprintf("Expression '%s' returned non-bool value '%v'", expression, out.Value()))
+		return false
+	}
+	if !passed {
+		result.Details = append(result.Details, fmt.Sprintf("Expression '%s' evaluated to false", expression))
+	}
+
+	return passed
+}
+
+func nonNilMap(m map[string]interface{}) map[string]interface{} {
+	if m == nil {
+		return map[string]interface{}{}
+	}
+	return m
+}
+
+func nonNilSlice(s []string) []string {
+	if s == nil {
+		return []string{}
+	}
+	return s
+}
@@ -0,0 +1,87 @@
+package rules
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vijayaxai/terraship/internal/cloud"
+)
+
+func TestRulesEngine_Expr(t *testing.T) {
+	engine := &Engine{policy: &Policy{}}
+
+	resource := map[string]interface{}{
+		"instance_type": "t3.micro",
+		"ingress": []interface{}{
+			map[string]interface{}{"from_port": float64(443), "cidr_blocks": []interface{}{"10.0.0.0/8"}},
+			map[string]interface{}{"from_port": float64(22), "cidr_blocks": []interface{}{"0.0.0.0/0"}},
+		},
+	}
+	env := &EvalContext{
+		Resource: map[string]interface{}{"address": "aws_instance.web", "type": "aws_instance"},
+		Plan:     map[string]interface{}{"variables": map[string]interface{}{"environment": "prod"}},
+		Actions:  []string{"create"},
+	}
+
+	tests := []struct {
+		name       string
+		expr       string
+		shouldPass bool
+	}{
+		{"values", `values.instance_type.startsWith("t3.")`, true},
+		{"macro over list", `!values.ingress.exists(r, r.from_port == 22.0 && "0.0.0.0/0" in r.cidr_blocks)`, false},
+		{"plan variables", `plan.variables.environment != "prod" || values.instance_type != "t3.micro"`, false},
+		{"actions", `!("delete" in actions)`, true},
+		{"resource address", `resource.address == "aws_instance.web"`, true},
+		{"missing key", `values.missing == "x"`, false},
+		{"has guard", `!has(values.missing) || values.missing == "x"`, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule := cloud.ValidationRule{
+				Name:       "expr-rule",
+				Severity:   "error",
+				Conditions: map[string]interface{}{"expr": tt.expr},
+			}
+			result := engine.EvaluateRuleWithContext(rule, resource, env)
+			assert.Equal(t, tt.shouldPass, result.Passed, "details: %v", result.Details)
+			if !tt.shouldPass {
+				assert.NotEmpty(t, result.Details)
+			}
+		})
+	}
+
+	// Without a context, plan and resource are empty maps
+	result := engine.EvaluateRule(cloud.ValidationRule{
+		Conditions: map[string]interface{}{"expr": `size(actions) == 0 && size(plan) == 0`},
+	}, resource)
+	assert.True(t, result.Passed, "details: %v", result.Details)
+}
+
+func TestNewEngine_InvalidExpr(t *testing.T) {
+	tests := []struct {
+		name string
+		expr string
+	}{
+		{"syntax error", `values.name ==`},
+		{"undeclared variable", `tags.owner == "x"`},
+		{"non-bool result", `size(values)`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			policy := "version: \"1.0\"\nrules:\n  - name: nested\n    enabled: true\n    conditions:\n      any:\n        - expr: '" + tt.expr + "'\n"
+			path := filepath.Join(dir, "policy.yml")
+			require.NoError(t, os.WriteFile(path, []byte(policy), 0644))
+
+			_, err := NewEngine(path)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), "rule nested")
+		})
+	}
+}
@@ -0,0 +1,227 @@
+package rules
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// operatorFunc compares a property value against an operator argument.
+// present reports whether the property exists on the resource; value is nil
+// when it does not. It returns whether the check passed and, on failure, a
+// detail message describing why.
+type operatorFunc func(value interface{}, present bool, arg interface{}) (bool, string)
+
+// operators maps the operator names usable in rule conditions to their
+// implementations, e.g.
+//
+//	conditions:
+//	  backup_retention_period:
+//	    gte: 7
+var operators = map[string]operatorFunc{
+	"gt":         numericOperator(">", func(a, b float64) bool { return a > b }),
+	"gte":        numericOperator(">=", func(a, b float64) bool { return a >= b }),
+	"lt":         numericOperator("<", func(a, b float64) bool { return a < b }),
+	"lte":        numericOperator("<=", func(a, b float64) bool { return a <= b }),
+	"in":         inOperator(true),
+	"not_in":     inOperator(false),
+	"matches":    matchesOperator,
+	"contains":   containsOperator,
+	"exists":     existsOperator(true),
+	"absent":     existsOperator(false),
+	"length_gte": lengthGteOperator,
+}
+
+// asOperators returns the operator map of a condition when every key of the
+// expected value names a known operator
+func asOperators(expected interface{}) (map[string]interface{}, bool) {
+	ops, ok := expected.(map[string]interface{})
+	if !ok || len(ops) == 0 {
+		return nil, false
+	}
+
+	for name := range ops {
+		if _, known := operators[name]; !known {
+			return nil, false
+		}
+	}
+
+	return ops, true
+}
+
+// sortedOperatorNames returns operator names in a stable order so failure
+// details are deterministic
+func sortedOperatorNames(ops map[string]interface{}) []string {
+	names := make([]string, 0, len(ops))
+	for name := range ops {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func numericOperator(symbol string, compare func(a, b float64) bool) operatorFunc {
+	return func(value interface{}, present bool, arg interface{}) (bool, string) {
+		if !present {
+			return false, "not found"
+		}
+
+		actual, ok := toFloat(value)
+		if !ok {
+			return false, fmt.Sprintf("has non-numeric value '%v'", value)
+		}
+
+		limit, ok := toFloat(arg)
+		if !ok {
+			return false, fmt.Sprintf("cannot be compared with non-numeric '%v'", arg)
+		}
+
+		if !compare(actual, limit) {
+			return false, fmt.Sprintf("has value '%v', expected %s %v", value, symbol, arg)
+		}
+		return true, ""
+	}
+}
+
+func inOperator(wantMember bool) operatorFunc {
+	return func(value interface{}, present bool, arg interface{}) (bool, string) {
+		allowed, ok := arg.([]interface{})
+		if !ok {
+			allowed = []interface{}{arg}
+		}
+
+		if !present {
+			if wantMember {
+				return false, "not found"
+			}
+			return true, ""
+		}
+
+		member := false
+		for _, candidate := range allowed {
+			if fmt.Sprint(candidate) == fmt.Sprint(value) {
+				member = true
+				break
+			}
+		}
+
+		if member != wantMember {
+			if wantMember {
+				return false, fmt.Sprintf("has value '%v', expected one of %v", value, allowed)
+			}
+			return false, fmt.Sprintf("has value '%v', which is not allowed %v", value, allowed)
+		}
+		return true, ""
+	}
+}
+
+func matchesOperator(value interface{}, present bool, arg interface{}) (bool, string) {
+	if !present {
+		return false, "not found"
+	}
+
+	pattern := fmt.Sprint(arg)
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return false, fmt.Sprintf("cannot be checked: invalid regex pattern: %s", err)
+	}
+
+	if !re.MatchString(fmt.Sprint(value)) {
+		return false, fmt.Sprintf("has value '%v', which does not match pattern '%s'", value, pattern)
+	}
+	return true, ""
+}
+
+func containsOperator(value interface{}, present bool, arg interface{}) (bool, string) {
+	if !present {
+		return false, "not found"
+	}
+
+	switch actual := value.(type) {
+	case string:
+		if strings.Contains(actual, fmt.Sprint(arg)) {
+			return true, ""
+		}
+	case []interface{}:
+		for _, item := range actual {
+			if fmt.Sprint(item) == fmt.Sprint(arg) {
+				return true, ""
+			}
+		}
+	case map[string]interface{}:
+		if _, ok := actual[fmt.Sprint(arg)]; ok {
+			return true, ""
+		}
+	}
+
+	return false, fmt.Sprintf("has value '%v', which does not contain '%v'", value, arg)
+}
+
+func existsOperator(wantPresent bool) operatorFunc {
+	return func(value interface{}, present bool, arg interface{}) (bool, string) {
+		enabled, ok := arg.(bool)
+		if !ok {
+			return false, fmt.Sprintf("cannot be checked: expected true or false, got '%v'", arg)
+		}
+		// exists: false means absent: true, and absent: false exists: true
+		want := wantPresent == enabled
+
+		if present != want {
+			if want {
+				return false, "not found"
+			}
+			return false, fmt.Sprintf("is set to '%v', expected it to be absent", value)
+		}
+		return true, ""
+	}
+}
+
+func lengthGteOperator(value interface{}, present bool, arg interface{}) (bool, string) {
+	if !present {
+		return false, "not found"
+	}
+
+	minLength, ok := toFloat(arg)
+	if !ok {
+		return false, fmt.Sprintf("cannot be compared with non-numeric length '%v'", arg)
+	}
+
+	var length int
+	switch actual := value.(type) {
+	case string:
+		length = len(actual)
+	case []interface{}:
+		length = len(actual)
+	case map[string]interface{}:
+		length = len(actual)
+	default:
+		return false, fmt.Sprintf("has value '%v', which has no length", value)
+	}
+
+	if float64(length) < minLength {
+		return false, fmt.Sprintf("has length %d, expected at least %v", length, arg)
+	}
+	return true, ""
+}
+
+// toFloat converts numeric values decoded from YAML or JSON, including
+// numeric strings, to float64
+func toFloat(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case float64:
+		return v, true
+	case float32:
+		return float64(v), true
+	case string:
+		f, err := strconv.ParseFloat(v, 64)
+		return f, err == nil
+	default:
+		return 0, false
+	}
+}
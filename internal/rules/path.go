@@ -0,0 +1,153 @@
+package rules
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// segmentKind identifies how a property path segment selects a value
+type segmentKind int
+
+const (
+	segmentKey   segmentKind = iota // map key, e.g. rule
+	segmentIndex                    // list index, e.g. [0]
+	segmentAll                      // every list element must match, [*] or [all]
+	segmentAny                      // at least one list element must match, [any]
+)
+
+// pathSegment is a single step of a parsed property path
+type pathSegment struct {
+	kind  segmentKind
+	key   string
+	index int
+}
+
+// valueCheck tests the value a property path resolves to. present is false
+// when the path does not exist on the resource. On failure it returns a detail
+// message that is prefixed with the concrete property path.
+type valueCheck func(value interface{}, present bool) (bool, string)
+
+// parsePropertyPath parses a property path such as
+// server_side_encryption_configuration[0].rule[*].sse_algorithm
+func parsePropertyPath(path string) ([]pathSegment, error) {
+	var segments []pathSegment
+
+	for _, part := range strings.Split(path, ".") {
+		name := part
+		brackets := ""
+		if i := strings.Index(part, "["); i >= 0 {
+			name, brackets = part[:i], part[i:]
+		}
+
+		if name == "" {
+			return nil, fmt.Errorf("invalid property path '%s': empty key", path)
+		}
+		segments = append(segments, pathSegment{kind: segmentKey, key: name})
+
+		for brackets != "" {
+			end := strings.Index(brackets, "]")
+			if brackets[0] != '[' || end < 0 {
+				return nil, fmt.Errorf("invalid property path '%s': malformed index in '%s'", path, part)
+			}
+
+			selector := brackets[1:end]
+			brackets = brackets[end+1:]
+
+			switch selector {
+			case "*", "all":
+				segments = append(segments, pathSegment{kind: segmentAll})
+			case "any":
+				segments = append(segments, pathSegment{kind: segmentAny})
+			default:
+				index, err := strconv.Atoi(selector)
+				if err != nil || index < 0 {
+					return nil, fmt.Errorf("invalid property path '%s': unsupported index '[%s]'", path, selector)
+				}
+				segments = append(segments, pathSegment{kind: segmentIndex, index: index})
+			}
+		}
+	}
+
+	return segments, nil
+}
+
+// evaluatePath walks segments from current and applies check to the value(s)
+// they select. Quantifier segments fan out over list elements: [*] requires
+// every element to pass and [any] requires at least one.
+func evaluatePath(current interface{}, segments []pathSegment, concrete string, check valueCheck) (bool, []string) {
+	if len(segments) == 0 {
+		ok, detail := check(current, true)
+		if ok {
+			return true, nil
+		}
+		return false, []string{fmt.Sprintf("Property '%s' %s", concrete, detail)}
+	}
+
+	segment := segments[0]
+	rest := segments[1:]
+
+	switch segment.kind {
+	case segmentKey:
+		next := segment.key
+		if concrete != "" {
+			next = concrete + "." + segment.key
+		}
+
+		values, ok := current.(map[string]interface{})
+		if !ok {
+			return evaluateMissing(next, check)
+		}
+		value, exists := values[segment.key]
+		if !exists {
+			return evaluateMissing(next, check)
+		}
+		return evaluatePath(value, rest, next, check)
+
+	case segmentIndex:
+		next := fmt.Sprintf("%s[%d]", concrete, segment.index)
+
+		items, ok := current.([]interface{})
+		if !ok || segment.index >= len(items) {
+			return evaluateMissing(next, check)
+		}
+		return evaluatePath(items[segment.index], rest, next, check)
+
+	default:
+		items, ok := current.([]interface{})
+		if !ok {
+			return false, []string{fmt.Sprintf("Property '%s' is not a list", concrete)}
+		}
+
+		var details []string
+		matched := 0
+		for i, item := range items {
+			passed, itemDetails := evaluatePath(item, rest, fmt.Sprintf("%s[%d]", concrete, i), check)
+			if passed {
+				matched++
+			}
+			details = append(details, itemDetails...)
+		}
+
+		if segment.kind == segmentAll {
+			return matched == len(items), details
+		}
+
+		if matched > 0 {
+			return true, nil
+		}
+		if len(items) == 0 {
+			return false, []string{fmt.Sprintf("Property '%s' has no elements", concrete)}
+		}
+		return false, append([]string{fmt.Sprintf("No element of '%s' matched", concrete)}, details...)
+	}
+}
+
+// evaluateMissing applies check to a property that does not exist
+func evaluateMissing(concrete string, check valueCheck) (bool, []string) {
+	ok, detail := check(nil, false)
+	if ok {
+		return true, nil
+	}
+	return false, []string{fmt.Sprintf("Property '%s' %s", concrete, detail)}
+}
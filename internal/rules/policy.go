@@ -0,0 +1,311 @@
+package rules
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/vijayaxai/terraship/internal/cloud"
+	"gopkg.in/yaml.v3"
+)
+
+// PolicyRefs lists the policy files a policy extends. It accepts a single
+// path or a list of paths.
+type PolicyRefs []string
+
+// UnmarshalYAML accepts both `extends: base.yml` and `extends: [a.yml, b.yml]`
+func (r *PolicyRefs) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind == yaml.ScalarNode {
+		*r = PolicyRefs{value.Value}
+		return nil
+	}
+
+	var refs []string
+	if err := value.Decode(&refs); err != nil {
+		return err
+	}
+	*r = refs
+	return nil
+}
+
+// RuleOverride changes the severity or enabled state of an inherited rule
+type RuleOverride struct {
+	Name     string `yaml:"name"`
+	Severity string `yaml:"severity"`
+	Enabled  *bool  `yaml:"enabled"`
+}
+
+// sourcedRule is a rule together with the policy file that defines it
+type sourcedRule struct {
+	rule   cloud.ValidationRule
+	source string
+}
+
+// loadedPolicy is a policy file with its extends chain resolved
+type loadedPolicy struct {
+	policy    Policy
+	rules     []sourcedRule
+	ignores   []DriftIgnore   // drift ignores of the file and the files it extends
+	ancestors map[string]bool // absolute paths of every extended policy file
+	files     []string        // the file and every file it extends, extended files first
+}
+
+// ResolvePolicyPaths expands policy arguments into the policy files they
+// name. An argument may be a file, a directory (its *.yml and *.yaml files)
+// or a glob pattern. The result is de-duplicated and keeps argument order.
+func ResolvePolicyPaths(paths []string) ([]string, error) {
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("no policy path specified")
+	}
+
+	var files []string
+	seen := make(map[string]bool)
+	add := func(file string) {
+		if !seen[file] {
+			seen[file] = true
+			files = append(files, file)
+		}
+	}
+
+	for _, path := range paths {
+		matches, err := expandPolicyPath(path)
+		if err != nil {
+			return nil, err
+		}
+		for _, match := range matches {
+			add(filepath.Clean(match))
+		}
+	}
+
+	return files, nil
+}
+
+// expandPolicyPath expands a single policy argument into sorted policy files
+func expandPolicyPath(path string) ([]string, error) {
+	if strings.ContainsAny(path, "*?[") {
+		matches, err := filepath.Glob(path)
+		if err != nil {
+			return nil, fmt.Errorf("invalid policy pattern %s: %w", path, err)
+		}
+		if len(matches) == 0 {
+			return nil, fmt.Errorf("policy pattern %s matched no files", path)
+		}
+		sort.Strings(matches)
+		return matches, nil
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read policy file: %w", err)
+	}
+	if !info.IsDir() {
+		return []string{path}, nil
+	}
+
+	var matches []string
+	for _, pattern := range []string{"*.yml", "*.yaml"} {
+		found, err := filepath.Glob(filepath.Join(path, pattern))
+		if err != nil {
+			return nil, fmt.Errorf("failed to list policy directory: %w", err)
+		}
+		matches = append(matches, found...)
+	}
+	if len(matches) == 0 {
+		return nil, fmt.Errorf("policy directory %s contains no policy files", path)
+	}
+	sort.Strings(matches)
+	return matches, nil
+}
+
+// loadPolicies loads and merges the given policy files. A file that another
+// file already extends is skipped so its rules are only included once, with
+// the extending file's overrides applied. It also returns every policy file
+// loaded, including those reached through extends.
+func loadPolicies(files []string) (*Policy, []string, error) {
+	loaded := make([]*loadedPolicy, 0, len(files))
+	absPaths := make([]string, 0, len(files))
+	for _, file := range files {
+		abs, err := filepath.Abs(file)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to resolve policy path: %w", err)
+		}
+		policy, err := loadPolicyFile(abs, nil)
+		if err != nil {
+			return nil, nil, err
+		}
+		loaded = append(loaded, policy)
+		absPaths = append(absPaths, abs)
+	}
+
+	merged := &Policy{}
+	var rules []sourcedRule
+	var sources []string
+	for i, policy := range loaded {
+		sources = append(sources, policy.files...)
+		if isExtended(absPaths[i], loaded) {
+			continue
+		}
+
+		if merged.Name == "" {
+			merged.Version = policy.policy.Version
+			merged.Name = policy.policy.Name
+			merged.Description = policy.policy.Description
+		}
+
+		var err error
+		if rules, err = mergeRules(rules, policy.rules); err != nil {
+			return nil, nil, err
+		}
+		merged.Drift.Ignore = append(merged.Drift.Ignore, policy.ignores...)
+	}
+
+	for _, rule := range rules {
+		merged.Rules = append(merged.Rules, rule.rule)
+	}
+
+	return merged, sources, nil
+}
+
+// isExtended reports whether any of the loaded policies extends path
+func isExtended(path string, loaded []*loadedPolicy) bool {
+	for _, policy := range loaded {
+		if policy.ancestors[path] {
+			return true
+		}
+	}
+	return false
+}
+
+// loadPolicyFile reads a policy file and resolves its extends chain. Inherited
+// rules come first, then the file's own rules; overrides are applied last.
+// stack holds the files currently being loaded to detect cycles.
+func loadPolicyFile(path string, stack []string) (*loadedPolicy, error) {
+	for _, parent := range stack {
+		if parent == path {
+			return nil, fmt.Errorf("policy %s extends itself: %s", path, strings.Join(append(stack, path), " -> "))
+		}
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read policy file: %w", err)
+	}
+
+	var policy Policy
+	if err := yaml.Unmarshal(data, &policy); err != nil {
+		return nil, fmt.Errorf("failed to parse policy %s: %w", path, err)
+	}
+
+	loaded := &loadedPolicy{policy: policy, ancestors: make(map[string]bool)}
+
+	for _, ref := range policy.Extends {
+		parentPath := ref
+		if !filepath.IsAbs(parentPath) {
+			parentPath = filepath.Join(filepath.Dir(path), ref)
+		}
+
+		parent, err := loadPolicyFile(parentPath, append(stack, path))
+		if err != nil {
+			return nil, err
+		}
+
+		loaded.ancestors[parentPath] = true
+		for ancestor := range parent.ancestors {
+			loaded.ancestors[ancestor] = true
+		}
+
+		if loaded.rules, err = mergeRules(loaded.rules, parent.rules); err != nil {
+			return nil, err
+		}
+		loaded.ignores = append(loaded.ignores, parent.ignores...)
+		loaded.files = append(loaded.files, parent.files...)
+	}
+	loaded.ignores = append(loaded.ignores, policy.Drift.Ignore...)
+	loaded.files = append(loaded.files, path)
+
+	own := make([]sourcedRule, 0, len(policy.Rules))
+	names := make(map[string]bool, len(policy.Rules))
+	for _, rule := range policy.Rules {
+		if names[rule.Name] {
+			return nil, fmt.Errorf("duplicate rule %q in %s", rule.Name, path)
+		}
+		names[rule.Name] = true
+		own = append(own, sourcedRule{rule: rule, source: path})
+	}
+	if loaded.rules, err = mergeRules(loaded.rules, own); err != nil {
+		return nil, err
+	}
+
+	for _, override := range policy.Overrides {
+		if err := applyOverride(loaded.rules, override, path); err != nil {
+			return nil, err
+		}
+	}
+
+	return loaded, nil
+}
+
+// mergeRules appends rules to existing, rejecting duplicate rule names. The
+// same rule reached through two extends chains is kept once as long as both
+// copies are identical.
+func mergeRules(existing, rules []sourcedRule) ([]sourcedRule, error) {
+	index := make(map[string]int, len(existing))
+	for i, rule := range existing {
+		index[rule.rule.Name] = i
+	}
+
+	for _, rule := range rules {
+		i, exists := index[rule.rule.Name]
+		if !exists {
+			index[rule.rule.Name] = len(existing)
+			existing = append(existing, rule)
+			continue
+		}
+
+		current := existing[i]
+		if current.source != rule.source {
+			return nil, fmt.Errorf("duplicate rule %q defined in %s and %s", rule.rule.Name, current.source, rule.source)
+		}
+		if !reflect.DeepEqual(current.rule, rule.rule) {
+			return nil, fmt.Errorf("rule %q from %s is overridden differently by policies that extend it", rule.rule.Name, rule.source)
+		}
+	}
+
+	return existing, nil
+}
+
+// applyOverride changes the severity or enabled state of a rule
+func applyOverride(rules []sourcedRule, override RuleOverride, source string) error {
+	for i := range rules {
+		if rules[i].rule.Name != override.Name {
+			continue
+		}
+		if override.Severity != "" {
+			rules[i].rule.Severity = override.Severity
+		}
+		if override.Enabled != nil {
+			rules[i].rule.Enabled = *override.Enabled
+		}
+		return nil
+	}
+
+	return fmt.Errorf("policy %s overrides unknown rule %q", source, override.Name)
+}
+
+// policyDirs returns the directories of the policy files, including those
+// reached through extends, used to locate Rego modules
+func policyDirs(files []string) []string {
+	var dirs []string
+	seen := make(map[string]bool)
+	for _, file := range files {
+		dir := filepath.Dir(file)
+		if !seen[dir] {
+			seen[dir] = true
+			dirs = append(dirs, dir)
+		}
+	}
+	return dirs
+}
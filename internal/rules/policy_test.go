@@ -0,0 +1,162 @@
+package rules
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const basePolicy = `version: "1.0"
+name: "Org Baseline"
+rules:
+  - name: required-tags
+    severity: error
+    enabled: true
+    conditions:
+      tags.required: ["Owner"]
+  - name: versioning
+    severity: error
+    enabled: true
+    conditions:
+      versioning.enabled: true
+`
+
+func writePolicyFiles(t *testing.T, files map[string]string) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	}
+	return dir
+}
+
+func ruleNames(engine *Engine) []string {
+	var names []string
+	for _, rule := range engine.policy.Rules {
+		names = append(names, rule.Name)
+	}
+	return names
+}
+
+func TestNewEngine_MultiplePolicies(t *testing.T) {
+	dir := writePolicyFiles(t, map[string]string{
+		"org/base.yml":     basePolicy,
+		"team/network.yml": "rules:\n  - name: private-subnet\n    enabled: true\n    conditions:\n      network.private_subnet: true\n",
+		"team/cost.yaml":   "rules:\n  - name: instance-size\n    enabled: true\n    conditions:\n      instance_type: t3.micro\n",
+	})
+
+	engine, err := NewEngine(filepath.Join(dir, "org"), filepath.Join(dir, "team", "*.y*ml"))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"required-tags", "versioning", "instance-size", "private-subnet"}, ruleNames(engine))
+	assert.Equal(t, "Org Baseline", engine.policy.Name)
+
+	// The same file given twice is only loaded once
+	engine, err = NewEngine(filepath.Join(dir, "org", "base.yml"), filepath.Join(dir, "org"))
+	require.NoError(t, err)
+	assert.Len(t, engine.policy.Rules, 2)
+}
+
+func TestNewEngine_DuplicateRules(t *testing.T) {
+	dir := writePolicyFiles(t, map[string]string{
+		"a.yml": basePolicy,
+		"b.yml": "rules:\n  - name: versioning\n    enabled: false\n",
+	})
+
+	_, err := NewEngine(dir)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `duplicate rule "versioning"`)
+}
+
+func TestNewEngine_Extends(t *testing.T) {
+	dir := writePolicyFiles(t, map[string]string{
+		"org/base.yml": basePolicy,
+		"team/app.yml": `extends: ../org/base.yml
+rules:
+  - name: naming
+    enabled: true
+    conditions:
+      naming.pattern: "^app-"
+overrides:
+  - name: required-tags
+    severity: warning
+  - name: versioning
+    enabled: false
+`,
+	})
+
+	engine, err := NewEngine(filepath.Join(dir, "team", "app.yml"))
+	require.NoError(t, err)
+	require.Equal(t, []string{"required-tags", "versioning", "naming"}, ruleNames(engine))
+	assert.Equal(t, "warning", engine.policy.Rules[0].Severity)
+	assert.True(t, engine.policy.Rules[0].Enabled)
+	assert.False(t, engine.policy.Rules[1].Enabled)
+
+	// The extended baseline is skipped when both are loaded together
+	engine, err = NewEngine(filepath.Join(dir, "org"), filepath.Join(dir, "team"))
+	require.NoError(t, err)
+	assert.Len(t, engine.policy.Rules, 3)
+	assert.Equal(t, "warning", engine.policy.Rules[0].Severity)
+}
+
+func TestNewEngine_ExtendsErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		files  map[string]string
+		errMsg string
+	}{
+		{
+			name: "cycle",
+			files: map[string]string{
+				"policy.yml": "extends: other.yml\nrules: []\n",
+				"other.yml":  "extends: [policy.yml]\nrules: []\n",
+			},
+			errMsg: "extends itself",
+		},
+		{
+			name: "unknown override",
+			files: map[string]string{
+				"policy.yml": "overrides:\n  - name: missing\n    severity: info\n",
+			},
+			errMsg: `overrides unknown rule "missing"`,
+		},
+		{
+			name: "redefined inherited rule",
+			files: map[string]string{
+				"base.yml":   basePolicy,
+				"policy.yml": "extends: base.yml\nrules:\n  - name: versioning\n    enabled: false\n",
+			},
+			errMsg: `duplicate rule "versioning"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := writePolicyFiles(t, tt.files)
+			_, err := NewEngine(filepath.Join(dir, "policy.yml"))
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.errMsg)
+		})
+	}
+}
+
+func TestResolvePolicyPaths_Errors(t *testing.T) {
+	dir := t.TempDir()
+
+	_, err := ResolvePolicyPaths(nil)
+	assert.Error(t, err)
+
+	_, err = ResolvePolicyPaths([]string{dir})
+	assert.ErrorContains(t, err, "contains no policy files")
+
+	_, err = ResolvePolicyPaths([]string{filepath.Join(dir, "*.yml")})
+	assert.ErrorContains(t, err, "matched no files")
+
+	_, err = ResolvePolicyPaths([]string{filepath.Join(dir, "missing.yml")})
+	assert.Error(t, err)
+}
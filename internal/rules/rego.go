@@ -0,0 +1,224 @@
+package rules
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/open-policy-agent/opa/ast"
+	"github.com/open-policy-agent/opa/rego"
+	"github.com/vijayaxai/terraship/internal/cloud"
+)
+
+// resourceNamespace is the Rego package prefix evaluated once per resource.
+// Packages outside it (e.g. conftest's default "main") are evaluated once
+// against the whole plan or state document.
+const resourceNamespace = "terraship"
+
+// regoSeverities maps conftest-style rule names to result severities
+var regoSeverities = map[string]string{
+	"deny":      "error",
+	"violation": "error",
+	"warn":      "warning",
+}
+
+// regoPolicy is a Rego package prepared for evaluation
+type regoPolicy struct {
+	pkg   string // package path without the data. prefix, e.g. terraship.s3
+	query rego.PreparedEvalQuery
+}
+
+// PlanFinding is a Rego result produced from the whole plan or state. Address
+// is empty when the policy did not attribute the finding to a resource.
+type PlanFinding struct {
+	Address string
+	Result  cloud.ValidationResult
+}
+
+// loadRegoPolicies compiles every .rego module in dirs (test modules excluded)
+// and prepares one query per package
+func loadRegoPolicies(dirs []string) ([]regoPolicy, error) {
+	var files []string
+	for _, dir := range dirs {
+		matches, err := filepath.Glob(filepath.Join(dir, "*.rego"))
+		if err != nil {
+			return nil, fmt.Errorf("failed to list rego modules: %w", err)
+		}
+		files = append(files, matches...)
+	}
+
+	var options []func(*rego.Rego)
+	packages := make(map[string]bool)
+
+	for _, file := range files {
+		if strings.HasSuffix(file, "_test.rego") {
+			continue
+		}
+
+		src, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read rego module: %w", err)
+		}
+
+		module, err := ast.ParseModule(file, string(src))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse rego module: %w", err)
+		}
+
+		packages[strings.TrimPrefix(module.Package.Path.String(), "data.")] = true
+		options = append(options, rego.Module(file, string(src)))
+	}
+
+	names := make([]string, 0, len(packages))
+	for pkg := range packages {
+		names = append(names, pkg)
+	}
+	sort.Strings(names)
+
+	policies := make([]regoPolicy, 0, len(names))
+	for _, pkg := range names {
+		query, err := rego.New(append(options, rego.Query("data."+pkg))...).PrepareForEval(context.Background())
+		if err != nil {
+			return nil, fmt.Errorf("failed to compile rego package %s: %w", pkg, err)
+		}
+		policies = append(policies, regoPolicy{pkg: pkg, query: query})
+	}
+
+	return policies, nil
+}
+
+// isResourcePolicy reports whether the package is evaluated per resource
+func (p *regoPolicy) isResourcePolicy() bool {
+	return p.pkg == resourceNamespace || strings.HasPrefix(p.pkg, resourceNamespace+".")
+}
+
+// eval evaluates the package against input and returns its deny/violation/warn
+// results, and whether its applies_to rule holds for the input
+func (p *regoPolicy) eval(ctx context.Context, input interface{}) ([]PlanFinding, bool, error) {
+	// Round-trip through JSON so OPA sees the same document terraform show -json emits
+	data, err := json.Marshal(input)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to encode rego input: %w", err)
+	}
+	var doc interface{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, false, fmt.Errorf("failed to encode rego input: %w", err)
+	}
+
+	rs, err := p.query.Eval(ctx, rego.EvalInput(doc))
+	if err != nil {
+		return nil, false, fmt.Errorf("rego package %s evaluation failed: %w", p.pkg, err)
+	}
+
+	var findings []PlanFinding
+	var applies bool
+	if len(rs) > 0 && len(rs[0].Expressions) > 0 {
+		values, _ := rs[0].Expressions[0].Value.(map[string]interface{})
+		applies, _ = values["applies_to"].(bool)
+		for _, rule := range []string{"deny", "violation", "warn"} {
+			messages, _ := values[rule].([]interface{})
+			for _, message := range messages {
+				findings = append(findings, p.finding(rule, message))
+			}
+		}
+	}
+
+	sort.SliceStable(findings, func(i, j int) bool {
+		return findings[i].Result.Message < findings[j].Result.Message
+	})
+
+	return findings, applies, nil
+}
+
+// finding converts a deny/warn message into a validation result. Messages
+// may be plain strings or objects with msg, rule, severity, remediation and
+// resource (address) fields.
+func (p *regoPolicy) finding(rule string, message interface{}) PlanFinding {
+	finding := PlanFinding{Result: cloud.ValidationResult{
+		RuleName: p.pkg + "." + rule,
+		Severity: regoSeverities[rule],
+		Passed:   false,
+		Message:  fmt.Sprint(message),
+	}}
+
+	fields, ok := message.(map[string]interface{})
+	if !ok {
+		return finding
+	}
+
+	if msg, ok := fields["msg"].(string); ok {
+		finding.Result.Message = msg
+	}
+	if name, ok := fields["rule"].(string); ok && name != "" {
+		finding.Result.RuleName = name
+	}
+	if severity, ok := fields["severity"].(string); ok && severity != "" {
+		finding.Result.Severity = severity
+	}
+	if remediation, ok := fields["remediation"].(string); ok {
+		finding.Result.Remediation = remediation
+	}
+	if address, ok := fields["resource"].(string); ok {
+		finding.Address = address
+	}
+
+	return finding
+}
+
+// EvaluateRegoResource evaluates the per-resource Rego packages (terraship.*)
+// with the resource (typically a terraform.Resource) encoded as JSON input.
+// A package without findings yields a passing result only when its applies_to
+// rule holds for the resource, so resources the package does not check are not
+// counted as passing it.
+func (e *Engine) EvaluateRegoResource(ctx context.Context, resource interface{}) ([]cloud.ValidationResult, error) {
+	var results []cloud.ValidationResult
+
+	for i := range e.regoPolicies {
+		policy := &e.regoPolicies[i]
+		if !policy.isResourcePolicy() {
+			continue
+		}
+
+		findings, applies, err := policy.eval(ctx, resource)
+		if err != nil {
+			return nil, err
+		}
+		if len(findings) == 0 && applies {
+			results = append(results, cloud.ValidationResult{
+				RuleName: policy.pkg,
+				Severity: "error",
+				Passed:   true,
+			})
+		}
+		for _, finding := range findings {
+			results = append(results, finding.Result)
+		}
+	}
+
+	return results, nil
+}
+
+// EvaluateRegoPlan evaluates the plan-level Rego packages (conftest style, e.g.
+// package main) with the whole plan or state document as input
+func (e *Engine) EvaluateRegoPlan(ctx context.Context, document interface{}) ([]PlanFinding, error) {
+	var findings []PlanFinding
+
+	for i := range e.regoPolicies {
+		policy := &e.regoPolicies[i]
+		if policy.isResourcePolicy() {
+			continue
+		}
+
+		policyFindings, _, err := policy.eval(ctx, document)
+		if err != nil {
+			return nil, err
+		}
+		findings = append(findings, policyFindings...)
+	}
+
+	return findings, nil
+}
@@ -0,0 +1,187 @@
+package rules
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const regoResourcePolicy = `package terraship.s3
+
+applies_to {
+	input.type == "aws_s3_bucket"
+}
+
+deny[msg] {
+	input.type == "aws_s3_bucket"
+	input.values.acl == "public-read"
+	msg := {"msg": sprintf("%s must not be public", [input.address]), "rule": "s3-no-public-acl", "remediation": "Set acl to private"}
+}
+
+warn[msg] {
+	input.type == "aws_s3_bucket"
+	not input.values.tags.Owner
+	msg := "bucket has no Owner tag"
+}
+`
+
+const regoPlanPolicy = `package main
+
+deny[msg] {
+	rc := input.resource_changes[_]
+	rc.change.actions[_] == "delete"
+	msg := {"msg": sprintf("%s would be deleted", [rc.address]), "resource": rc.address}
+}
+
+deny[msg] {
+	count(input.resource_changes) > 1
+	msg := "plan changes more than one resource"
+}
+`
+
+func writeRegoPolicyDir(t *testing.T, modules map[string]string) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "policy.yml"), []byte("version: \"1.0\"\nrules: []\n"), 0644))
+	for name, src := range modules {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(src), 0644))
+	}
+	return filepath.Join(dir, "policy.yml")
+}
+
+func TestEngine_EvaluateRegoResource(t *testing.T) {
+	engine, err := NewEngine(writeRegoPolicyDir(t, map[string]string{
+		"s3.rego":      regoResourcePolicy,
+		"s3_test.rego": "package terraship.s3\n\ntest_ignored { true }\n",
+	}))
+	require.NoError(t, err)
+
+	ctx := context.Background()
+
+	results, err := engine.EvaluateRegoResource(ctx, map[string]interface{}{
+		"address": "aws_s3_bucket.logs",
+		"type":    "aws_s3_bucket",
+		"values":  map[string]interface{}{"acl": "public-read"},
+	})
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+
+	assert.Equal(t, "aws_s3_bucket.logs must not be public", results[0].Message)
+	assert.Equal(t, "s3-no-public-acl", results[0].RuleName)
+	assert.Equal(t, "error", results[0].Severity)
+	assert.Equal(t, "Set acl to private", results[0].Remediation)
+	assert.False(t, results[0].Passed)
+
+	assert.Equal(t, "terraship.s3.warn", results[1].RuleName)
+	assert.Equal(t, "warning", results[1].Severity)
+
+	results, err = engine.EvaluateRegoResource(ctx, map[string]interface{}{
+		"address": "aws_s3_bucket.private",
+		"type":    "aws_s3_bucket",
+		"values":  map[string]interface{}{"acl": "private", "tags": map[string]interface{}{"Owner": "platform"}},
+	})
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.True(t, results[0].Passed)
+	assert.Equal(t, "terraship.s3", results[0].RuleName)
+
+	// Resources the package does not apply to do not pass it
+	results, err = engine.EvaluateRegoResource(ctx, map[string]interface{}{
+		"address": "aws_iam_role.deploy",
+		"type":    "aws_iam_role",
+		"values":  map[string]interface{}{"name": "deploy"},
+	})
+	require.NoError(t, err)
+	assert.Empty(t, results)
+}
+
+func TestEngine_EvaluateRegoResourceWithoutAppliesTo(t *testing.T) {
+	engine, err := NewEngine(writeRegoPolicyDir(t, map[string]string{
+		"tags.rego": `package terraship.tags
+
+deny[msg] {
+	not input.values.tags.Owner
+	msg := sprintf("%s has no Owner tag", [input.address])
+}
+`,
+	}))
+	require.NoError(t, err)
+
+	ctx := context.Background()
+
+	// Without applies_to only findings are reported
+	results, err := engine.EvaluateRegoResource(ctx, map[string]interface{}{
+		"address": "aws_s3_bucket.logs",
+		"type":    "aws_s3_bucket",
+		"values":  map[string]interface{}{"tags": map[string]interface{}{"Owner": "platform"}},
+	})
+	require.NoError(t, err)
+	assert.Empty(t, results)
+
+	results, err = engine.EvaluateRegoResource(ctx, map[string]interface{}{
+		"address": "aws_s3_bucket.data",
+		"type":    "aws_s3_bucket",
+		"values":  map[string]interface{}{},
+	})
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.False(t, results[0].Passed)
+	assert.Equal(t, "aws_s3_bucket.data has no Owner tag", results[0].Message)
+}
+
+func TestEngine_EvaluateRegoPlan(t *testing.T) {
+	engine, err := NewEngine(writeRegoPolicyDir(t, map[string]string{"plan.rego": regoPlanPolicy}))
+	require.NoError(t, err)
+
+	plan := map[string]interface{}{
+		"resource_changes": []interface{}{
+			map[string]interface{}{"address": "aws_s3_bucket.logs", "change": map[string]interface{}{"actions": []interface{}{"delete"}}},
+			map[string]interface{}{"address": "aws_s3_bucket.data", "change": map[string]interface{}{"actions": []interface{}{"create"}}},
+		},
+	}
+
+	findings, err := engine.EvaluateRegoPlan(context.Background(), plan)
+	require.NoError(t, err)
+	require.Len(t, findings, 2)
+
+	assert.Equal(t, "aws_s3_bucket.logs", findings[0].Address)
+	assert.Equal(t, "aws_s3_bucket.logs would be deleted", findings[0].Result.Message)
+	assert.Equal(t, "", findings[1].Address)
+	assert.Equal(t, "main.deny", findings[1].Result.RuleName)
+
+	// Resource-level packages are not evaluated against the plan
+	results, err := engine.EvaluateRegoResource(context.Background(), map[string]interface{}{"type": "aws_s3_bucket"})
+	require.NoError(t, err)
+	assert.Empty(t, results)
+}
+
+func TestNewEngine_ExtendedRego(t *testing.T) {
+	dir := writePolicyFiles(t, map[string]string{
+		"org/base.yml": basePolicy,
+		"org/s3.rego":  regoResourcePolicy,
+		"team/app.yml": "extends: ../org/base.yml\n",
+	})
+
+	// Rego modules next to an extended policy are loaded too
+	engine, err := NewEngine(filepath.Join(dir, "team", "app.yml"))
+	require.NoError(t, err)
+
+	results, err := engine.EvaluateRegoResource(context.Background(), map[string]interface{}{
+		"address": "aws_s3_bucket.logs",
+		"type":    "aws_s3_bucket",
+		"values":  map[string]interface{}{"acl": "public-read"},
+	})
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+	assert.Equal(t, "s3-no-public-acl", results[0].RuleName)
+}
+
+func TestNewEngine_InvalidRego(t *testing.T) {
+	_, err := NewEngine(writeRegoPolicyDir(t, map[string]string{"broken.rego": "package main\n\ndeny[msg] {"}))
+	assert.Error(t, err)
+}
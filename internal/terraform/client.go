@@ -87,8 +87,14 @@ type Configuration struct {
 
 // ConfigModule represents module configuration
 type ConfigModule struct {
-	Resources   []ConfigResource       `json:"resources,omitempty"`
-	ModuleCalls map[string]interface{} `json:"module_calls,omitempty"`
+	Resources   []ConfigResource      `json:"resources,omitempty"`
+	ModuleCalls map[string]ModuleCall `json:"module_calls,omitempty"`
+}
+
+// ModuleCall represents a module block and the configuration of the module
+type ModuleCall struct {
+	Source string        `json:"source,omitempty"`
+	Module *ConfigModule `json:"module,omitempty"`
 }
 
 // ConfigResource represents a resource in configuration
@@ -203,12 +209,17 @@ func (c *Client) ShowJSON(ctx context.Context, planFile string) (*PlanOutput, er
 		return nil, fmt.Errorf("terraform show failed: %w\nOutput: %s", err, output)
 	}
 
-	var plan PlanOutput
-	if err := json.Unmarshal([]byte(output), &plan); err != nil {
-		return nil, fmt.Errorf("failed to parse plan output: %w", err)
+	return ParsePlanJSON([]byte(output))
+}
+
+// ShowState runs terraform show -json on the current state
+func (c *Client) ShowState(ctx context.Context) (*StateOutput, error) {
+	output, err := c.runCommand(ctx, "show", "-json")
+	if err != nil {
+		return nil, fmt.Errorf("terraform show failed: %w\nOutput: %s", err, output)
 	}
 
-	return &plan, nil
+	return ParseStateJSON([]byte(output))
 }
 
 // Apply runs terraform apply
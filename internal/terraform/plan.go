@@ -0,0 +1,156 @@
+package terraform
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// ParsePlanJSON parses the output of terraform show -json for a plan
+func ParsePlanJSON(data []byte) (*PlanOutput, error) {
+	var plan PlanOutput
+	if err := json.Unmarshal(data, &plan); err != nil {
+		return nil, fmt.Errorf("failed to parse plan output: %w", err)
+	}
+
+	return &plan, nil
+}
+
+// LoadPlanFile reads a plan previously exported with terraform show -json.
+// No terraform binary is needed to load it.
+func LoadPlanFile(path string) (*PlanOutput, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read plan JSON file: %w", err)
+	}
+
+	plan, err := ParsePlanJSON(data)
+	if err != nil {
+		return nil, err
+	}
+
+	if plan.PlannedValues == nil {
+		return nil, fmt.Errorf("%s does not contain planned_values; export it with terraform show -json <planfile>", path)
+	}
+
+	return plan, nil
+}
+
+// ProviderConfig is a provider block from the configuration of a plan
+type ProviderConfig struct {
+	Name     string                 // provider type, e.g. aws
+	FullName string                 // source address, e.g. registry.terraform.io/hashicorp/aws
+	Alias    string                 // alias of the block, empty for the default configuration
+	Settings map[string]interface{} // arguments set to constant values, e.g. region; nested blocks are lists of such maps
+}
+
+// ProviderConfigs returns the provider blocks of the plan by provider config
+// key, e.g. "aws", "aws.west" or "module.vpc:aws"
+func (p *PlanOutput) ProviderConfigs() map[string]ProviderConfig {
+	configs := make(map[string]ProviderConfig)
+	if p.Configuration == nil {
+		return configs
+	}
+
+	for key, raw := range p.Configuration.ProviderConfig {
+		block, ok := raw.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		config := ProviderConfig{Settings: make(map[string]interface{})}
+		config.Name, _ = block["name"].(string)
+		config.FullName, _ = block["full_name"].(string)
+		config.Alias, _ = block["alias"].(string)
+
+		expressions, _ := block["expressions"].(map[string]interface{})
+		for name, expression := range expressions {
+			if value, ok := constantValue(expression); ok {
+				config.Settings[name] = value
+			}
+		}
+
+		configs[key] = config
+	}
+
+	return configs
+}
+
+// ResourceProviderKeys returns the provider config key of every configured
+// resource, including resources of module calls, by configuration address
+// (see ConfigAddress). Keys that don't name a provider block of the plan, like
+// those of providers inherited from the root module, are resolved to the
+// block they refer to or left out.
+func (p *PlanOutput) ResourceProviderKeys() map[string]string {
+	keys := make(map[string]string)
+	if p.Configuration == nil || p.Configuration.RootModule == nil {
+		return keys
+	}
+
+	configs := p.Configuration.ProviderConfig
+	var walk func(module *ConfigModule, prefix string)
+	walk = func(module *ConfigModule, prefix string) {
+		for _, resource := range module.Resources {
+			key := resource.ProviderName
+			if _, ok := configs[key]; !ok {
+				// "vpc:aws" refers to the aws block the module inherits
+				if i := strings.LastIndex(key, ":"); i >= 0 {
+					key = key[i+1:]
+				}
+			}
+			if _, ok := configs[key]; ok {
+				keys[prefix+resource.Address] = key
+			}
+		}
+
+		for name, call := range module.ModuleCalls {
+			if call.Module != nil {
+				walk(call.Module, prefix+"module."+name+".")
+			}
+		}
+	}
+	walk(p.Configuration.RootModule, "")
+
+	return keys
+}
+
+// instanceKeyPattern matches the count and for_each keys of an address
+var instanceKeyPattern = regexp.MustCompile(`\[[^\]]*\]`)
+
+// ConfigAddress strips the instance keys from a resource address, mapping
+// e.g. module.app[0].aws_instance.web["a"] to module.app.aws_instance.web
+func ConfigAddress(address string) string {
+	return instanceKeyPattern.ReplaceAllString(address, "")
+}
+
+// constantValue returns the value of an expression set to a constant. Nested
+// blocks, like the endpoints block of the aws provider, are rendered as lists
+// of expression maps and yield a list of their constant arguments.
+func constantValue(expression interface{}) (interface{}, bool) {
+	if blocks, ok := expression.([]interface{}); ok {
+		values := make([]interface{}, 0, len(blocks))
+		for _, block := range blocks {
+			expressions, ok := block.(map[string]interface{})
+			if !ok {
+				return nil, false
+			}
+			settings := make(map[string]interface{})
+			for name, expression := range expressions {
+				if value, ok := constantValue(expression); ok {
+					settings[name] = value
+				}
+			}
+			values = append(values, settings)
+		}
+		return values, true
+	}
+
+	fields, ok := expression.(map[string]interface{})
+	if !ok {
+		return nil, false
+	}
+	value, ok := fields["constant_value"]
+	return value, ok
+}
@@ -0,0 +1,144 @@
+package terraform
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const samplePlanJSON = `{
+  "format_version": "1.2",
+  "terraform_version": "1.6.0",
+  "planned_values": {
+    "root_module": {
+      "resources": [
+        {
+          "address": "aws_s3_bucket.logs",
+          "mode": "managed",
+          "type": "aws_s3_bucket",
+          "name": "logs",
+          "provider_name": "registry.terraform.io/hashicorp/aws",
+          "values": {"bucket": "logs", "tags": {"Owner": "platform"}}
+        }
+      ],
+      "child_modules": [
+        {
+          "address": "module.vpc",
+          "resources": [
+            {
+              "address": "module.vpc.aws_vpc.main",
+              "mode": "managed",
+              "type": "aws_vpc",
+              "name": "main",
+              "provider_name": "registry.terraform.io/hashicorp/aws",
+              "values": {"cidr_block": "10.0.0.0/16"}
+            }
+          ]
+        }
+      ]
+    }
+  },
+  "resource_changes": [
+    {
+      "address": "aws_s3_bucket.logs",
+      "mode": "managed",
+      "type": "aws_s3_bucket",
+      "name": "logs",
+      "provider_name": "registry.terraform.io/hashicorp/aws",
+      "change": {"actions": ["create"], "before": null, "after": {"bucket": "logs"}}
+    }
+  ]
+}`
+
+func TestLoadPlanFile(t *testing.T) {
+	planPath := filepath.Join(t.TempDir(), "plan.json")
+	require.NoError(t, os.WriteFile(planPath, []byte(samplePlanJSON), 0644))
+
+	plan, err := LoadPlanFile(planPath)
+	require.NoError(t, err)
+
+	require.NotNil(t, plan.PlannedValues)
+	require.NotNil(t, plan.PlannedValues.RootModule)
+	assert.Len(t, plan.PlannedValues.RootModule.Resources, 1)
+	assert.Equal(t, "aws_s3_bucket.logs", plan.PlannedValues.RootModule.Resources[0].Address)
+	require.Len(t, plan.PlannedValues.RootModule.ChildModules, 1)
+	assert.Equal(t, "aws_vpc", plan.PlannedValues.RootModule.ChildModules[0].Resources[0].Type)
+	require.Len(t, plan.ResourceChanges, 1)
+	assert.Equal(t, []string{"create"}, plan.ResourceChanges[0].Change.Actions)
+}
+
+func TestLoadPlanFile_Errors(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	_, err := LoadPlanFile(filepath.Join(tmpDir, "missing.json"))
+	assert.Error(t, err)
+
+	invalidPath := filepath.Join(tmpDir, "invalid.json")
+	require.NoError(t, os.WriteFile(invalidPath, []byte("not json"), 0644))
+	_, err = LoadPlanFile(invalidPath)
+	assert.Error(t, err)
+
+	noValuesPath := filepath.Join(tmpDir, "state.json")
+	require.NoError(t, os.WriteFile(noValuesPath, []byte(`{"format_version": "1.0", "values": {}}`), 0644))
+	_, err = LoadPlanFile(noValuesPath)
+	assert.Error(t, err)
+}
+
+const multiProviderPlanJSON = `{
+  "format_version": "1.2",
+  "planned_values": {"root_module": {}},
+  "configuration": {
+    "provider_config": {
+      "aws": {"name": "aws", "full_name": "registry.terraform.io/hashicorp/aws", "expressions": {"region": {"constant_value": "us-east-1"}}},
+      "aws.west": {"name": "aws", "full_name": "registry.terraform.io/hashicorp/aws", "alias": "west", "expressions": {"region": {"constant_value": "us-west-2"}, "endpoints": [{"s3": {"constant_value": "http://localhost:4566"}, "ec2": {"references": ["var.ec2_endpoint"]}}]}},
+      "google": {"name": "google", "full_name": "registry.terraform.io/hashicorp/google", "expressions": {"project": {"references": ["var.project"]}}}
+    },
+    "root_module": {
+      "resources": [
+        {"address": "aws_s3_bucket.logs", "mode": "managed", "type": "aws_s3_bucket", "name": "logs", "provider_config_key": "aws"},
+        {"address": "aws_s3_bucket.replica", "mode": "managed", "type": "aws_s3_bucket", "name": "replica", "provider_config_key": "aws.west"},
+        {"address": "google_storage_bucket.assets", "mode": "managed", "type": "google_storage_bucket", "name": "assets", "provider_config_key": "google"}
+      ],
+      "module_calls": {
+        "vpc": {
+          "source": "./vpc",
+          "module": {
+            "resources": [
+              {"address": "aws_vpc.main", "mode": "managed", "type": "aws_vpc", "name": "main", "provider_config_key": "vpc:aws"}
+            ]
+          }
+        }
+      }
+    }
+  }
+}`
+
+func TestPlanOutput_ProviderConfigs(t *testing.T) {
+	plan, err := ParsePlanJSON([]byte(multiProviderPlanJSON))
+	require.NoError(t, err)
+
+	configs := plan.ProviderConfigs()
+	require.Len(t, configs, 3)
+	assert.Equal(t, "west", configs["aws.west"].Alias)
+	assert.Equal(t, "us-west-2", configs["aws.west"].Settings["region"])
+	assert.Equal(t, []interface{}{map[string]interface{}{"s3": "http://localhost:4566"}}, configs["aws.west"].Settings["endpoints"])
+	assert.Equal(t, "registry.terraform.io/hashicorp/google", configs["google"].FullName)
+	assert.NotContains(t, configs["google"].Settings, "project")
+
+	keys := plan.ResourceProviderKeys()
+	assert.Equal(t, map[string]string{
+		"aws_s3_bucket.logs":           "aws",
+		"aws_s3_bucket.replica":        "aws.west",
+		"google_storage_bucket.assets": "google",
+		"module.vpc.aws_vpc.main":      "aws",
+	}, keys)
+}
+
+func TestConfigAddress(t *testing.T) {
+	assert.Equal(t, "aws_s3_bucket.logs", ConfigAddress("aws_s3_bucket.logs"))
+	assert.Equal(t, "aws_instance.web", ConfigAddress("aws_instance.web[0]"))
+	assert.Equal(t, "module.app.aws_instance.web", ConfigAddress(`module.app["a"].aws_instance.web[2]`))
+}
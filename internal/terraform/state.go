@@ -0,0 +1,209 @@
+package terraform
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// StateOutput represents the parsed output of terraform show -json for a state
+type StateOutput struct {
+	FormatVersion    string       `json:"format_version"`
+	TerraformVersion string       `json:"terraform_version"`
+	Values           *StateValues `json:"values,omitempty"`
+}
+
+// rawState represents a terraform.tfstate file (state format version 4)
+type rawState struct {
+	Version          int                `json:"version"`
+	TerraformVersion string             `json:"terraform_version"`
+	Resources        []rawStateResource `json:"resources"`
+}
+
+// rawStateResource represents a resource entry in a terraform.tfstate file
+type rawStateResource struct {
+	Module    string             `json:"module,omitempty"`
+	Mode      string             `json:"mode"`
+	Type      string             `json:"type"`
+	Name      string             `json:"name"`
+	Provider  string             `json:"provider"`
+	Instances []rawStateInstance `json:"instances"`
+}
+
+// rawStateInstance represents a single instance of a resource in a terraform.tfstate file
+type rawStateInstance struct {
+	IndexKey      interface{}            `json:"index_key,omitempty"`
+	SchemaVersion int                    `json:"schema_version"`
+	Attributes    map[string]interface{} `json:"attributes"`
+}
+
+// ParseStateJSON parses either the output of terraform show -json for a state
+// or a raw terraform.tfstate file (version 4) into the same resource model
+// used for plans.
+func ParseStateJSON(data []byte) (*StateOutput, error) {
+	var probe struct {
+		Version       *int            `json:"version"`
+		FormatVersion string          `json:"format_version"`
+		PlannedValues json.RawMessage `json:"planned_values"`
+	}
+	if err := json.Unmarshal(data, &probe); err != nil {
+		return nil, fmt.Errorf("failed to parse state: %w", err)
+	}
+
+	if probe.FormatVersion != "" {
+		if probe.PlannedValues != nil {
+			return nil, fmt.Errorf("file contains a plan, not a state; use validate --plan-json instead")
+		}
+
+		var state StateOutput
+		if err := json.Unmarshal(data, &state); err != nil {
+			return nil, fmt.Errorf("failed to parse state output: %w", err)
+		}
+		if state.Values == nil {
+			// An empty state is exported without values
+			state.Values = &StateValues{RootModule: &Module{}}
+		}
+		return &state, nil
+	}
+
+	if probe.Version == nil {
+		return nil, fmt.Errorf("unrecognized state format: expected terraform show -json output or a terraform.tfstate file")
+	}
+	if *probe.Version != 4 {
+		return nil, fmt.Errorf("unsupported state file version %d (only version 4 is supported)", *probe.Version)
+	}
+
+	var raw rawState
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("failed to parse state file: %w", err)
+	}
+
+	return &StateOutput{
+		TerraformVersion: raw.TerraformVersion,
+		Values:           &StateValues{RootModule: raw.rootModule()},
+	}, nil
+}
+
+// LoadStateFile reads a state exported with terraform show -json or a raw
+// terraform.tfstate file. No terraform binary is needed to load it.
+func LoadStateFile(path string) (*StateOutput, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read state file: %w", err)
+	}
+
+	return ParseStateJSON(data)
+}
+
+// rootModule converts the flat resource list of a raw state into the nested
+// module tree produced by terraform show -json
+func (s *rawState) rootModule() *Module {
+	resources := make(map[string][]Resource)
+	children := make(map[string][]string)
+	seen := map[string]bool{"": true}
+
+	var register func(address string)
+	register = func(address string) {
+		if seen[address] {
+			return
+		}
+		seen[address] = true
+		parent := parentModuleAddress(address)
+		register(parent)
+		children[parent] = append(children[parent], address)
+	}
+
+	for _, res := range s.Resources {
+		register(res.Module)
+		for _, instance := range res.Instances {
+			resources[res.Module] = append(resources[res.Module], Resource{
+				Address:       res.instanceAddress(instance.IndexKey),
+				Mode:          res.Mode,
+				Type:          res.Type,
+				Name:          res.Name,
+				ProviderName:  providerName(res.Provider),
+				SchemaVersion: instance.SchemaVersion,
+				Values:        instance.Attributes,
+			})
+		}
+	}
+
+	var build func(address string) Module
+	build = func(address string) Module {
+		module := Module{Address: address, Resources: resources[address]}
+		for _, child := range children[address] {
+			module.ChildModules = append(module.ChildModules, build(child))
+		}
+		return module
+	}
+
+	root := build("")
+	return &root
+}
+
+// instanceAddress builds the absolute address of a resource instance, e.g.
+// module.vpc.aws_subnet.private[0]
+func (r *rawStateResource) instanceAddress(indexKey interface{}) string {
+	var sb strings.Builder
+	if r.Module != "" {
+		sb.WriteString(r.Module)
+		sb.WriteString(".")
+	}
+	if r.Mode == "data" {
+		sb.WriteString("data.")
+	}
+	sb.WriteString(r.Type)
+	sb.WriteString(".")
+	sb.WriteString(r.Name)
+
+	switch key := indexKey.(type) {
+	case nil:
+	case string:
+		sb.WriteString(fmt.Sprintf("[%q]", key))
+	case float64:
+		sb.WriteString(fmt.Sprintf("[%d]", int(key)))
+	default:
+		sb.WriteString(fmt.Sprintf("[%v]", key))
+	}
+
+	return sb.String()
+}
+
+// parentModuleAddress returns the address of the module containing the given
+// module, e.g. module.a for module.a.module.b["x"]
+func parentModuleAddress(address string) string {
+	depth := 0
+	inQuotes := false
+	last := -1
+
+	for i := 0; i < len(address); i++ {
+		switch c := address[i]; {
+		case c == '"':
+			inQuotes = !inQuotes
+		case inQuotes:
+		case c == '[':
+			depth++
+		case c == ']':
+			depth--
+		case depth == 0 && strings.HasPrefix(address[i:], ".module."):
+			last = i
+		}
+	}
+
+	if last < 0 {
+		return ""
+	}
+	return address[:last]
+}
+
+// providerName extracts the provider source address from a state provider
+// reference such as provider["registry.terraform.io/hashicorp/aws"].east
+func providerName(provider string) string {
+	start := strings.Index(provider, `["`)
+	end := strings.Index(provider, `"]`)
+	if start < 0 || end < start {
+		return provider
+	}
+	return provider[start+2 : end]
+}
@@ -0,0 +1,132 @@
+package terraform
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const sampleShowStateJSON = `{
+  "format_version": "1.0",
+  "terraform_version": "1.6.0",
+  "values": {
+    "root_module": {
+      "resources": [
+        {
+          "address": "aws_s3_bucket.logs",
+          "mode": "managed",
+          "type": "aws_s3_bucket",
+          "name": "logs",
+          "provider_name": "registry.terraform.io/hashicorp/aws",
+          "values": {"id": "logs", "bucket": "logs"}
+        }
+      ]
+    }
+  }
+}`
+
+const sampleRawStateJSON = `{
+  "version": 4,
+  "terraform_version": "1.6.0",
+  "serial": 3,
+  "lineage": "5d1c6b2a",
+  "outputs": {},
+  "resources": [
+    {
+      "mode": "managed",
+      "type": "aws_s3_bucket",
+      "name": "logs",
+      "provider": "provider[\"registry.terraform.io/hashicorp/aws\"]",
+      "instances": [
+        {"schema_version": 0, "attributes": {"id": "logs", "bucket": "logs"}}
+      ]
+    },
+    {
+      "module": "module.vpc",
+      "mode": "managed",
+      "type": "aws_subnet",
+      "name": "private",
+      "provider": "provider[\"registry.terraform.io/hashicorp/aws\"].east",
+      "instances": [
+        {"index_key": 0, "schema_version": 1, "attributes": {"id": "subnet-0"}},
+        {"index_key": 1, "schema_version": 1, "attributes": {"id": "subnet-1"}}
+      ]
+    },
+    {
+      "module": "module.vpc.module.endpoints[\"s3\"]",
+      "mode": "data",
+      "type": "aws_region",
+      "name": "current",
+      "provider": "provider[\"registry.terraform.io/hashicorp/aws\"]",
+      "instances": [
+        {"schema_version": 0, "attributes": {"name": "us-east-1"}}
+      ]
+    }
+  ]
+}`
+
+func TestParseStateJSON_ShowOutput(t *testing.T) {
+	state, err := ParseStateJSON([]byte(sampleShowStateJSON))
+	require.NoError(t, err)
+
+	require.NotNil(t, state.Values)
+	require.NotNil(t, state.Values.RootModule)
+	require.Len(t, state.Values.RootModule.Resources, 1)
+	assert.Equal(t, "aws_s3_bucket.logs", state.Values.RootModule.Resources[0].Address)
+	assert.Equal(t, "logs", state.Values.RootModule.Resources[0].Values["id"])
+}
+
+func TestParseStateJSON_RawState(t *testing.T) {
+	state, err := ParseStateJSON([]byte(sampleRawStateJSON))
+	require.NoError(t, err)
+	assert.Equal(t, "1.6.0", state.TerraformVersion)
+
+	root := state.Values.RootModule
+	require.Len(t, root.Resources, 1)
+	assert.Equal(t, "aws_s3_bucket.logs", root.Resources[0].Address)
+	assert.Equal(t, "registry.terraform.io/hashicorp/aws", root.Resources[0].ProviderName)
+
+	require.Len(t, root.ChildModules, 1)
+	vpc := root.ChildModules[0]
+	assert.Equal(t, "module.vpc", vpc.Address)
+	require.Len(t, vpc.Resources, 2)
+	assert.Equal(t, "module.vpc.aws_subnet.private[0]", vpc.Resources[0].Address)
+	assert.Equal(t, "module.vpc.aws_subnet.private[1]", vpc.Resources[1].Address)
+	assert.Equal(t, "registry.terraform.io/hashicorp/aws", vpc.Resources[1].ProviderName)
+	assert.Equal(t, 1, vpc.Resources[1].SchemaVersion)
+
+	require.Len(t, vpc.ChildModules, 1)
+	endpoints := vpc.ChildModules[0]
+	assert.Equal(t, `module.vpc.module.endpoints["s3"]`, endpoints.Address)
+	require.Len(t, endpoints.Resources, 1)
+	assert.Equal(t, `module.vpc.module.endpoints["s3"].data.aws_region.current`, endpoints.Resources[0].Address)
+}
+
+func TestParseStateJSON_Errors(t *testing.T) {
+	_, err := ParseStateJSON([]byte("not json"))
+	assert.Error(t, err)
+
+	_, err = ParseStateJSON([]byte(`{"version": 3, "modules": []}`))
+	assert.Error(t, err)
+
+	_, err = ParseStateJSON([]byte(samplePlanJSON))
+	assert.Error(t, err)
+
+	_, err = ParseStateJSON([]byte(`{"foo": "bar"}`))
+	assert.Error(t, err)
+}
+
+func TestLoadStateFile(t *testing.T) {
+	statePath := filepath.Join(t.TempDir(), "terraform.tfstate")
+	require.NoError(t, os.WriteFile(statePath, []byte(sampleRawStateJSON), 0644))
+
+	state, err := LoadStateFile(statePath)
+	require.NoError(t, err)
+	assert.Len(t, state.Values.RootModule.Resources, 1)
+
+	_, err = LoadStateFile(filepath.Join(t.TempDir(), "missing.tfstate"))
+	assert.Error(t, err)
+}
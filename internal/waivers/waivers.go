@@ -0,0 +1,362 @@
+// Package waivers suppresses known policy findings for specific resources and
+// rules until an optional expiry date.
+package waivers
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/vijayaxai/terraship/internal/cloud"
+	"gopkg.in/yaml.v3"
+)
+
+// dateLayout is the format of waiver expiry dates
+const dateLayout = "2006-01-02"
+
+// Waiver suppresses the findings of a rule on matching resources. Rule and
+// Resource accept * wildcards; an empty Resource matches every resource.
+type Waiver struct {
+	Rule     string `yaml:"rule"`
+	Resource string `yaml:"resource"`
+	Reason   string `yaml:"reason"`
+	Expires  string `yaml:"expires"` // YYYY-MM-DD; the waiver no longer applies from this date
+	Source   string `yaml:"-"`       // file (and line) the waiver was declared in
+
+	expires time.Time
+}
+
+// waiverFile is the layout of a waivers YAML file
+type waiverFile struct {
+	Waivers []Waiver `yaml:"waivers"`
+}
+
+// Set is a collection of waivers applied to validation results
+type Set struct {
+	waivers []Waiver
+	now     func() time.Time
+}
+
+// NewSet creates a set from already parsed waivers
+func NewSet(waivers []Waiver) (*Set, error) {
+	set := &Set{now: time.Now}
+	for _, waiver := range waivers {
+		if err := waiver.init(); err != nil {
+			return nil, err
+		}
+		set.waivers = append(set.waivers, waiver)
+	}
+	return set, nil
+}
+
+// Load builds a set from an optional waivers file and the inline ignore
+// comments in the .tf files of dir and its local modules. Either argument may
+// be empty.
+func Load(path, dir string) (*Set, error) {
+	var waivers []Waiver
+
+	if path != "" {
+		fileWaivers, err := LoadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		waivers = append(waivers, fileWaivers...)
+	}
+
+	if dir != "" {
+		inline, err := ScanDir(dir)
+		if err != nil {
+			return nil, err
+		}
+		waivers = append(waivers, inline...)
+	}
+
+	return NewSet(waivers)
+}
+
+// LoadFile reads waivers from a YAML file
+func LoadFile(path string) ([]Waiver, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read waivers file: %w", err)
+	}
+
+	var file waiverFile
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse waivers file: %w", err)
+	}
+
+	for i := range file.Waivers {
+		file.Waivers[i].Source = path
+		if file.Waivers[i].Rule == "" {
+			return nil, fmt.Errorf("waiver %d in %s has no rule", i+1, path)
+		}
+	}
+
+	return file.Waivers, nil
+}
+
+// init validates the waiver and parses its expiry date
+func (w *Waiver) init() error {
+	if w.Expires == "" {
+		return nil
+	}
+
+	expires, err := time.Parse(dateLayout, w.Expires)
+	if err != nil {
+		return fmt.Errorf("invalid expiry date %q for waiver of %s in %s: expected YYYY-MM-DD", w.Expires, w.Rule, w.Source)
+	}
+	w.expires = expires
+	return nil
+}
+
+// matches reports whether the waiver covers a rule on a resource address.
+// A resource pattern without an index also covers count/for_each instances,
+// of the resource and of the modules it is in.
+func (w *Waiver) matches(rule, address string) bool {
+	if !globMatch(w.Rule, rule) {
+		return false
+	}
+	if w.Resource == "" {
+		return true
+	}
+	if globMatch(w.Resource, address) {
+		return true
+	}
+	if strings.Contains(address, "[") && !strings.Contains(w.Resource, "[") {
+		return globMatch(w.Resource, indexPattern.ReplaceAllString(address, ""))
+	}
+	return false
+}
+
+// expired reports whether the waiver has passed its expiry date
+func (w *Waiver) expired(now time.Time) bool {
+	return !w.expires.IsZero() && !now.Before(w.expires)
+}
+
+// Apply marks a failed result on the resource at address as waived when a
+// waiver matches it. A result covered only by expired waivers stays failed and
+// gets a detail noting the expiry.
+func (s *Set) Apply(address string, result *cloud.ValidationResult) {
+	if s == nil || result.Passed || result.Waived {
+		return
+	}
+	now := s.now()
+
+	var expired *Waiver
+	for i := range s.waivers {
+		waiver := &s.waivers[i]
+		if !waiver.matches(result.RuleName, address) {
+			continue
+		}
+		if waiver.expired(now) {
+			expired = waiver
+			continue
+		}
+
+		result.Waived = true
+		result.WaiverReason = waiver.Reason
+		result.WaiverExpires = waiver.Expires
+		return
+	}
+
+	if expired != nil {
+		result.Details = append(result.Details, fmt.Sprintf("Waiver expired on %s (%s)", expired.Expires, expired.Source))
+	}
+}
+
+// globMatch matches value against a pattern where * matches any characters
+func globMatch(pattern, value string) bool {
+	if !strings.Contains(pattern, "*") {
+		return pattern == value
+	}
+	expr := "^" + strings.ReplaceAll(regexp.QuoteMeta(pattern), `\*`, ".*") + "$"
+	matched, _ := regexp.MatchString(expr, value)
+	return matched
+}
+
+var (
+	// ignorePattern matches "# terraship:ignore rule-name key=value ..." (or //)
+	ignorePattern = regexp.MustCompile(`^\s*(?:#|//)\s*terraship:ignore\s+(\S+)(.*)$`)
+	// resourcePattern matches the opening line of a resource block
+	resourcePattern = regexp.MustCompile(`^\s*resource\s+"([^"]+)"\s+"([^"]+)"`)
+	// modulePattern matches the opening line of a module block
+	modulePattern = regexp.MustCompile(`^\s*module\s+"([^"]+)"`)
+	// sourcePattern matches the source argument of a module block
+	sourcePattern = regexp.MustCompile(`^\s*source\s*=\s*"([^"]+)"`)
+	// indexPattern matches the count/for_each index of an address segment
+	indexPattern = regexp.MustCompile(`\[[^\]]*\]`)
+	// optionPattern finds the start of each key= option of an ignore comment
+	optionPattern = regexp.MustCompile(`\b(reason|expires)=`)
+)
+
+// ScanDir collects inline ignore comments from the .tf files in dir and in the
+// local modules it calls. A comment applies to the resource block it is placed
+// in, or to the next resource block when it precedes one. Resources of a module
+// are addressed through its calls, e.g. module.app.aws_s3_bucket.logs; modules
+// from a registry or other remote source are not scanned.
+func ScanDir(dir string) ([]Waiver, error) {
+	return scanModule(dir, "", nil)
+}
+
+// moduleCall is a module block with a local source
+type moduleCall struct {
+	name   string
+	source string
+}
+
+// scanModule collects the ignore comments of the module in dir, whose
+// resource addresses start with prefix, and of the local modules it calls.
+// parents holds the directories of the calling modules to stop at cycles.
+func scanModule(dir, prefix string, parents []string) ([]Waiver, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.tf"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list terraform files: %w", err)
+	}
+
+	var (
+		waivers []Waiver
+		calls   []moduleCall
+	)
+	for _, file := range files {
+		fileWaivers, fileCalls, err := scanFile(file, prefix)
+		if err != nil {
+			return nil, err
+		}
+		waivers = append(waivers, fileWaivers...)
+		calls = append(calls, fileCalls...)
+	}
+
+	parents = append(parents, filepath.Clean(dir))
+	for _, call := range calls {
+		moduleDir := filepath.Clean(filepath.Join(dir, call.source))
+		if contains(parents, moduleDir) {
+			continue
+		}
+		moduleWaivers, err := scanModule(moduleDir, prefix+"module."+call.name+".", parents)
+		if err != nil {
+			return nil, err
+		}
+		waivers = append(waivers, moduleWaivers...)
+	}
+
+	return waivers, nil
+}
+
+// scanFile parses the ignore comments and the local module calls in a single
+// .tf file, prefixing the resource addresses with prefix
+func scanFile(path, prefix string) ([]Waiver, []moduleCall, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read terraform file: %w", err)
+	}
+	defer f.Close()
+
+	var (
+		waivers []Waiver
+		calls   []moduleCall
+		pending []Waiver // comments waiting for the next resource block
+		current string   // address of the enclosing resource block
+		module  string   // name of the enclosing module block
+		depth   int
+		line    int
+	)
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line++
+		text := scanner.Text()
+
+		if match := ignorePattern.FindStringSubmatch(text); match != nil {
+			waiver := parseOptions(match[2])
+			waiver.Rule = match[1]
+			waiver.Source = fmt.Sprintf("%s:%d", path, line)
+
+			if current != "" {
+				waiver.Resource = current
+				waivers = append(waivers, waiver)
+			} else {
+				pending = append(pending, waiver)
+			}
+			continue
+		}
+
+		if depth == 1 && module != "" {
+			if match := sourcePattern.FindStringSubmatch(text); match != nil && isLocalSource(match[1]) {
+				calls = append(calls, moduleCall{name: module, source: match[1]})
+			}
+		}
+
+		if depth == 0 {
+			if match := modulePattern.FindStringSubmatch(text); match != nil {
+				module = match[1]
+			}
+			if match := resourcePattern.FindStringSubmatch(text); match != nil {
+				current = prefix + match[1] + "." + match[2]
+				for _, waiver := range pending {
+					waiver.Resource = current
+					waivers = append(waivers, waiver)
+				}
+				pending = nil
+			} else if trimmed := strings.TrimSpace(text); trimmed != "" && !strings.HasPrefix(trimmed, "#") && !strings.HasPrefix(trimmed, "//") {
+				// Ignore comments only attach to an immediately following resource
+				pending = nil
+			}
+		}
+
+		depth += strings.Count(text, "{") - strings.Count(text, "}")
+		if depth <= 0 {
+			depth = 0
+			current = ""
+			module = ""
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, nil, fmt.Errorf("failed to read terraform file: %w", err)
+	}
+
+	return waivers, calls, nil
+}
+
+// isLocalSource reports whether a module source is a path on disk
+func isLocalSource(source string) bool {
+	return strings.HasPrefix(source, "./") || strings.HasPrefix(source, "../")
+}
+
+// contains reports whether dirs includes dir
+func contains(dirs []string, dir string) bool {
+	for _, d := range dirs {
+		if d == dir {
+			return true
+		}
+	}
+	return false
+}
+
+// parseOptions parses the reason= and expires= options of an ignore comment.
+// A value runs until the next option, so unquoted reasons may contain spaces.
+func parseOptions(text string) Waiver {
+	var waiver Waiver
+
+	locations := optionPattern.FindAllStringSubmatchIndex(text, -1)
+	for i, loc := range locations {
+		end := len(text)
+		if i+1 < len(locations) {
+			end = locations[i+1][0]
+		}
+
+		value := strings.Trim(strings.TrimSpace(text[loc[1]:end]), `"'`)
+		switch text[loc[2]:loc[3]] {
+		case "reason":
+			waiver.Reason = value
+		case "expires":
+			waiver.Expires = value
+		}
+	}
+
+	return waiver
+}
@@ -0,0 +1,189 @@
+package waivers
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vijayaxai/terraship/internal/cloud"
+)
+
+const mainTF = `# terraship:ignore enable-versioning reason="logs are write-once" expires=2027-01-01
+resource "aws_s3_bucket" "logs" {
+  bucket = "logs"
+}
+
+resource "aws_s3_bucket" "data" {
+  # terraship:ignore required-tags reason=owned by the data platform team
+  bucket = "data"
+  tags = {
+    Team = "data"
+  }
+}
+
+# terraship:ignore encryption-at-rest
+locals {
+  name = "unused"
+}
+
+resource "aws_instance" "web" {
+  // terraship:ignore iam-* expires=2020-01-01
+  ami = "ami-123"
+}
+`
+
+func fixedSet(t *testing.T, waivers []Waiver, now string) *Set {
+	t.Helper()
+
+	set, err := NewSet(waivers)
+	require.NoError(t, err)
+	at, err := time.Parse(dateLayout, now)
+	require.NoError(t, err)
+	set.now = func() time.Time { return at }
+	return set
+}
+
+func failed(rule string) cloud.ValidationResult {
+	return cloud.ValidationResult{RuleName: rule, Severity: "error", Passed: false}
+}
+
+func TestScanDir(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "main.tf"), []byte(mainTF), 0644))
+
+	waivers, err := ScanDir(dir)
+	require.NoError(t, err)
+	require.Len(t, waivers, 3)
+
+	assert.Equal(t, "enable-versioning", waivers[0].Rule)
+	assert.Equal(t, "aws_s3_bucket.logs", waivers[0].Resource)
+	assert.Equal(t, "logs are write-once", waivers[0].Reason)
+	assert.Equal(t, "2027-01-01", waivers[0].Expires)
+	assert.Contains(t, waivers[0].Source, "main.tf:1")
+
+	assert.Equal(t, "required-tags", waivers[1].Rule)
+	assert.Equal(t, "aws_s3_bucket.data", waivers[1].Resource)
+	assert.Equal(t, "owned by the data platform team", waivers[1].Reason)
+	assert.Empty(t, waivers[1].Expires)
+
+	// The comment above the locals block is not attached to any resource
+	assert.Equal(t, "iam-*", waivers[2].Rule)
+	assert.Equal(t, "aws_instance.web", waivers[2].Resource)
+}
+
+func TestScanDir_Modules(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "modules", "app", "bucket"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "main.tf"), []byte(`module "app" {
+  source = "./modules/app"
+}
+
+module "vpc" {
+  source = "terraform-aws-modules/vpc/aws"
+}
+`), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "modules", "app", "main.tf"), []byte(`module "assets" {
+  count  = 2
+  source = "./bucket"
+}
+
+# terraship:ignore iam-* reason=app role
+resource "aws_iam_role" "app" {
+  name = "app"
+}
+`), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "modules", "app", "bucket", "main.tf"), []byte(`resource "aws_s3_bucket" "this" {
+  # terraship:ignore enable-versioning
+  bucket = "assets"
+}
+`), 0644))
+
+	waivers, err := ScanDir(dir)
+	require.NoError(t, err)
+	require.Len(t, waivers, 2)
+
+	assert.Equal(t, "module.app.aws_iam_role.app", waivers[0].Resource)
+	assert.Equal(t, "module.app.module.assets.aws_s3_bucket.this", waivers[1].Resource)
+	assert.Contains(t, waivers[1].Source, filepath.Join("bucket", "main.tf")+":2")
+
+	// The waiver covers every instance of the module calls
+	set, err := NewSet(waivers)
+	require.NoError(t, err)
+	result := failed("enable-versioning")
+	set.Apply("module.app.module.assets[1].aws_s3_bucket.this", &result)
+	assert.True(t, result.Waived)
+}
+
+func TestSet_Apply(t *testing.T) {
+	set := fixedSet(t, []Waiver{
+		{Rule: "enable-versioning", Resource: "aws_s3_bucket.logs", Reason: "write-once", Expires: "2027-01-01", Source: "waivers.yml"},
+		{Rule: "required-tags", Resource: "module.app.*", Reason: "legacy module"},
+		{Rule: "iam-*", Resource: "aws_instance.web", Expires: "2026-01-01", Source: "main.tf:20"},
+	}, "2026-10-16")
+
+	result := failed("enable-versioning")
+	set.Apply("aws_s3_bucket.logs[0]", &result)
+	assert.True(t, result.Waived)
+	assert.Equal(t, "write-once", result.WaiverReason)
+	assert.Equal(t, "2027-01-01", result.WaiverExpires)
+
+	result = failed("required-tags")
+	set.Apply("module.app.aws_s3_bucket.assets", &result)
+	assert.True(t, result.Waived)
+
+	result = failed("required-tags")
+	set.Apply("aws_s3_bucket.assets", &result)
+	assert.False(t, result.Waived)
+	assert.Empty(t, result.Details)
+
+	// Expired waivers turn back into failures
+	result = failed("iam-least-privilege")
+	set.Apply("aws_instance.web", &result)
+	assert.False(t, result.Waived)
+	assert.Equal(t, []string{"Waiver expired on 2026-01-01 (main.tf:20)"}, result.Details)
+
+	// Passing results are left alone
+	result = cloud.ValidationResult{RuleName: "enable-versioning", Passed: true}
+	set.Apply("aws_s3_bucket.logs", &result)
+	assert.False(t, result.Waived)
+
+	// The waiver stops applying on its expiry date
+	set = fixedSet(t, []Waiver{{Rule: "enable-versioning", Expires: "2027-01-01"}}, "2027-01-01")
+	result = failed("enable-versioning")
+	set.Apply("aws_s3_bucket.logs", &result)
+	assert.False(t, result.Waived)
+
+	// A nil set applies nothing
+	var empty *Set
+	result = failed("enable-versioning")
+	empty.Apply("aws_s3_bucket.logs", &result)
+	assert.False(t, result.Waived)
+}
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "waivers.yml")
+	require.NoError(t, os.WriteFile(path, []byte(`waivers:
+  - rule: required-tags
+    resource: aws_s3_bucket.*
+    reason: tagged by the account baseline
+    expires: 2099-12-31
+`), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "main.tf"), []byte(mainTF), 0644))
+
+	set, err := Load(path, dir)
+	require.NoError(t, err)
+	assert.Len(t, set.waivers, 4)
+	assert.Equal(t, path, set.waivers[0].Source)
+
+	require.NoError(t, os.WriteFile(path, []byte("waivers:\n  - rule: x\n    expires: next year\n"), 0644))
+	_, err = Load(path, "")
+	assert.ErrorContains(t, err, "invalid expiry date")
+
+	require.NoError(t, os.WriteFile(path, []byte("waivers:\n  - resource: aws_s3_bucket.logs\n"), 0644))
+	_, err = Load(path, "")
+	assert.ErrorContains(t, err, "has no rule")
+}